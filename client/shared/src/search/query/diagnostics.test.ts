@@ -344,7 +344,7 @@ describe('getDiagnostics()', () => {
                 [
                   {
                     "severity": 8,
-                    "message": "Error: Structural search syntax only applies to searching file contents and is not compatible with \`type:\`. Remove this filter or switch to a different search type.",
+                    "message": "Error: Structural search syntax only applies to searching file contents and diffs, and is not compatible with other \`type:\` values. Remove this filter or switch to a different search type.",
                     "startLineNumber": 1,
                     "endLineNumber": 1,
                     "startColumn": 1,
@@ -357,7 +357,7 @@ describe('getDiagnostics()', () => {
                 [
                   {
                     "severity": 8,
-                    "message": "Error: Structural search syntax only applies to searching file contents and is not compatible with \`type:\`. Remove this filter or switch to a different search type.",
+                    "message": "Error: Structural search syntax only applies to searching file contents and diffs, and is not compatible with other \`type:\` values. Remove this filter or switch to a different search type.",
                     "startLineNumber": 1,
                     "endLineNumber": 1,
                     "startColumn": 1,
@@ -367,6 +367,12 @@ describe('getDiagnostics()', () => {
             `)
         })
 
+        test('accepts type:diff filter in structural search', () => {
+            expect(
+                parseAndDiagnose('type:diff defer :[x].Close()', SearchPatternType.structural)
+            ).toMatchInlineSnapshot('[]')
+        })
+
         test('accepts type: filter in non-structure search', () => {
             expect(parseAndDiagnose('type:symbol test', SearchPatternType.regexp)).toMatchInlineSnapshot('[]')
             expect(parseAndDiagnose('type:symbol test', SearchPatternType.literal)).toMatchInlineSnapshot('[]')
@@ -163,8 +163,9 @@ const rules: PatternOf<Token[], PatternData>[] = [
         ),
         some({
             field: { value: 'type' },
+            value: { value: not('diff') },
             $data: addFilterDiagnostic(
-                'Error: Structural search syntax only applies to searching file contents and is not compatible with `type:`. Remove this filter or switch to a different search type.'
+                'Error: Structural search syntax only applies to searching file contents and diffs, and is not compatible with other `type:` values. Remove this filter or switch to a different search type.'
             ),
         })
     ),
@@ -10,6 +10,7 @@ import { Filter, Literal } from './token'
 export enum FilterType {
     after = 'after',
     archived = 'archived',
+    at = 'at',
     author = 'author',
     before = 'before',
     case = 'case',
@@ -17,6 +18,7 @@ export enum FilterType {
     content = 'content',
     context = 'context',
     count = 'count',
+    explain = 'explain',
     file = 'file',
     fork = 'fork',
     lang = 'lang',
@@ -184,6 +186,10 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: 'Include results from archived repositories.',
         singular: true,
     },
+    [FilterType.at]: {
+        description: 'Search the code as it was at a certain date.',
+        singular: true,
+    },
     [FilterType.author]: {
         negatable: true,
         description: negated => `${negated ? 'Exclude' : 'Include only'} commits or diffs authored by a user.`,
@@ -218,6 +224,12 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: 'Number of results to fetch (integer) or "all"',
         singular: true,
     },
+    [FilterType.explain]: {
+        description: 'Explain how the search is evaluated: its plan and the jobs that ran for it.',
+        discreteValues: () => ['yes', 'no'].map(value => ({ label: value })),
+        default: 'no',
+        singular: true,
+    },
     [FilterType.file]: {
         alias: 'f',
         negatable: true,
@@ -243,8 +255,8 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
             `${negated ? 'Exclude' : 'Include only'} Commits with messages matching a certain string`,
     },
     [FilterType.patterntype]: {
-        discreteValues: () => ['regexp', 'literal', 'structural'].map(value => ({ label: value })),
-        description: 'The pattern type (regexp, literal, structural) in use',
+        discreteValues: () => ['regexp', 'literal', 'structural', 'fuzzy'].map(value => ({ label: value })),
+        description: 'The pattern type (regexp, literal, structural, fuzzy) in use',
         singular: true,
     },
     [FilterType.repo]: {
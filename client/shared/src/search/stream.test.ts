@@ -0,0 +1,50 @@
+import { of } from 'rxjs'
+import { last } from 'rxjs/operators'
+
+import { mergeMatches, OwnerMatch, RepositoryMatch, SearchEvent, switchAggregateSearchResults } from './stream'
+
+const owner = (handle: string, fileCount: number, repository = 'github.com/sourcegraph/sourcegraph'): OwnerMatch => ({
+    type: 'owner',
+    handle,
+    fileCount,
+    repository,
+    commit: 'deadbeef',
+})
+
+const repo: RepositoryMatch = { type: 'repo', repository: 'github.com/sourcegraph/about' }
+
+describe('mergeMatches()', () => {
+    test('appends matches', () => {
+        expect(mergeMatches([repo], [owner('@team', 1)])).toEqual([repo, owner('@team', 1)])
+    })
+
+    test('replaces earlier owner matches with the same key in place', () => {
+        expect(mergeMatches([owner('@team', 1), repo, owner('@docs', 1)], [owner('@team', 3)])).toEqual([
+            owner('@team', 3),
+            repo,
+            owner('@docs', 1),
+        ])
+    })
+
+    test('compares handles case-insensitively', () => {
+        expect(mergeMatches([owner('@team', 1)], [owner('@Team', 2)])).toEqual([owner('@Team', 2)])
+    })
+
+    test('keeps owners of different repositories', () => {
+        expect(mergeMatches([owner('@team', 1)], [owner('@team', 2, 'github.com/sourcegraph/about')])).toEqual([
+            owner('@team', 1),
+            owner('@team', 2, 'github.com/sourcegraph/about'),
+        ])
+    })
+})
+
+describe('switchAggregateSearchResults', () => {
+    test('replaces owner matches updated by later events', async () => {
+        const events: SearchEvent[] = [
+            { type: 'matches', data: [owner('@team', 1), owner('@docs', 1)] },
+            { type: 'matches', data: [owner('@team', 2)] },
+        ]
+        const results = await of(...events).pipe(switchAggregateSearchResults, last()).toPromise()
+        expect(results.results).toEqual([owner('@team', 2), owner('@docs', 1)])
+    })
+})
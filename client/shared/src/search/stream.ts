@@ -17,7 +17,7 @@ export type SearchEvent =
     | { type: 'error'; data: ErrorLike }
     | { type: 'done'; data: {} }
 
-export type SearchMatch = ContentMatch | RepositoryMatch | CommitMatch | SymbolMatch | PathMatch
+export type SearchMatch = ContentMatch | RepositoryMatch | CommitMatch | SymbolMatch | PathMatch | OwnerMatch
 
 export interface PathMatch {
     type: 'path'
@@ -122,6 +122,24 @@ export interface RepositoryMatch {
     branches?: string[]
 }
 
+/**
+ * An owner of matched files in a repository, as declared in its CODEOWNERS
+ * file. An owner is sent again with an updated fileCount when more of its
+ * files match, which replaces the earlier match with the same key.
+ *
+ * @see getOwnerMatchKey
+ */
+export interface OwnerMatch {
+    type: 'owner'
+    handle: string
+    fileCount: number
+    repository: string
+    repoStars?: number
+    repoLastFetched?: string
+    branches?: string[]
+    commit?: string
+}
+
 /**
  * An aggregate type representing a progress update.
  * Should be replaced when a new ones come in.
@@ -151,6 +169,31 @@ export interface Progress {
 
     // The URL of the trace for this query, if it exists.
     trace?: string
+
+    /**
+     * How the search was evaluated, if the query contains explain:yes. Only
+     * set on the final progress event.
+     */
+    explain?: PlanNode
+}
+
+/**
+ * A node of the tree explaining how a search was evaluated. The root node is
+ * the plan of the query. Its descendants are the queries of the plan and the
+ * jobs that ran for them.
+ */
+export interface PlanNode {
+    name: string
+    query?: string
+    durationMs: number
+    repositories: number
+    shards: number
+    skippedShards: number
+    matches: number
+    timedout: number
+    limitHit: boolean
+    error?: string
+    children: PlanNode[]
 }
 
 export interface Skipped {
@@ -248,6 +291,45 @@ export const emptyAggregateResults: AggregateStreamingSearchResults = {
     },
 }
 
+/**
+ * Returns the key identifying an owner match. Like on the backend, handles are
+ * compared case-insensitively.
+ */
+export function getOwnerMatchKey(match: OwnerMatch): string {
+    return `${match.repository}@${match.commit ?? ''}:${match.handle.toLowerCase()}`
+}
+
+/**
+ * Appends newMatches to matches. An owner match replaces the earlier owner
+ * match with the same key in place, since it carries the updated file count.
+ */
+export function mergeMatches(matches: SearchMatch[], newMatches: SearchMatch[]): SearchMatch[] {
+    if (!newMatches.some(match => match.type === 'owner')) {
+        return matches.concat(newMatches)
+    }
+
+    const merged = [...matches]
+    const ownerIndexes = new Map<string, number>()
+    for (const [index, match] of merged.entries()) {
+        if (match.type === 'owner') {
+            ownerIndexes.set(getOwnerMatchKey(match), index)
+        }
+    }
+    for (const match of newMatches) {
+        if (match.type === 'owner') {
+            const key = getOwnerMatchKey(match)
+            const index = ownerIndexes.get(key)
+            if (index !== undefined) {
+                merged[index] = match
+                continue
+            }
+            ownerIndexes.set(key, merged.length)
+        }
+        merged.push(match)
+    }
+    return merged
+}
+
 /**
  * Converts a stream of SearchEvents into AggregateStreamingSearchResults
  */
@@ -264,8 +346,8 @@ export const switchAggregateSearchResults: OperatorFunction<SearchEvent, Aggrega
                         case 'matches':
                             return {
                                 ...results,
-                                // Matches are additive
-                                results: results.results.concat(newEvent.value.data),
+                                // Matches are additive, except for updated owners
+                                results: mergeMatches(results.results, newEvent.value.data),
                             }
 
                         case 'progress':
@@ -520,13 +602,20 @@ export function getMatchUrl(match: SearchMatch): string {
             return match.url
         case 'repo':
             return getRepoMatchUrl(match)
+        case 'owner':
+            return getRepositoryUrl(match.repository, match.branches)
     }
 }
 
-export function getMatchTitle(match: RepositoryMatch | CommitMatch): MarkdownText {
+export function getMatchTitle(match: RepositoryMatch | CommitMatch | OwnerMatch): MarkdownText {
     if (match.type === 'commit') {
         return match.label
     }
+    if (match.type === 'owner') {
+        const label = match.repository + (match.branches?.[0] ? `@${match.branches[0]}` : '')
+        const url = getRepositoryUrl(match.repository, match.branches)
+        return `\`${match.handle}\` in [${displayRepoName(label)}](${url})`
+    }
 
     return `[${displayRepoName(getRepoMatchLabel(match))}](${getRepoMatchUrl(match)})`
 }
@@ -9,15 +9,16 @@ import { Markdown } from '@sourcegraph/shared/src/components/Markdown'
 import { RepoIcon } from '@sourcegraph/shared/src/components/RepoIcon'
 import { ResultContainer } from '@sourcegraph/shared/src/components/ResultContainer'
 import { SearchResultStar } from '@sourcegraph/shared/src/components/SearchResultStar'
-import { CommitMatch, getMatchTitle, RepositoryMatch } from '@sourcegraph/shared/src/search/stream'
+import { CommitMatch, getMatchTitle, OwnerMatch, RepositoryMatch } from '@sourcegraph/shared/src/search/stream'
 import { renderMarkdown } from '@sourcegraph/shared/src/util/markdown'
+import { pluralize } from '@sourcegraph/shared/src/util/strings'
 import { formatRepositoryStarCount } from '@sourcegraph/shared/src/util/stars'
 
 import { CommitSearchResultMatch } from './CommitSearchResultMatch'
 import styles from './SearchResult.module.scss'
 
 interface Props {
-    result: CommitMatch | RepositoryMatch
+    result: CommitMatch | RepositoryMatch | OwnerMatch
     repoName: string
     icon: React.ComponentType<{ className?: string }>
 }
@@ -116,6 +117,16 @@ export const SearchResult: React.FunctionComponent<Props> = ({ result, icon, rep
             )
         }
 
+        if (result.type === 'owner') {
+            return (
+                <div className={classNames(styles.searchResultMatch, 'p-2')}>
+                    <small>
+                        Owns {result.fileCount} matched {pluralize('file', result.fileCount)}
+                    </small>
+                </div>
+            )
+        }
+
         return <CommitSearchResultMatch key={result.url} item={result} />
     }
 
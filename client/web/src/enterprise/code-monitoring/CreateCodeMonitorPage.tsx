@@ -8,7 +8,7 @@ import { AuthenticatedUser } from '../../auth'
 import { withAuthenticatedUser } from '../../auth/withAuthenticatedUser'
 import { CodeMonitoringLogo } from '../../code-monitoring/CodeMonitoringLogo'
 import { PageTitle } from '../../components/PageTitle'
-import { CodeMonitorFields, MonitorEmailPriority } from '../../graphql-operations'
+import { CodeMonitorFields, MonitorEmailDigest, MonitorEmailPriority } from '../../graphql-operations'
 import { eventLogger } from '../../tracking/eventLogger'
 
 import { createCodeMonitor as _createCodeMonitor } from './backend'
@@ -60,6 +60,7 @@ const AuthenticatedCreateCodeMonitorPage: React.FunctionComponent<CreateCodeMoni
                     email: {
                         enabled: action.enabled,
                         priority: MonitorEmailPriority.NORMAL,
+                        digest: 'digest' in action ? action.digest : MonitorEmailDigest.NONE,
                         recipients: [authenticatedUser.id],
                         header: '',
                     },
@@ -7,7 +7,7 @@ import sinon from 'sinon'
 import { WebStory } from '../../components/WebStory'
 
 import { ManageCodeMonitorPage } from './ManageCodeMonitorPage'
-import { mockCodeMonitor, mockUser } from './testing/util'
+import { mockCodeMonitor, mockResultHistory, mockUser } from './testing/util'
 
 const { add } = storiesOf('web/enterprise/code-monitoring/ManageCodeMonitorPage', module).addParameters({
     design: {
@@ -26,6 +26,7 @@ add('Example', () => (
                 updateCodeMonitor={sinon.fake()}
                 fetchCodeMonitor={sinon.fake((id: string) => of(mockCodeMonitor))}
                 deleteCodeMonitor={sinon.fake((id: string) => NEVER)}
+                fetchCodeMonitorResultHistory={sinon.fake(() => of(mockResultHistory))}
             />
         )}
     </WebStory>
@@ -46,6 +47,7 @@ add('Disabled toggles', () => {
                     updateCodeMonitor={sinon.fake()}
                     fetchCodeMonitor={sinon.fake((id: string) => of(monitor))}
                     deleteCodeMonitor={sinon.fake((id: string) => NEVER)}
+                    fetchCodeMonitorResultHistory={sinon.fake(() => of(mockResultHistory))}
                 />
             )}
         </WebStory>
@@ -9,6 +9,7 @@ import {
     MonitorEditInput,
     MonitorEditTriggerInput,
     MonitorEditActionInput,
+    MonitorEmailDigest,
     MonitorEmailPriority,
 } from '@sourcegraph/shared/src/graphql-operations'
 
@@ -44,6 +45,9 @@ describe('ManageCodeMonitorPage', () => {
         },
         toggleCodeMonitorEnabled: sinon.spy((id: string, enabled: boolean) => of({ id: 'test', enabled: true })),
         deleteCodeMonitor: sinon.spy((id: string) => NEVER),
+        fetchCodeMonitorResultHistory: sinon.spy(() =>
+            of({ nodes: [], totalCount: 0, pageInfo: { endCursor: null, hasNextPage: false } })
+        ),
     }
 
     test('Form is pre-loaded with code monitor data', () => {
@@ -78,7 +82,7 @@ describe('ManageCodeMonitorPage', () => {
             props.updateCodeMonitor,
             {
                 id: 'test-id',
-                update: { namespace: 'userID', description: 'Test updated', enabled: true },
+                update: { namespace: 'userID', description: 'Test updated', enabled: true, quietHours: null },
             },
             { id: 'test-0', update: { query: 'test' } },
             [
@@ -88,6 +92,7 @@ describe('ManageCodeMonitorPage', () => {
                         update: {
                             enabled: true,
                             priority: MonitorEmailPriority.NORMAL,
+                            digest: MonitorEmailDigest.NONE,
                             recipients: ['userID'],
                             header: '',
                         },
@@ -14,15 +14,17 @@ import { AuthenticatedUser } from '../../auth'
 import { withAuthenticatedUser } from '../../auth/withAuthenticatedUser'
 import { CodeMonitoringLogo } from '../../code-monitoring/CodeMonitoringLogo'
 import { PageTitle } from '../../components/PageTitle'
-import { CodeMonitorFields, MonitorEmailPriority } from '../../graphql-operations'
+import { CodeMonitorFields, MonitorEmailDigest, MonitorEmailPriority } from '../../graphql-operations'
 import { eventLogger } from '../../tracking/eventLogger'
 
 import {
     fetchCodeMonitor as _fetchCodeMonitor,
     updateCodeMonitor as _updateCodeMonitor,
     deleteCodeMonitor as _deleteCodeMonitor,
+    fetchCodeMonitorResultHistory as _fetchCodeMonitorResultHistory,
 } from './backend'
 import { CodeMonitorForm } from './components/CodeMonitorForm'
+import { CodeMonitorResultHistory } from './components/CodeMonitorResultHistory'
 
 interface ManageCodeMonitorPageProps extends RouteComponentProps<{ id: Scalars['ID'] }> {
     authenticatedUser: AuthenticatedUser
@@ -32,6 +34,7 @@ interface ManageCodeMonitorPageProps extends RouteComponentProps<{ id: Scalars['
     fetchCodeMonitor?: typeof _fetchCodeMonitor
     updateCodeMonitor?: typeof _updateCodeMonitor
     deleteCodeMonitor?: typeof _deleteCodeMonitor
+    fetchCodeMonitorResultHistory?: typeof _fetchCodeMonitorResultHistory
 }
 
 const AuthenticatedManageCodeMonitorPage: React.FunctionComponent<ManageCodeMonitorPageProps> = ({
@@ -42,6 +45,7 @@ const AuthenticatedManageCodeMonitorPage: React.FunctionComponent<ManageCodeMoni
     fetchCodeMonitor = _fetchCodeMonitor,
     updateCodeMonitor = _updateCodeMonitor,
     deleteCodeMonitor = _deleteCodeMonitor,
+    fetchCodeMonitorResultHistory = _fetchCodeMonitorResultHistory,
 }) => {
     const LOADING = 'loading' as const
 
@@ -51,8 +55,18 @@ const AuthenticatedManageCodeMonitorPage: React.FunctionComponent<ManageCodeMoni
         id: '',
         description: '',
         enabled: true,
+        quietHours: null,
         trigger: { id: '', query: '' },
-        actions: { nodes: [{ id: '', enabled: true, recipients: { nodes: [{ id: authenticatedUser.id }] } }] },
+        actions: {
+            nodes: [
+                {
+                    id: '',
+                    enabled: true,
+                    digest: MonitorEmailDigest.NONE,
+                    recipients: { nodes: [{ id: authenticatedUser.id }] },
+                },
+            ],
+        },
     })
 
     const codeMonitorOrError = useObservable(
@@ -80,20 +94,43 @@ const AuthenticatedManageCodeMonitorPage: React.FunctionComponent<ManageCodeMoni
                         namespace: authenticatedUser.id,
                         description: codeMonitor.description,
                         enabled: codeMonitor.enabled,
+                        // Quiet hours can't be edited here yet. We send them
+                        // unchanged so that they are not removed.
+                        quietHours: codeMonitor.quietHours && {
+                            start: codeMonitor.quietHours.start,
+                            end: codeMonitor.quietHours.end,
+                            timezone: codeMonitor.quietHours.timezone,
+                        },
                     },
                 },
                 { id: codeMonitor.trigger.id, update: { query: codeMonitor.trigger.query } },
-                codeMonitor.actions.nodes.map(action => ({
-                    email: {
-                        id: action.id,
-                        update: {
-                            enabled: action.enabled,
-                            priority: MonitorEmailPriority.NORMAL,
-                            recipients: [authenticatedUser.id],
-                            header: '',
-                        },
-                    },
-                }))
+                codeMonitor.actions.nodes.map(action => {
+                    // Slack webhooks and webhooks can't be edited here yet. We
+                    // send them unchanged so that they are not deleted.
+                    switch (action.__typename) {
+                        case 'MonitorSlackWebhook':
+                            return {
+                                slackWebhook: { id: action.id, update: { enabled: action.enabled, url: action.url } },
+                            }
+                        case 'MonitorWebhook':
+                            return {
+                                webhook: { id: action.id, update: { enabled: action.enabled, url: action.url } },
+                            }
+                        default:
+                            return {
+                                email: {
+                                    id: action.id,
+                                    update: {
+                                        enabled: action.enabled,
+                                        priority: MonitorEmailPriority.NORMAL,
+                                        digest: action.digest,
+                                        recipients: [authenticatedUser.id],
+                                        header: '',
+                                    },
+                                },
+                            }
+                    }
+                })
             ),
         [authenticatedUser.id, match.params.id, updateCodeMonitor]
     )
@@ -125,6 +162,12 @@ const AuthenticatedManageCodeMonitorPage: React.FunctionComponent<ManageCodeMoni
                         submitButtonLabel="Save"
                         showDeleteButton={true}
                     />
+                    <CodeMonitorResultHistory
+                        monitorID={match.params.id}
+                        location={location}
+                        history={history}
+                        fetchCodeMonitorResultHistory={fetchCodeMonitorResultHistory}
+                    />
                 </>
             )}
         </div>
@@ -15,12 +15,15 @@ import {
     DeleteCodeMonitorVariables,
     FetchCodeMonitorResult,
     FetchCodeMonitorVariables,
+    FetchCodeMonitorResultHistoryResult,
+    FetchCodeMonitorResultHistoryVariables,
     ListCodeMonitors,
     ListUserCodeMonitorsResult,
     ListUserCodeMonitorsVariables,
     MonitorEditActionInput,
     MonitorEditInput,
     MonitorEditTriggerInput,
+    MonitorResultFields,
     ResetTriggerQueryTimestampsResult,
     ResetTriggerQueryTimestampsVariables,
     Scalars,
@@ -37,6 +40,11 @@ const CodeMonitorFragment = gql`
         id
         description
         enabled
+        quietHours {
+            start
+            end
+            timezone
+        }
         trigger {
             ... on MonitorQuery {
                 id
@@ -48,12 +56,25 @@ const CodeMonitorFragment = gql`
                 ... on MonitorEmail {
                     id
                     enabled
+                    digest
                     recipients {
                         nodes {
                             id
                         }
                     }
                 }
+                ... on MonitorSlackWebhook {
+                    __typename
+                    id
+                    enabled
+                    url
+                }
+                ... on MonitorWebhook {
+                    __typename
+                    id
+                    enabled
+                    url
+                }
             }
         }
     }
@@ -174,6 +195,11 @@ export const fetchCodeMonitor = (id: string): Observable<FetchCodeMonitorResult>
                         namespaceName
                     }
                     enabled
+                    quietHours {
+                        start
+                        end
+                        timezone
+                    }
                     actions {
                         nodes {
                             ... on MonitorEmail {
@@ -185,6 +211,19 @@ export const fetchCodeMonitor = (id: string): Observable<FetchCodeMonitorResult>
                                     }
                                 }
                                 enabled
+                                digest
+                            }
+                            ... on MonitorSlackWebhook {
+                                __typename
+                                id
+                                enabled
+                                url
+                            }
+                            ... on MonitorWebhook {
+                                __typename
+                                id
+                                enabled
+                                url
                             }
                         }
                     }
@@ -300,3 +339,65 @@ export const triggerTestEmailAction = ({
         })
     )
 }
+
+export const fetchCodeMonitorResultHistory = ({
+    id,
+    first,
+    after,
+}: FetchCodeMonitorResultHistoryVariables): Observable<{
+    nodes: MonitorResultFields[]
+    totalCount: number
+    pageInfo: { endCursor: string | null; hasNextPage: boolean }
+}> => {
+    const query = gql`
+        query FetchCodeMonitorResultHistory($id: ID!, $first: Int, $after: String) {
+            node(id: $id) {
+                __typename
+                ... on Monitor {
+                    resultHistory(first: $first, after: $after) {
+                        nodes {
+                            ...MonitorResultFields
+                        }
+                        totalCount
+                        pageInfo {
+                            endCursor
+                            hasNextPage
+                        }
+                    }
+                }
+            }
+        }
+
+        fragment MonitorResultFields on MonitorResult {
+            id
+            repository
+            url
+            commit
+            message
+            author
+            path
+            lineNumber
+            preview
+            timestamp
+        }
+    `
+
+    return requestGraphQL<FetchCodeMonitorResultHistoryResult, FetchCodeMonitorResultHistoryVariables>(query, {
+        id,
+        first,
+        after,
+    }).pipe(
+        map(dataOrThrowErrors),
+        map(data => {
+            if (!data.node) {
+                throw new Error('code monitor not found')
+            }
+
+            if (data.node.__typename !== 'Monitor') {
+                throw new Error(`Requested node is a ${data.node.__typename}, not a Monitor`)
+            }
+
+            return data.node.resultHistory
+        })
+    )
+}
@@ -66,6 +66,7 @@ export const CodeMonitorForm: React.FunctionComponent<CodeMonitorFormProps> = ({
             id: '',
             description: description ?? '',
             enabled: true,
+            quietHours: null,
             trigger: { id: '', query: triggerQuery ?? '' },
             actions: {
                 nodes: [],
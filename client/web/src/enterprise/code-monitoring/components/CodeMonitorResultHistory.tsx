@@ -0,0 +1,96 @@
+import * as H from 'history'
+import React, { useCallback } from 'react'
+
+import { Link } from '@sourcegraph/shared/src/components/Link'
+import { Container } from '@sourcegraph/wildcard'
+
+import { FilteredConnection } from '../../../components/FilteredConnection'
+import { Timestamp } from '../../../components/time/Timestamp'
+import { FetchCodeMonitorResultHistoryVariables, MonitorResultFields } from '../../../graphql-operations'
+import { fetchCodeMonitorResultHistory as _fetchCodeMonitorResultHistory } from '../backend'
+
+interface CodeMonitorResultHistoryProps {
+    monitorID: string
+    location: H.Location
+    history: H.History
+
+    fetchCodeMonitorResultHistory?: typeof _fetchCodeMonitorResultHistory
+}
+
+const MonitorResultNodeHeader: React.FunctionComponent = () => (
+    <thead>
+        <tr>
+            <th>Time</th>
+            <th>Repository</th>
+            <th>Result</th>
+        </tr>
+    </thead>
+)
+
+const MonitorResultNode: React.FunctionComponent<{ node: MonitorResultFields }> = ({ node }) => (
+    <tr>
+        <td className="text-nowrap">
+            <Timestamp date={node.timestamp} />
+        </td>
+        <td className="text-nowrap">{node.repository}</td>
+        <td className="w-100">
+            <Link to={node.url}>
+                {node.path
+                    ? `${node.path}${node.lineNumber ? `:${node.lineNumber}` : ''}`
+                    : node.commit?.slice(0, 7) ?? node.url}
+            </Link>
+            {(node.preview || node.message) && (
+                <div className="text-monospace text-muted text-truncate">{node.preview ?? node.message}</div>
+            )}
+        </td>
+    </tr>
+)
+
+/**
+ * Lists the search results a code monitor ran its actions for, newest first.
+ */
+export const CodeMonitorResultHistory: React.FunctionComponent<CodeMonitorResultHistoryProps> = ({
+    monitorID,
+    location,
+    history,
+    fetchCodeMonitorResultHistory = _fetchCodeMonitorResultHistory,
+}) => {
+    const queryConnection = useCallback(
+        (args: Partial<FetchCodeMonitorResultHistoryVariables>) =>
+            fetchCodeMonitorResultHistory({
+                id: monitorID,
+                first: args.first ?? null,
+                after: args.after ?? null,
+            }),
+        [monitorID, fetchCodeMonitorResultHistory]
+    )
+
+    return (
+        <div className="mt-5">
+            <h3>Result history</h3>
+            <Container>
+                <FilteredConnection<MonitorResultFields>
+                    location={location}
+                    history={history}
+                    defaultFirst={20}
+                    useURLQuery={false}
+                    queryConnection={queryConnection}
+                    hideSearch={true}
+                    listComponent="table"
+                    listClassName="table mb-0"
+                    headComponent={MonitorResultNodeHeader}
+                    nodeComponent={MonitorResultNode}
+                    noun="result"
+                    pluralNoun="results"
+                    noSummaryIfAllNodesVisible={true}
+                    cursorPaging={true}
+                    emptyElement={
+                        <p className="w-100 mb-0 text-muted text-center">
+                            This code monitor has not found any results yet.
+                        </p>
+                    }
+                />
+            </Container>
+        </div>
+    )
+}
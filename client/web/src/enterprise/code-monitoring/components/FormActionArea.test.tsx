@@ -4,6 +4,7 @@ import { act } from 'react-dom/test-utils'
 import sinon from 'sinon'
 
 import { AuthenticatedUser } from '../../../auth'
+import { MonitorEmailDigest } from '../../../graphql-operations'
 
 import { FormActionArea } from './FormActionArea'
 
@@ -14,7 +15,14 @@ describe('FormActionArea', () => {
         email: 'alice@alice.com',
     } as AuthenticatedUser
     const mockActions = {
-        nodes: [{ id: 'id1', recipients: { nodes: [{ id: authenticatedUser.id }] }, enabled: true }],
+        nodes: [
+            {
+                id: 'id1',
+                recipients: { nodes: [{ id: authenticatedUser.id }] },
+                enabled: true,
+                digest: MonitorEmailDigest.NONE,
+            },
+        ],
     }
 
     test('Error is shown if code monitor has empty description', () => {
@@ -8,7 +8,7 @@ import { asError, isErrorLike } from '@sourcegraph/shared/src/util/errors'
 import { useEventObservable } from '@sourcegraph/shared/src/util/useObservable'
 
 import { AuthenticatedUser } from '../../../auth'
-import { CodeMonitorFields, MonitorEmailPriority } from '../../../graphql-operations'
+import { CodeMonitorFields, MonitorEmailDigest, MonitorEmailPriority } from '../../../graphql-operations'
 import { triggerTestEmailAction } from '../backend'
 
 import styles from './FormActionArea.module.scss'
@@ -54,12 +54,45 @@ export const FormActionArea: React.FunctionComponent<ActionAreaProps> = ({
         actions.nodes[0] ? actions.nodes[0].enabled : true
     )
 
+    const [emailDigest, setEmailDigest] = useState(
+        actions.nodes[0] && 'digest' in actions.nodes[0] ? actions.nodes[0].digest : MonitorEmailDigest.NONE
+    )
+
     const toggleEmailNotificationEnabled: (enabled: boolean) => void = useCallback(
         enabled => {
             setEmailNotificationEnabled(enabled)
             onActionsChange({
                 // TODO farhan: refactor to accomodate more than one action.
-                nodes: [{ id: actions.nodes[0].id, recipients: { nodes: [{ id: authenticatedUser.id }] }, enabled }],
+                nodes: actions.nodes.map((action, index) =>
+                    index === 0
+                        ? {
+                              id: action.id,
+                              recipients: { nodes: [{ id: authenticatedUser.id }] },
+                              enabled,
+                              digest: emailDigest,
+                          }
+                        : action
+                ),
+            })
+        },
+        [authenticatedUser, onActionsChange, actions.nodes, emailDigest]
+    )
+
+    const onEmailDigestChange: React.ChangeEventHandler<HTMLSelectElement> = useCallback(
+        event => {
+            const digest = event.target.value as MonitorEmailDigest
+            setEmailDigest(digest)
+            onActionsChange({
+                nodes: actions.nodes.map((action, index) =>
+                    index === 0
+                        ? {
+                              id: action.id,
+                              recipients: { nodes: [{ id: authenticatedUser.id }] },
+                              enabled: action.enabled,
+                              digest,
+                          }
+                        : action
+                ),
             })
         },
         [authenticatedUser, onActionsChange, actions.nodes]
@@ -74,11 +107,25 @@ export const FormActionArea: React.FunctionComponent<ActionAreaProps> = ({
                 // We are creating a new monitor if there are no actions yet.
                 // The ID can be empty here, since we'll generate a new ID when we send the creation request.
                 onActionsChange({
-                    nodes: [{ id: '', enabled: true, recipients: { nodes: [{ id: authenticatedUser.id }] } }],
+                    nodes: [
+                        {
+                            id: '',
+                            enabled: true,
+                            digest: emailDigest,
+                            recipients: { nodes: [{ id: authenticatedUser.id }] },
+                        },
+                    ],
                 })
             }
         },
-        [setActionsCompleted, setShowEmailNotificationForm, actions.nodes.length, authenticatedUser.id, onActionsChange]
+        [
+            setActionsCompleted,
+            setShowEmailNotificationForm,
+            actions.nodes.length,
+            authenticatedUser.id,
+            onActionsChange,
+            emailDigest,
+        ]
     )
     const cancelForm: React.FormEventHandler = useCallback(
         event => {
@@ -157,6 +204,22 @@ export const FormActionArea: React.FunctionComponent<ActionAreaProps> = ({
                             Code monitors are currently limited to sending emails to your primary email address.
                         </small>
                     </div>
+                    <div className="form-group">
+                        <label htmlFor="code-monitoring-form-actions-digest">Frequency</label>
+                        <select
+                            id="code-monitoring-form-actions-digest"
+                            className="form-control test-action-digest"
+                            value={emailDigest}
+                            onChange={onEmailDigestChange}
+                        >
+                            <option value={MonitorEmailDigest.NONE}>Send an email for every new result</option>
+                            <option value={MonitorEmailDigest.HOURLY}>Send an hourly digest</option>
+                            <option value={MonitorEmailDigest.DAILY}>Send a daily digest</option>
+                        </select>
+                        <small className="text-muted">
+                            Digests summarize all new results of an hour or a day in a single email.
+                        </small>
+                    </div>
                     <div className="flex mt-1">
                         <button
                             type="button"
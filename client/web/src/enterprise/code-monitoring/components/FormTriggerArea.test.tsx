@@ -19,7 +19,7 @@ describe('FormTriggerArea', () => {
     test('Correct checkboxes shown when query does not fulfill requirements', () => {
         let component = mount(
             <FormTriggerArea
-                query="test repo:test"
+                query="test type:repo repo:test"
                 triggerCompleted={false}
                 onQueryChange={sinon.spy()}
                 setTriggerCompleted={sinon.spy()}
@@ -38,25 +38,25 @@ describe('FormTriggerArea', () => {
 
     const testCases = [
         { query: '', patternTypeChecked: true, typeChecked: false, repoChecked: false, validChecked: false },
-        { query: 'test', patternTypeChecked: true, typeChecked: false, repoChecked: false, validChecked: true },
+        { query: 'test', patternTypeChecked: true, typeChecked: true, repoChecked: false, validChecked: true },
         {
             query: 'test patternType:literal',
             patternTypeChecked: true,
-            typeChecked: false,
+            typeChecked: true,
             repoChecked: false,
             validChecked: true,
         },
         {
             query: 'test patternType:regexp',
             patternTypeChecked: true,
-            typeChecked: false,
+            typeChecked: true,
             repoChecked: false,
             validChecked: true,
         },
         {
             query: 'test patternType:structural',
             patternTypeChecked: false,
-            typeChecked: false,
+            typeChecked: true,
             repoChecked: false,
             validChecked: true,
         },
@@ -84,7 +84,7 @@ describe('FormTriggerArea', () => {
         {
             query: 'test repo:test',
             patternTypeChecked: true,
-            typeChecked: false,
+            typeChecked: true,
             repoChecked: true,
             validChecked: true,
         },
@@ -27,7 +27,8 @@ interface TriggerAreaProps {
     cardLinkClassName?: string
 }
 
-const isDiffOrCommit = (value: string): boolean => value === 'diff' || value === 'commit'
+// Code monitors search file contents if there is no type: filter.
+const isFileDiffOrCommit = (value: string): boolean => value === 'file' || value === 'diff' || value === 'commit'
 const isLiteralOrRegexp = (value: string): boolean => value === 'literal' || value === 'regexp'
 
 const ValidQueryChecklistItem: React.FunctionComponent<{ checked: boolean; hint?: string; className?: string }> = ({
@@ -113,7 +114,7 @@ export const FormTriggerArea: React.FunctionComponent<TriggerAreaProps> = ({
     }, [])
 
     const [isValidQuery, setIsValidQuery] = useState(false)
-    const [hasTypeDiffOrCommitFilter, setHasTypeDiffOrCommitFilter] = useState(false)
+    const [hasValidTypeFilter, setHasValidTypeFilter] = useState(false)
     const [hasRepoFilter, setHasRepoFilter] = useState(false)
     const [hasPatternTypeFilter, setHasPatternTypeFilter] = useState(false)
     const [hasValidPatternTypeFilter, setHasValidPatternTypeFilter] = useState(true)
@@ -129,20 +130,21 @@ export const FormTriggerArea: React.FunctionComponent<TriggerAreaProps> = ({
                         const isValidQuery = !!value && tokens.type === 'success'
                         setIsValidQuery(isValidQuery)
 
-                        let hasTypeDiffOrCommitFilter = false
+                        let hasValidTypeFilter = false
                         let hasRepoFilter = false
                         let hasPatternTypeFilter = false
                         let hasValidPatternTypeFilter = true
 
                         if (tokens.type === 'success') {
                             const filters = tokens.term.filter(token => token.type === 'filter')
-                            hasTypeDiffOrCommitFilter = filters.some(
-                                filter =>
-                                    filter.type === 'filter' &&
-                                    resolveFilter(filter.field.value)?.type === FilterType.type &&
-                                    filter.value &&
-                                    isDiffOrCommit(filter.value.value)
-                            )
+                            hasValidTypeFilter =
+                                isValidQuery &&
+                                filters.every(
+                                    filter =>
+                                        filter.type !== 'filter' ||
+                                        resolveFilter(filter.field.value)?.type !== FilterType.type ||
+                                        (filter.value && isFileDiffOrCommit(filter.value.value))
+                                )
 
                             hasRepoFilter = filters.some(
                                 filter =>
@@ -172,7 +174,7 @@ export const FormTriggerArea: React.FunctionComponent<TriggerAreaProps> = ({
                                 )
                         }
 
-                        setHasTypeDiffOrCommitFilter(hasTypeDiffOrCommitFilter)
+                        setHasValidTypeFilter(hasValidTypeFilter)
                         setHasRepoFilter(hasRepoFilter)
                         setHasPatternTypeFilter(hasPatternTypeFilter)
                         setHasValidPatternTypeFilter(hasValidPatternTypeFilter)
@@ -181,8 +183,8 @@ export const FormTriggerArea: React.FunctionComponent<TriggerAreaProps> = ({
                             return 'Failed to parse query'
                         }
 
-                        if (!hasTypeDiffOrCommitFilter) {
-                            return 'Code monitors require queries to specify either `type:commit` or `type:diff`.'
+                        if (!hasValidTypeFilter) {
+                            return 'Code monitors search file contents, or commits with `type:commit` or `type:diff`.'
                         }
 
                         if (!hasRepoFilter) {
@@ -257,10 +259,10 @@ export const FormTriggerArea: React.FunctionComponent<TriggerAreaProps> = ({
                                     <li>
                                         <ValidQueryChecklistItem
                                             className="test-type-checkbox"
-                                            checked={hasTypeDiffOrCommitFilter}
-                                            hint="type:diff targets code present in new commits, while type:commit targets commit messages"
+                                            checked={hasValidTypeFilter}
+                                            hint="Without a type: filter, code monitors fire for lines which newly match. type:diff targets code present in new commits, while type:commit targets commit messages"
                                         >
-                                            Contains a <code>type:diff</code> or <code>type:commit</code> filter
+                                            Searches file contents, <code>type:diff</code> or <code>type:commit</code>
                                         </ValidQueryChecklistItem>
                                     </li>
                                     <li>
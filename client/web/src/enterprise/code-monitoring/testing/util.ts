@@ -1,5 +1,5 @@
 import { AuthenticatedUser } from '../../../auth'
-import { CodeMonitorFields } from '../../../graphql-operations'
+import { CodeMonitorFields, MonitorEmailDigest, MonitorResultFields } from '../../../graphql-operations'
 
 export const mockUser: AuthenticatedUser = {
     __typename: 'User',
@@ -26,9 +26,17 @@ export const mockCodeMonitorFields: CodeMonitorFields = {
     id: 'foo0',
     description: 'Test code monitor',
     enabled: true,
+    quietHours: null,
     trigger: { id: 'test-0', query: 'test' },
     actions: {
-        nodes: [{ id: 'test-action-0', enabled: true, recipients: { nodes: [{ id: 'baz-0' }] } }],
+        nodes: [
+            {
+                id: 'test-action-0',
+                enabled: true,
+                digest: MonitorEmailDigest.NONE,
+                recipients: { nodes: [{ id: 'baz-0' }] },
+            },
+        ],
     },
 }
 
@@ -38,12 +46,18 @@ export const mockCodeMonitor = {
         id: 'foo0',
         description: 'Test code monitor',
         enabled: true,
+        quietHours: null,
         owner: { id: 'test-id', namespaceName: 'test-user' },
         actions: {
             id: 'test-0',
             enabled: true,
             nodes: [
-                { id: 'test-action-0', enabled: true, recipients: { nodes: [{ id: 'baz-0', url: '/user/test' }] } },
+                {
+                    id: 'test-action-0',
+                    enabled: true,
+                    digest: MonitorEmailDigest.NONE,
+                    recipients: { nodes: [{ id: 'baz-0', url: '/user/test' }] },
+                },
             ],
         },
         trigger: { id: 'test-0', query: 'test' },
@@ -184,3 +198,38 @@ export const mockCodeMonitorNodes = [
         trigger: { id: 'test-2', query: 'test' },
     },
 ]
+
+export const mockResultHistory: {
+    nodes: MonitorResultFields[]
+    totalCount: number
+    pageInfo: { endCursor: string | null; hasNextPage: boolean }
+} = {
+    nodes: [
+        {
+            id: 'result-2',
+            repository: 'github.com/sourcegraph/sourcegraph',
+            url: '/github.com/sourcegraph/sourcegraph/-/blob/main.go?L3',
+            commit: null,
+            message: null,
+            author: null,
+            path: 'main.go',
+            lineNumber: 3,
+            preview: '// TODO(security): validate input',
+            timestamp: '2021-09-30T12:00:00Z',
+        },
+        {
+            id: 'result-1',
+            repository: 'github.com/sourcegraph/sourcegraph',
+            url: '/github.com/sourcegraph/sourcegraph/-/commit/0123456789abcdef',
+            commit: '0123456789abcdef',
+            message: 'Add TODO',
+            author: 'alice',
+            path: null,
+            lineNumber: null,
+            preview: null,
+            timestamp: '2021-09-29T12:00:00Z',
+        },
+    ],
+    totalCount: 2,
+    pageInfo: { endCursor: null, hasNextPage: false },
+}
@@ -1,4 +1,5 @@
 import * as H from 'history'
+import AccountIcon from 'mdi-react/AccountIcon'
 import AlphaSBoxIcon from 'mdi-react/AlphaSBoxIcon'
 import FileDocumentIcon from 'mdi-react/FileDocumentIcon'
 import FileIcon from 'mdi-react/FileIcon'
@@ -18,6 +19,7 @@ import {
     PathMatch,
     SearchMatch,
     getMatchUrl,
+    getOwnerMatchKey,
 } from '@sourcegraph/shared/src/search/stream'
 import { SettingsCascadeProps } from '@sourcegraph/shared/src/settings/settings'
 import { TelemetryProps } from '@sourcegraph/shared/src/telemetry/telemetryService'
@@ -63,6 +65,9 @@ export const StreamingSearchResultsList: React.FunctionComponent<StreamingSearch
         if (item.type === 'content' || item.type === 'symbol') {
             return `file:${getMatchUrl(item)}`
         }
+        if (item.type === 'owner') {
+            return `owner:${getOwnerMatchKey(item)}`
+        }
         return getMatchUrl(item)
     }, [])
 
@@ -93,6 +98,8 @@ export const StreamingSearchResultsList: React.FunctionComponent<StreamingSearch
                     return <SearchResult icon={SourceCommitIcon} result={result} repoName={result.repository} />
                 case 'repo':
                     return <SearchResult icon={SourceRepositoryIcon} result={result} repoName={result.repository} />
+                case 'owner':
+                    return <SearchResult icon={AccountIcon} result={result} repoName={result.repository} />
             }
         },
         [
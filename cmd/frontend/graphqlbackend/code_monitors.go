@@ -37,8 +37,35 @@ type MonitorResolver interface {
 	Description() string
 	Owner(ctx context.Context) (NamespaceResolver, error)
 	Enabled() bool
+	QuietHours() MonitorQuietHoursResolver
 	Trigger(ctx context.Context) (MonitorTrigger, error)
 	Actions(ctx context.Context, args *ListActionArgs) (MonitorActionConnectionResolver, error)
+	ResultHistory(ctx context.Context, args *ListMonitorResultsArgs) (MonitorResultConnectionResolver, error)
+}
+
+type MonitorQuietHoursResolver interface {
+	Start() int32
+	End() int32
+	Timezone() string
+}
+
+type MonitorResultConnectionResolver interface {
+	Nodes(ctx context.Context) ([]MonitorResultResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type MonitorResultResolver interface {
+	ID() graphql.ID
+	Repository() string
+	URL() string
+	Commit() *string
+	Message() *string
+	Author() *string
+	Path() *string
+	LineNumber() *int32
+	Preview() *string
+	Timestamp() DateTime
 }
 
 type MonitorTrigger interface {
@@ -73,17 +100,34 @@ type MonitorActionConnectionResolver interface {
 
 type MonitorAction interface {
 	ToMonitorEmail() (MonitorEmailResolver, bool)
+	ToMonitorSlackWebhook() (MonitorSlackWebhookResolver, bool)
+	ToMonitorWebhook() (MonitorWebhookResolver, bool)
 }
 
 type MonitorEmailResolver interface {
 	ID() graphql.ID
 	Enabled() bool
 	Priority() string
+	Digest() string
 	Header() string
 	Recipients(ctx context.Context, args *ListRecipientsArgs) (MonitorActionEmailRecipientsConnectionResolver, error)
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
 
+type MonitorSlackWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
+type MonitorWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
 type MonitorEmailRecipient interface {
 	ToUser() (*UserResolver, bool)
 }
@@ -122,6 +166,11 @@ type ListActionArgs struct {
 	After *string
 }
 
+type ListMonitorResultsArgs struct {
+	First int32
+	After *string
+}
+
 type ListRecipientsArgs struct {
 	First int32
 	After *string
@@ -138,16 +187,29 @@ type CreateTriggerArgs struct {
 }
 
 type CreateActionArgs struct {
-	Email *CreateActionEmailArgs
+	Email        *CreateActionEmailArgs
+	SlackWebhook *CreateActionSlackWebhookArgs
+	Webhook      *CreateActionWebhookArgs
 }
 
 type CreateActionEmailArgs struct {
 	Enabled    bool
 	Priority   string
+	Digest     string
 	Recipients []graphql.ID
 	Header     string
 }
 
+type CreateActionSlackWebhookArgs struct {
+	Enabled bool
+	URL     string
+}
+
+type CreateActionWebhookArgs struct {
+	Enabled bool
+	URL     string
+}
+
 type ToggleCodeMonitorArgs struct {
 	Id      graphql.ID
 	Enabled bool
@@ -171,6 +233,13 @@ type CreateMonitorArgs struct {
 	Namespace   graphql.ID
 	Description string
 	Enabled     bool
+	QuietHours  *MonitorQuietHoursArgs
+}
+
+type MonitorQuietHoursArgs struct {
+	Start    int32
+	End      int32
+	Timezone string
 }
 
 type EditActionEmailArgs struct {
@@ -178,8 +247,20 @@ type EditActionEmailArgs struct {
 	Update *CreateActionEmailArgs
 }
 
+type EditActionSlackWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionSlackWebhookArgs
+}
+
+type EditActionWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionWebhookArgs
+}
+
 type EditActionArgs struct {
-	Email *EditActionEmailArgs
+	Email        *EditActionEmailArgs
+	SlackWebhook *EditActionSlackWebhookArgs
+	Webhook      *EditActionWebhookArgs
 }
 
 type EditTriggerArgs struct {
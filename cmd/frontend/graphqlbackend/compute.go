@@ -3,11 +3,13 @@ package graphqlbackend
 import (
 	"context"
 	"regexp"
+	"sync"
 
 	"github.com/sourcegraph/go-langserver/pkg/lsp"
 	"github.com/sourcegraph/sourcegraph/internal/compute"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -22,7 +24,7 @@ type ComputeResolver interface {
 // A dummy type to express the union of compute results. This how its done by the GQL library we use.
 // https://github.com/graph-gophers/graphql-go/blob/af5bb93e114f0cd4cc095dd8eae0b67070ae8f20/example/starwars/starwars.go#L485-L487
 //
-// union ComputeResult = ComputeMatchContext | ComputeText
+// union ComputeResult = ComputeMatchContext | ComputeText | ComputeAggregate | ComputeProgress
 type computeResultResolver struct {
 	result interface{}
 }
@@ -107,18 +109,42 @@ func toLspRange(r compute.Range) lsp.Range {
 // ComputeText GQL result resolver definitions.
 
 type computeTextResolver struct {
-	repository *RepositoryResolver //nolint
-	commit     string              //nolint
-	path       string              //nolint
+	repository *RepositoryResolver
+	commit     string
+	path       string
 	t          *compute.Text
 }
 
-func (c *computeTextResolver) Repository() *RepositoryResolver { return nil }
-func (r *computeTextResolver) Commit() *string                 { return nil }
-func (r *computeTextResolver) Path() *string                   { return nil }
-func (r *computeTextResolver) Kind() *string                   { return nil }
+func (c *computeTextResolver) Repository() *RepositoryResolver { return c.repository }
+func (r *computeTextResolver) Commit() *string                 { return nilIfEmpty(r.commit) }
+func (r *computeTextResolver) Path() *string                   { return nilIfEmpty(r.path) }
+func (r *computeTextResolver) Kind() *string                   { return nilIfEmpty(r.t.Kind) }
 func (r *computeTextResolver) Value() string                   { return r.t.Value }
 
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ComputeAggregate GQL result resolver definitions.
+
+type computeAggregateResolver struct {
+	a compute.Aggregate
+}
+
+func (r *computeAggregateResolver) Value() string { return r.a.Value }
+func (r *computeAggregateResolver) Count() int32  { return int32(r.a.Count) }
+
+// ComputeProgress GQL result resolver definitions.
+
+type computeProgressResolver struct {
+	limitHit bool
+}
+
+func (r *computeProgressResolver) LimitHit() bool { return r.limitHit }
+
 // Definitions required by https://github.com/graph-gophers/graphql-go to resolve
 // a union type in GraphQL.
 
@@ -132,6 +158,16 @@ func (r *computeResultResolver) ToComputeText() (*computeTextResolver, bool) {
 	return res, ok
 }
 
+func (r *computeResultResolver) ToComputeAggregate() (*computeAggregateResolver, bool) {
+	res, ok := r.result.(*computeAggregateResolver)
+	return res, ok
+}
+
+func (r *computeResultResolver) ToComputeProgress() (*computeProgressResolver, bool) {
+	res, ok := r.result.(*computeProgressResolver)
+	return res, ok
+}
+
 func toComputeMatchContextResolver(fm *result.FileMatch, mc *compute.MatchContext, db dbutil.DB) *computeMatchContextResolver {
 	type repoKey struct {
 		Name types.RepoName
@@ -183,6 +219,13 @@ func NewComputeImplementer(ctx context.Context, db dbutil.DB, args *ComputeArgs)
 	if err != nil {
 		return nil, err
 	}
+	switch q := query.(type) {
+	case *compute.CountBy, *compute.GroupBy:
+		return computeAggregates(ctx, db, query, compute.AggregationSearchQuery(args.Query))
+	case *compute.Output:
+		return computeOutputs(ctx, db, q, compute.SearchQuery(args.Query))
+	}
+
 	patternType := "regexp"
 	job, err := NewSearchImplementer(ctx, db, &SearchArgs{Query: args.Query, PatternType: &patternType})
 	if err != nil {
@@ -197,6 +240,98 @@ func NewComputeImplementer(ctx context.Context, db dbutil.DB, args *ComputeArgs)
 	return toResultResolverList(pattern, results.Matches, db), nil
 }
 
+// computeAggregates runs searchQuery and aggregates its file matches as
+// described by the aggregating compute query q. Matches are aggregated as they
+// are streamed.
+func computeAggregates(ctx context.Context, db dbutil.DB, q compute.Query, searchQuery string) ([]*computeResultResolver, error) {
+	aggregator, err := compute.NewAggregator(q)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		mu    sync.Mutex
+		stats streaming.Stats
+	)
+	stream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		for _, m := range event.Results {
+			if fm, ok := m.(*result.FileMatch); ok {
+				aggregator.Add(fm)
+			}
+		}
+		mu.Lock()
+		stats.Update(&event.Stats)
+		mu.Unlock()
+	})
+
+	patternType := "regexp"
+	job, err := NewSearchImplementer(ctx, db, &SearchArgs{Query: searchQuery, PatternType: &patternType, Stream: stream})
+	if err != nil {
+		return nil, err
+	}
+	results, err := job.Results(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	limitHit := stats.IsLimitHit || (results != nil && results.LimitHit())
+	mu.Unlock()
+
+	return toAggregateResultResolvers(aggregator.Aggregates(), limitHit), nil
+}
+
+// toAggregateResultResolvers returns a result for each aggregate, followed by
+// a single progress result which reports whether the search hit its result
+// limit, in which case the counts of the aggregates are incomplete.
+func toAggregateResultResolvers(aggregates []compute.Aggregate, limitHit bool) []*computeResultResolver {
+	computeResult := make([]*computeResultResolver, 0, len(aggregates)+1)
+	for _, a := range aggregates {
+		computeResult = append(computeResult, &computeResultResolver{result: &computeAggregateResolver{a: a}})
+	}
+	return append(computeResult, &computeResultResolver{result: &computeProgressResolver{limitHit: limitHit}})
+}
+
+// computeOutputs runs searchQuery and renders the output template of q for the
+// matches in its file and commit results.
+func computeOutputs(ctx context.Context, db dbutil.DB, q *compute.Output, searchQuery string) ([]*computeResultResolver, error) {
+	patternType := "regexp"
+	job, err := NewSearchImplementer(ctx, db, &SearchArgs{Query: searchQuery, PatternType: &patternType})
+	if err != nil {
+		return nil, err
+	}
+	results, err := job.Results(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var computeResult []*computeResultResolver
+	for _, m := range results.Matches {
+		var r *computeTextResolver
+		switch v := m.(type) {
+		case *result.FileMatch:
+			text, err := compute.OutputFromFileMatch(ctx, v, q)
+			if err != nil {
+				return nil, err
+			}
+			if text != nil {
+				r = &computeTextResolver{repository: NewRepositoryResolver(db, v.Repo.ToRepo()), commit: string(v.CommitID), path: v.Path, t: text}
+			}
+		case *result.CommitMatch:
+			text, err := compute.OutputFromCommitMatch(ctx, v, q)
+			if err != nil {
+				return nil, err
+			}
+			if text != nil {
+				r = &computeTextResolver{repository: NewRepositoryResolver(db, v.Repo.ToRepo()), commit: string(v.Commit.ID), t: text}
+			}
+		}
+		if r != nil {
+			computeResult = append(computeResult, &computeResultResolver{result: r})
+		}
+	}
+	return computeResult, nil
+}
+
 func (r *schemaResolver) Compute(ctx context.Context, args *ComputeArgs) ([]*computeResultResolver, error) {
 	return NewComputeImplementer(ctx, r.db, args)
 }
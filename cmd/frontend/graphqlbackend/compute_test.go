@@ -2,10 +2,12 @@ package graphqlbackend
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/hexops/autogold"
+	"github.com/sourcegraph/sourcegraph/internal/compute"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
@@ -33,3 +35,22 @@ func TestToResultResolverList(t *testing.T) {
 
 	autogold.Want("resolver copies all match reseults", `["a","b"]`).Equal(t, test("a|b"))
 }
+
+func TestToAggregateResultResolvers(t *testing.T) {
+	test := func(aggregates []compute.Aggregate, limitHit bool) string {
+		var results []string
+		for _, r := range toAggregateResultResolvers(aggregates, limitHit) {
+			if a, ok := r.ToComputeAggregate(); ok {
+				results = append(results, fmt.Sprintf("%s:%d", a.Value(), a.Count()))
+			}
+			if p, ok := r.ToComputeProgress(); ok {
+				results = append(results, fmt.Sprintf("limitHit:%t", p.LimitHit()))
+			}
+		}
+		v, _ := json.Marshal(results)
+		return string(v)
+	}
+
+	autogold.Want("progress follows aggregates", `["foo:2","bar:1","limitHit:false"]`).Equal(t, test([]compute.Aggregate{{Value: "foo", Count: 2}, {Value: "bar", Count: 1}}, false))
+	autogold.Want("progress without aggregates", `["limitHit:true"]`).Equal(t, test(nil, true))
+}
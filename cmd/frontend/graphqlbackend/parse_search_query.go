@@ -3,7 +3,13 @@ package graphqlbackend
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
@@ -69,22 +75,24 @@ func toJSON(node query.Node) interface{} {
 	return struct{}{}
 }
 
-func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
-	Query       string
-	PatternType string
-}) (*JSONValue, error) {
-	var searchType query.SearchType
-	switch args.PatternType {
+// toSearchType returns the search type of the patternType argument of search
+// query fields.
+func toSearchType(patternType string) query.SearchType {
+	switch patternType {
 	case "literal":
-		searchType = query.SearchTypeLiteral
+		return query.SearchTypeLiteral
 	case "structural":
-		searchType = query.SearchTypeStructural
+		return query.SearchTypeStructural
+	case "fuzzy":
+		return query.SearchTypeFuzzy
 	case "regexp", "regex":
-		searchType = query.SearchTypeRegex
+		return query.SearchTypeRegex
 	default:
-		searchType = query.SearchTypeLiteral
+		return query.SearchTypeLiteral
 	}
+}
 
+func (r *schemaResolver) parseSearchQueryPlan(ctx context.Context, q string, searchType query.SearchType) (query.Plan, error) {
 	settings, err := decodedViewerFinalSettings(ctx, r.db)
 	if err != nil {
 		return nil, err
@@ -92,10 +100,17 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 
 	globbing := getBoolPtr(settings.SearchGlobbing, false)
 
-	plan, err := query.Pipeline(
-		query.Init(args.Query, searchType),
+	return query.Pipeline(
+		query.Init(q, searchType),
 		query.With(globbing, query.Globbing),
 	)
+}
+
+func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
+	Query       string
+	PatternType string
+}) (*JSONValue, error) {
+	plan, err := r.parseSearchQueryPlan(ctx, args.Query, toSearchType(args.PatternType))
 	if err != nil {
 		return nil, err
 	}
@@ -110,3 +125,96 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 	}
 	return &JSONValue{Value: string(json)}, nil
 }
+
+func (r *schemaResolver) LintSearchQuery(ctx context.Context, args *struct {
+	Query       string
+	PatternType string
+}) ([]*searchQueryDiagnosticResolver, error) {
+	// Like searches, the patterntype: field of the query overrides the
+	// patternType argument.
+	searchType := overrideSearchType(args.Query, toSearchType(args.PatternType))
+	plan, err := r.parseSearchQueryPlan(ctx, args.Query, searchType)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := query.Lint(args.Query, plan)
+	repoDiagnostics, err := lintRepoFilters(ctx, r.db, plan)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics = append(diagnostics, repoDiagnostics...)
+
+	resolvers := make([]*searchQueryDiagnosticResolver, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		resolvers = append(resolvers, &searchQueryDiagnosticResolver{d: d})
+	}
+	return resolvers, nil
+}
+
+// lintRepoFilters reports the repo: filters of plan which match no
+// repositories the user has access to.
+func lintRepoFilters(ctx context.Context, db dbutil.DB, plan query.Plan) ([]query.Diagnostic, error) {
+	var diagnostics []query.Diagnostic
+	seen := map[query.Range]struct{}{}
+	for _, b := range plan {
+		for _, p := range b.Parameters {
+			if p.Field != query.FieldRepo || p.Negated || p.Annotation.Labels.IsSet(query.IsPredicate) {
+				continue
+			}
+			if _, ok := seen[p.Annotation.Range]; ok {
+				continue
+			}
+			seen[p.Annotation.Range] = struct{}{}
+
+			repoPattern, _ := search.ParseRepositoryRevisions(p.Value)
+			if repoPattern == "" {
+				continue
+			}
+			repos, err := database.Repos(db).ListRepoNames(ctx, database.ReposListOptions{
+				IncludePatterns: []string{repoPattern},
+				LimitOffset:     &database.LimitOffset{Limit: 1},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(repos) == 0 {
+				diagnostics = append(diagnostics, query.NewDiagnostic(query.DiagnosticNoMatchingRepositories, query.SeverityWarning, p.Annotation.Range,
+					fmt.Sprintf("No repositories match repo:%s.", p.Value)))
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+type searchQueryDiagnosticResolver struct {
+	d query.Diagnostic
+}
+
+func (r *searchQueryDiagnosticResolver) Kind() string { return string(r.d.Kind) }
+
+func (r *searchQueryDiagnosticResolver) Severity() string { return string(r.d.Severity) }
+
+func (r *searchQueryDiagnosticResolver) Message() string { return r.d.Message }
+
+func (r *searchQueryDiagnosticResolver) Range() RangeResolver {
+	return NewRangeResolver(lsp.Range{
+		Start: lsp.Position{Line: r.d.Range.Start.Line, Character: r.d.Range.Start.Column},
+		End:   lsp.Position{Line: r.d.Range.End.Line, Character: r.d.Range.End.Column},
+	})
+}
+
+func (r *searchQueryDiagnosticResolver) Suggestion() *searchQuerySuggestionResolver {
+	if r.d.Suggestion == nil {
+		return nil
+	}
+	return &searchQuerySuggestionResolver{s: r.d.Suggestion}
+}
+
+type searchQuerySuggestionResolver struct {
+	s *query.Suggestion
+}
+
+func (r *searchQuerySuggestionResolver) Description() string { return r.s.Description }
+
+func (r *searchQuerySuggestionResolver) Query() string { return r.s.Query }
@@ -68,6 +68,9 @@ func (r *schemaResolver) savedSearchByID(ctx context.Context, id graphql.ID) (*s
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			SlackChannel:    ss.Config.SlackChannel,
+			NotifyTeams:     ss.Config.NotifyTeams,
+			TeamsWebhookURL: ss.Config.TeamsWebhookURL,
 		},
 	}
 	return savedSearch, nil
@@ -109,6 +112,12 @@ func (r savedSearchResolver) Namespace(ctx context.Context) (*NamespaceResolver,
 
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) SlackChannel() *string { return r.s.SlackChannel }
+
+func (r savedSearchResolver) NotifyTeams() bool { return r.s.NotifyTeams }
+
+func (r savedSearchResolver) TeamsWebhookURL() *string { return r.s.TeamsWebhookURL }
+
 func (r *schemaResolver) toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{db: r.db, s: entry}
 }
@@ -153,12 +162,15 @@ func (r *schemaResolver) SendSavedSearchTestNotification(ctx context.Context, ar
 }
 
 func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	Description     string
+	Query           string
+	NotifyOwner     bool
+	NotifySlack     bool
+	SlackChannel    *string
+	NotifyTeams     *bool
+	TeamsWebhookURL *string
+	OrgID           *graphql.ID
+	UserID          *graphql.ID
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -188,13 +200,25 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	var notifyTeams bool
+	if args.NotifyTeams != nil {
+		notifyTeams = *args.NotifyTeams
+	}
+	teamsWebhookURL := nonEmptyStringPtr(args.TeamsWebhookURL)
+	if notifyTeams && teamsWebhookURL == nil {
+		return nil, errMissingTeamsWebhookURL
+	}
+
 	ss, err := database.SavedSearches(r.db).Create(ctx, &types.SavedSearch{
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		Description:     args.Description,
+		Query:           args.Query,
+		Notify:          args.NotifyOwner,
+		NotifySlack:     args.NotifySlack,
+		UserID:          userID,
+		OrgID:           orgID,
+		SlackChannel:    nonEmptyStringPtr(args.SlackChannel),
+		NotifyTeams:     notifyTeams,
+		TeamsWebhookURL: teamsWebhookURL,
 	})
 	if err != nil {
 		return nil, err
@@ -204,13 +228,16 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 }
 
 func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
-	ID          graphql.ID
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	ID              graphql.ID
+	Description     string
+	Query           string
+	NotifyOwner     bool
+	NotifySlack     bool
+	SlackChannel    *string
+	NotifyTeams     *bool
+	TeamsWebhookURL *string
+	OrgID           *graphql.ID
+	UserID          *graphql.ID
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
@@ -245,14 +272,41 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	// Clients which don't know about the Slack channel or Microsoft Teams
+	// notifications omit them, in which case the existing settings are kept.
+	slackChannel, notifyTeams, teamsWebhookURL := args.SlackChannel, args.NotifyTeams, args.TeamsWebhookURL
+	if slackChannel == nil || notifyTeams == nil || teamsWebhookURL == nil {
+		existing, err := database.SavedSearches(r.db).GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if slackChannel == nil {
+			slackChannel = existing.Config.SlackChannel
+		}
+		if notifyTeams == nil {
+			notifyTeams = &existing.Config.NotifyTeams
+		}
+		if teamsWebhookURL == nil {
+			teamsWebhookURL = existing.Config.TeamsWebhookURL
+		}
+	}
+	slackChannel = nonEmptyStringPtr(slackChannel)
+	teamsWebhookURL = nonEmptyStringPtr(teamsWebhookURL)
+	if *notifyTeams && teamsWebhookURL == nil {
+		return nil, errMissingTeamsWebhookURL
+	}
+
 	ss, err := database.SavedSearches(r.db).Update(ctx, &types.SavedSearch{
-		ID:          id,
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		ID:              id,
+		Description:     args.Description,
+		Query:           args.Query,
+		Notify:          args.NotifyOwner,
+		NotifySlack:     args.NotifySlack,
+		UserID:          userID,
+		OrgID:           orgID,
+		SlackChannel:    slackChannel,
+		NotifyTeams:     *notifyTeams,
+		TeamsWebhookURL: teamsWebhookURL,
 	})
 	if err != nil {
 		return nil, err
@@ -298,3 +352,13 @@ func queryHasPatternType(query string) bool {
 }
 
 var errMissingPatternType = errors.New("a `patternType:` filter is required in the query for all saved searches. `patternType` can be \"literal\", \"regexp\" or \"structural\"")
+
+var errMissingTeamsWebhookURL = errors.New("a Microsoft Teams webhook URL is required to notify via Microsoft Teams")
+
+// nonEmptyStringPtr returns s, or nil if s points to the empty string.
+func nonEmptyStringPtr(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
@@ -154,12 +154,15 @@ func TestCreateSavedSearch(t *testing.T) {
 	}
 	userID := MarshalUserID(key)
 	savedSearches, err := (&schemaResolver{db: db}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description     string
+		Query           string
+		NotifyOwner     bool
+		NotifySlack     bool
+		SlackChannel    *string
+		NotifyTeams     *bool
+		TeamsWebhookURL *string
+		OrgID           *graphql.ID
+		UserID          *graphql.ID
 	}{Description: "test query", Query: "test type:diff patternType:regexp", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -184,16 +187,37 @@ func TestCreateSavedSearch(t *testing.T) {
 
 	// Ensure create saved search errors when patternType is not provided in the query.
 	_, err = (&schemaResolver{db: db}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description     string
+		Query           string
+		NotifyOwner     bool
+		NotifySlack     bool
+		SlackChannel    *string
+		NotifyTeams     *bool
+		TeamsWebhookURL *string
+		OrgID           *graphql.ID
+		UserID          *graphql.ID
 	}{Description: "test query", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for createSavedSearch when query does not provide a patternType: field.")
 	}
+
+	// Ensure create saved search errors when Microsoft Teams notifications
+	// are enabled without a webhook URL.
+	notifyTeams := true
+	_, err = (&schemaResolver{db: db}).CreateSavedSearch(ctx, &struct {
+		Description     string
+		Query           string
+		NotifyOwner     bool
+		NotifySlack     bool
+		SlackChannel    *string
+		NotifyTeams     *bool
+		TeamsWebhookURL *string
+		OrgID           *graphql.ID
+		UserID          *graphql.ID
+	}{Description: "test query", Query: "test type:diff patternType:regexp", NotifyTeams: &notifyTeams, UserID: &userID})
+	if err != errMissingTeamsWebhookURL {
+		t.Errorf("got error %v, want %v", err, errMissingTeamsWebhookURL)
+	}
 }
 
 func TestUpdateSavedSearch(t *testing.T) {
@@ -209,30 +233,46 @@ func TestUpdateSavedSearch(t *testing.T) {
 
 	database.Mocks.SavedSearches.Update = func(ctx context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error) {
 		updateSavedSearchCalled = true
-		return &types.SavedSearch{ID: key, Description: savedSearch.Description, Query: savedSearch.Query, Notify: savedSearch.Notify, NotifySlack: savedSearch.NotifySlack, UserID: savedSearch.UserID, OrgID: savedSearch.OrgID}, nil
+		return &types.SavedSearch{ID: key, Description: savedSearch.Description, Query: savedSearch.Query, Notify: savedSearch.Notify, NotifySlack: savedSearch.NotifySlack, UserID: savedSearch.UserID, OrgID: savedSearch.OrgID, SlackChannel: savedSearch.SlackChannel, NotifyTeams: savedSearch.NotifyTeams, TeamsWebhookURL: savedSearch.TeamsWebhookURL}, nil
+	}
+	slackChannel := "C0123456789"
+	teamsWebhookURL := "https://example.webhook.office.com/webhookb2/abc"
+	database.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		return &api.SavedQuerySpecAndConfig{
+			Spec:   api.SavedQueryIDSpec{Subject: api.SettingsSubject{User: &key}, Key: "1"},
+			Config: api.ConfigSavedQuery{Key: "1", Query: "test type:diff patternType:regexp", UserID: &key, SlackChannel: &slackChannel, NotifyTeams: true, TeamsWebhookURL: &teamsWebhookURL},
+		}, nil
 	}
 	userID := MarshalUserID(key)
 	savedSearches, err := (&schemaResolver{db: db}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID              graphql.ID
+		Description     string
+		Query           string
+		NotifyOwner     bool
+		NotifySlack     bool
+		SlackChannel    *string
+		NotifyTeams     *bool
+		TeamsWebhookURL *string
+		OrgID           *graphql.ID
+		UserID          *graphql.ID
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff patternType:regexp", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	// The Slack channel and Microsoft Teams settings are omitted, so the
+	// existing ones are kept.
 	want := &savedSearchResolver{db, types.SavedSearch{
-		ID:          key,
-		Description: "updated query description",
-		Query:       "test type:diff patternType:regexp",
-		Notify:      true,
-		NotifySlack: false,
-		OrgID:       nil,
-		UserID:      &key,
+		ID:              key,
+		Description:     "updated query description",
+		Query:           "test type:diff patternType:regexp",
+		Notify:          true,
+		NotifySlack:     false,
+		OrgID:           nil,
+		UserID:          &key,
+		SlackChannel:    &slackChannel,
+		NotifyTeams:     true,
+		TeamsWebhookURL: &teamsWebhookURL,
 	}}
 
 	if !updateSavedSearchCalled {
@@ -245,13 +285,16 @@ func TestUpdateSavedSearch(t *testing.T) {
 
 	// Ensure update saved search errors when patternType is not provided in the query.
 	_, err = (&schemaResolver{db: db}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID              graphql.ID
+		Description     string
+		Query           string
+		NotifyOwner     bool
+		NotifySlack     bool
+		SlackChannel    *string
+		NotifyTeams     *bool
+		TeamsWebhookURL *string
+		OrgID           *graphql.ID
+		UserID          *graphql.ID
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for updateSavedSearch when query does not provide a patternType: field.")
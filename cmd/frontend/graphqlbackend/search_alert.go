@@ -28,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/search/unindexed"
 )
 
 type searchAlert struct {
@@ -503,6 +504,7 @@ func alertForError(err error) *searchAlert {
 		rErr  *commit.RepoLimitError
 		tErr  *commit.TimeLimitError
 		mErr  *missingRepoRevsError
+		nErr  *unindexed.NegatedPatternLimitError
 	)
 
 	if errors.As(err, &mErr) {
@@ -522,6 +524,13 @@ func alertForError(err error) *searchAlert {
 			description:    `Running your structural search requires more memory. You could try reducing the number of repositories with the "repo:" filter. If you are an administrator, try double the memory allocated for the "searcher" service. If you're unsure, reach out to us at support@sourcegraph.com.`,
 			priority:       4,
 		}
+	} else if errors.As(err, &nErr) {
+		alert = &searchAlert{
+			prometheusType: "structural_search_negated_pattern_limit",
+			title:          "Too many files match the negated pattern",
+			description:    fmt.Sprintf(`Structural search can only exclude up to %d files matching a negated pattern. Try a more specific negated pattern, or narrow down which files to search with the "repo:" and "file:" filters.`, nErr.Max),
+			priority:       3,
+		}
 	} else if errors.As(err, &rErr) {
 		alert = &searchAlert{
 			prometheusType: "exceeded_diff_commit_search_limit",
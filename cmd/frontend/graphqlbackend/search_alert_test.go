@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
+	"github.com/sourcegraph/sourcegraph/internal/search/unindexed"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -211,6 +212,11 @@ func TestErrorToAlertStructuralSearch(t *testing.T) {
 			},
 			wantAlertTitle: "Structural search needs more memory",
 		},
+		{
+			name:           "surface_alert_on_negated_pattern_limit",
+			errors:         []error{&unindexed.NegatedPatternLimitError{Max: 10000}},
+			wantAlertTitle: "Too many files match the negated pattern",
+		},
 	}
 	for _, test := range cases {
 		multiErr := &multierror.Error{
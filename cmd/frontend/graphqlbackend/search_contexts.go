@@ -27,6 +27,7 @@ type SearchContextsResolver interface {
 	CreateSearchContext(ctx context.Context, args CreateSearchContextArgs) (SearchContextResolver, error)
 	UpdateSearchContext(ctx context.Context, args UpdateSearchContextArgs) (SearchContextResolver, error)
 	DeleteSearchContext(ctx context.Context, args DeleteSearchContextArgs) (*EmptyResponse, error)
+	SearchContextsFile(ctx context.Context) (string, error)
 
 	NodeResolvers() map[string]NodeByIDFunc
 	SearchContextsToResolvers(searchContexts []*types.SearchContext) []SearchContextResolver
@@ -43,6 +44,8 @@ type SearchContextResolver interface {
 	Namespace(ctx context.Context) (*NamespaceResolver, error)
 	ViewerCanManage(ctx context.Context) bool
 	Repositories(ctx context.Context) ([]SearchContextRepositoryRevisionsResolver, error)
+	Query() *string
+	Managed() bool
 }
 
 type SearchContextConnectionResolver interface {
@@ -61,12 +64,14 @@ type SearchContextInputArgs struct {
 	Description string
 	Public      bool
 	Namespace   *graphql.ID
+	Query       *string
 }
 
 type SearchContextEditInputArgs struct {
 	Name        string
 	Description string
 	Public      bool
+	Query       *string
 }
 
 type SearchContextRepositoryRevisionsInputArgs struct {
@@ -0,0 +1,47 @@
+package graphqlbackend
+
+import "github.com/sourcegraph/sourcegraph/internal/search/explain"
+
+// searchPlanNodeResolver resolves a node of the plan tree of a query with
+// explain:yes.
+type searchPlanNodeResolver struct {
+	node *explain.Node
+}
+
+func (r *searchPlanNodeResolver) Name() string { return r.node.Name }
+
+func (r *searchPlanNodeResolver) Query() *string {
+	if r.node.Query == "" {
+		return nil
+	}
+	return &r.node.Query
+}
+
+func (r *searchPlanNodeResolver) DurationMilliseconds() int32 { return int32(r.node.DurationMs) }
+
+func (r *searchPlanNodeResolver) Repositories() int32 { return int32(r.node.Repositories) }
+
+func (r *searchPlanNodeResolver) Shards() int32 { return int32(r.node.Shards) }
+
+func (r *searchPlanNodeResolver) SkippedShards() int32 { return int32(r.node.SkippedShards) }
+
+func (r *searchPlanNodeResolver) Matches() int32 { return int32(r.node.Matches) }
+
+func (r *searchPlanNodeResolver) TimedoutRepositories() int32 { return int32(r.node.Timedout) }
+
+func (r *searchPlanNodeResolver) LimitHit() bool { return r.node.LimitHit }
+
+func (r *searchPlanNodeResolver) Error() *string {
+	if r.node.Error == "" {
+		return nil
+	}
+	return &r.node.Error
+}
+
+func (r *searchPlanNodeResolver) Children() []*searchPlanNodeResolver {
+	children := make([]*searchPlanNodeResolver, 0, len(r.node.Children))
+	for _, child := range r.node.Children {
+		children = append(children, &searchPlanNodeResolver{child})
+	}
+	return children
+}
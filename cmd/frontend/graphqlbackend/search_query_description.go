@@ -19,6 +19,8 @@ func (q searchQueryDescription) Query() string {
 			return q.query + " patternType:literal"
 		case query.SearchTypeStructural:
 			return q.query + " patternType:structural"
+		case query.SearchTypeFuzzy:
+			return q.query + " patternType:fuzzy"
 		default:
 			panic("unreachable")
 		}
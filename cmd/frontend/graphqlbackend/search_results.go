@@ -34,14 +34,18 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeowners"
 	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
 	"github.com/sourcegraph/sourcegraph/internal/search/unindexed"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -112,6 +116,9 @@ type SearchResultsResolver struct {
 	// The time it took to compute all results.
 	elapsed time.Duration
 
+	// explanation is the plan tree of a query with explain:yes.
+	explanation *explain.Node
+
 	// cache for user settings. Ideally this should be set just once in the code path
 	// by an upstream resolver
 	UserSettings *schema.Settings
@@ -196,6 +203,18 @@ func (sr *SearchResultsResolver) ElapsedMilliseconds() int32 {
 	return int32(sr.elapsed.Milliseconds())
 }
 
+// Explanation returns the plan tree of a query with explain:yes, or nil.
+func (sr *SearchResultsResolver) Explanation() *explain.Node {
+	return sr.explanation
+}
+
+func (sr *SearchResultsResolver) Explain() *searchPlanNodeResolver {
+	if sr.explanation == nil {
+		return nil
+	}
+	return &searchPlanNodeResolver{sr.explanation}
+}
+
 func (sr *SearchResultsResolver) DynamicFilters(ctx context.Context) []*searchFilterResolver {
 	tr, ctx := trace.New(ctx, "DynamicFilters", "", trace.Tag{Key: "resolver", Value: "SearchResultsResolver"})
 	defer func() {
@@ -402,6 +421,8 @@ func LogSearchLatency(ctx context.Context, db dbutil.DB, si *run.SearchInputs, d
 				types = append(types, "literal")
 			case si.PatternType == query.SearchTypeRegex:
 				types = append(types, "regexp")
+			case si.PatternType == query.SearchTypeFuzzy:
+				types = append(types, "fuzzy")
 			}
 		}
 	}
@@ -501,6 +522,9 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	visibility := query.ParseVisibility(visibilityStr)
 
 	commitAfter, _ := q.StringValue(query.FieldRepoHasCommitAfter)
+	at, _ := q.StringValue(query.FieldAt)
+	topics, _ := q.StringValues(query.FieldRepoHasTopic)
+	descriptionPatterns, _ := q.RegexpPatterns(query.FieldRepoHasDescription)
 	searchContextSpec, _ := q.StringValue(query.FieldContext)
 
 	var CacheLookup bool
@@ -510,21 +534,24 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	}
 
 	return search.RepoOptions{
-		RepoFilters:       repoFilters,
-		MinusRepoFilters:  minusRepoFilters,
-		RepoGroupFilters:  repoGroupFilters,
-		SearchContextSpec: searchContextSpec,
-		UserSettings:      r.UserSettings,
-		OnlyForks:         fork == query.Only,
-		NoForks:           fork == query.No,
-		OnlyArchived:      archived == query.Only,
-		NoArchived:        archived == query.No,
-		Visibility:        visibility,
-		CommitAfter:       commitAfter,
-		Query:             q,
-		Ranked:            true,
-		Limit:             opts.limit,
-		CacheLookup:       CacheLookup,
+		RepoFilters:         repoFilters,
+		MinusRepoFilters:    minusRepoFilters,
+		RepoGroupFilters:    repoGroupFilters,
+		SearchContextSpec:   searchContextSpec,
+		UserSettings:        r.UserSettings,
+		OnlyForks:           fork == query.Only,
+		NoForks:             fork == query.No,
+		OnlyArchived:        archived == query.Only,
+		NoArchived:          archived == query.No,
+		Visibility:          visibility,
+		CommitAfter:         commitAfter,
+		Topics:              topics,
+		DescriptionPatterns: descriptionPatterns,
+		At:                  at,
+		Query:               q,
+		Ranked:              true,
+		Limit:               opts.limit,
+		CacheLookup:         CacheLookup,
 	}
 }
 
@@ -547,7 +574,10 @@ func withMode(args search.TextParameters, st query.SearchType) search.TextParame
 				return n.Negated
 			case
 				query.FieldRepoGroup,
-				query.FieldRepoHasFile:
+				query.FieldRepoHasFile,
+				query.FieldRepoHasTopic,
+				query.FieldRepoHasDescription,
+				query.FieldAt:
 				return false
 			default:
 				return true
@@ -592,6 +622,17 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 	if err != nil {
 		return nil, nil, err
 	}
+
+	var filters []*search.TextPatternInfo
+	if r.PatternType == query.SearchTypeStructural {
+		if structural, patterns, ok := b.SplitStructural(); ok {
+			b = structural
+			for _, pattern := range patterns {
+				filter := query.Basic{Parameters: b.Parameters, Pattern: pattern}
+				filters = append(filters, search.ToTextPatternInfo(filter, r.protocol(), query.Identity))
+			}
+		}
+	}
 	p := search.ToTextPatternInfo(b, r.protocol(), query.Identity)
 
 	forceResultTypes := result.TypeEmpty
@@ -602,10 +643,28 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 			r.PatternType = query.SearchTypeLiteral
 			p.IsStructuralPat = false
 			forceResultTypes = result.Types(0)
+		} else if !p.IsStructuralPat {
+			// A regexp or negated pattern which is searched on its own,
+			// like an operand of an expression evaluated with
+			// evaluateAnd.
+			forceResultTypes = result.TypeFile
+		} else if types, _ := q.StringValues(query.FieldType); len(types) > 0 {
+			// Validation only admits type:diff alongside a structural
+			// pattern. Run comby over the hunks of diff search.
+			forceResultTypes = result.TypeDiff
 		} else {
 			forceResultTypes = result.TypeStructural
 		}
 	}
+	if r.PatternType == query.SearchTypeFuzzy {
+		if p.Pattern == "" {
+			// Fallback to literal search for searching repos and files if
+			// the fuzzy search pattern is empty.
+			r.PatternType = query.SearchTypeLiteral
+		} else {
+			forceResultTypes = result.TypeFuzzy
+		}
+	}
 
 	args := search.TextParameters{
 		PatternInfo: p,
@@ -628,7 +687,7 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 		// It which specializes search logic in doResults. In time, all
 		// of the above logic should be used to create search jobs
 		// across all of Sourcegraph.
-		if r.PatternType == query.SearchTypeStructural && p.Pattern != "" {
+		if r.PatternType == query.SearchTypeStructural && forceResultTypes == result.TypeStructural {
 			jobs = append(jobs, &unindexed.StructuralSearch{
 				RepoFetcher: unindexed.NewRepoFetcher(r.stream, &args),
 				Mode:        args.Mode,
@@ -637,6 +696,14 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 					PatternInfo:     args.PatternInfo,
 					UseFullDeadline: args.UseFullDeadline,
 				},
+				Filters: filters,
+			})
+		}
+
+		if r.PatternType == query.SearchTypeFuzzy && forceResultTypes == result.TypeFuzzy {
+			jobs = append(jobs, &fuzzy.FileSearch{
+				Args:  &args,
+				Limit: r.MaxResults(),
 			})
 		}
 	}
@@ -647,9 +714,12 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 // evaluation of leaf expression in a query.
 func (r *searchResolver) evaluateLeaf(ctx context.Context, args *search.TextParameters, jobs []run.Job) (_ *SearchResults, err error) {
 	tr, ctx := trace.New(ctx, "evaluateLeaf", "")
+	ctx, node := explain.Start(ctx, "Leaf", query.StringHuman(args.Query))
+	node.SetRepositories(len(args.Repos))
 	defer func() {
 		tr.SetError(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
 	return r.resultsWithTimeoutSuggestion(ctx, args, jobs)
@@ -842,6 +912,16 @@ func (r *searchResolver) evaluatePatternExpression(ctx context.Context, q query.
 
 		switch term.Kind {
 		case query.And:
+			if _, _, ok := q.SplitStructural(); ok && r.PatternType == query.SearchTypeStructural {
+				// The regexp and negated patterns narrow down the
+				// files the structural search runs on.
+				r.invalidateCache()
+				args, jobs, err := r.toSearchInputs(q.ToParseTree())
+				if err != nil {
+					return &SearchResults{}, err
+				}
+				return r.evaluateLeaf(ctx, args, jobs)
+			}
 			return r.evaluateAnd(ctx, q)
 		case query.Or:
 			return r.evaluateOr(ctx, q)
@@ -989,7 +1069,15 @@ func (r *searchResolver) resultsStreaming(ctx context.Context) (*SearchResultsRe
 	if sp, _ := r.Plan.ToParseTree().StringValue(query.FieldSelect); sp != "" {
 		// Ensure downstream events sent on the stream are processed by `select:`.
 		selectPath, _ := filter.SelectPathFromString(sp) // Invariant: error already checked
+		if selectsOwners(r.Plan.ToParseTree()) {
+			// Resolve the owners of the files that remain after select:file.
+			r.stream = codeowners.WithOwners(ctx, r.stream)
+		}
 		r.stream = streaming.WithSelect(r.stream, selectPath)
+		if selectPath.Root() == filter.Symbol {
+			// Map content matches to their enclosing symbols before select.
+			r.stream = symbol.WithEnclosingSymbols(ctx, r.stream)
+		}
 	}
 	sr, err := r.resultsRecursive(ctx, r.Plan)
 	srr := r.resultsToResolver(sr)
@@ -1008,7 +1096,18 @@ func (r *searchResolver) resultsToResolver(results *SearchResults) *SearchResult
 	}
 }
 
-func (r *searchResolver) Results(ctx context.Context) (*SearchResultsResolver, error) {
+func (r *searchResolver) Results(ctx context.Context) (srr *SearchResultsResolver, err error) {
+	if r.Plan.ToParseTree().Explain() {
+		var plan *explain.Node
+		ctx, plan = explain.New(ctx, "Plan", query.StringHuman(r.Plan.ToParseTree()))
+		defer func() {
+			plan.Finish(err)
+			if srr != nil {
+				srr.explanation = plan
+			}
+		}()
+	}
+
 	if r.stream == nil {
 		return r.resultsBatch(ctx)
 	}
@@ -1051,7 +1150,7 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 	}
 
 	for _, q := range plan {
-		predicatePlan, err := substitutePredicates(q, func(pred query.Predicate) (*SearchResults, error) {
+		predicatePlan, err := substitutePredicates(ctx, q, func(pred query.Predicate) (*SearchResults, error) {
 			// Disable streaming for subqueries so we can use
 			// the results rather than sending them back to the caller
 			orig := r.stream
@@ -1077,14 +1176,27 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 			return r.resultsRecursive(ctx, predicatePlan)
 		}
 
-		newResult, err := r.evaluate(ctx, q)
+		qCtx, node := explain.Start(ctx, "Query", query.StringHuman(q.ToParseTree()))
+		newResult, err := r.evaluate(qCtx, q)
+		node.Finish(err)
 		if err != nil {
 			// Fail if any subexpression fails.
 			return nil, err
 		}
 
 		if newResult != nil {
+			if selectsSymbols(q.ToParseTree()) {
+				if err := symbol.AddEnclosingSymbols(ctx, newResult.Matches); err != nil {
+					return nil, err
+				}
+			}
 			newResult.Matches = result.Select(newResult.Matches, q)
+			if selectsOwners(q.ToParseTree()) {
+				newResult.Matches, err = codeowners.ToOwnerMatches(ctx, newResult.Matches)
+				if err != nil {
+					return nil, err
+				}
+			}
 			sr = union(sr, newResult)
 			if len(sr.Matches) > wantCount {
 				sr.Matches = sr.Matches[:wantCount]
@@ -1148,6 +1260,94 @@ func searchResultsToFileNodes(matches []result.Match) ([]query.Node, error) {
 	return nodes, nil
 }
 
+// searchResultsToOwnedFileNodes converts a set of CODEOWNERS file matches
+// into repo/file nodes matching the files owned by owner, so that they can
+// replace a file:has.owner() predicate.
+func searchResultsToOwnedFileNodes(ctx context.Context, matches []result.Match, owner string) ([]query.Node, error) {
+	// A repository may contain more than one CODEOWNERS file, in which case
+	// only the one with the highest precedence applies.
+	precedence := func(path string) int {
+		for i, p := range codeowners.Paths {
+			if p == path {
+				return i
+			}
+		}
+		return len(codeowners.Paths)
+	}
+	files := make(map[api.RepoID]*result.FileMatch)
+	var repos []api.RepoID
+	for _, match := range matches {
+		fileMatch, ok := match.(*result.FileMatch)
+		if !ok {
+			return nil, errors.Errorf("expected type %T, but got %T", &result.FileMatch{}, match)
+		}
+		prev, ok := files[fileMatch.Repo.ID]
+		if !ok {
+			repos = append(repos, fileMatch.Repo.ID)
+		}
+		if !ok || precedence(fileMatch.Path) < precedence(prev.Path) {
+			files[fileMatch.Repo.ID] = fileMatch
+		}
+	}
+
+	var nodes []query.Node
+	for _, id := range repos {
+		fileMatch := files[id]
+		rs, err := codeowners.LoadFile(ctx, fileMatch.Repo.Name, fileMatch.CommitID, fileMatch.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s in %s", fileMatch.Path, fileMatch.Repo.Name)
+		}
+
+		// Each conjunction is a path the owned files must match, followed by
+		// paths of later rules which override the owner.
+		for _, conjunction := range rs.OwnedPathRegexps(owner) {
+			operands := []query.Node{
+				query.Parameter{
+					Field: query.FieldRepo,
+					Value: "^" + regexp.QuoteMeta(string(fileMatch.Repo.Name)) + "$",
+				},
+				query.Parameter{
+					Field: query.FieldFile,
+					Value: conjunction[0],
+				},
+			}
+			for _, excluded := range conjunction[1:] {
+				operands = append(operands, query.Parameter{
+					Field:   query.FieldFile,
+					Value:   excluded,
+					Negated: true,
+				})
+			}
+			nodes = append(nodes, query.Operator{
+				Kind:     query.And,
+				Operands: operands,
+			})
+		}
+	}
+
+	return nodes, nil
+}
+
+// selectsOwners returns true if q selects the owners of matched files.
+func selectsOwners(q query.Q) bool {
+	v, _ := q.StringValue(query.FieldSelect)
+	if v == "" {
+		return false
+	}
+	sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
+	return sp.Root() == filter.File && len(sp) > 1 && sp[1] == filter.Owners
+}
+
+// selectsSymbols returns true if q selects symbols.
+func selectsSymbols(q query.Q) bool {
+	v, _ := q.StringValue(query.FieldSelect)
+	if v == "" {
+		return false
+	}
+	sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
+	return sp.Root() == filter.Symbol
+}
+
 // resultsWithTimeoutSuggestion calls doResults, and in case of deadline
 // exceeded returns a search alert with a did-you-mean link for the same
 // query with a longer timeout.
@@ -1175,7 +1375,7 @@ func (r *searchResolver) resultsWithTimeoutSuggestion(ctx context.Context, args
 
 // substitutePredicates replaces all the predicates in a query with their expanded form. The predicates
 // are expanded using the doExpand function.
-func substitutePredicates(q query.Basic, evaluate func(query.Predicate) (*SearchResults, error)) (query.Plan, error) {
+func substitutePredicates(ctx context.Context, q query.Basic, evaluate func(query.Predicate) (*SearchResults, error)) (query.Plan, error) {
 	var topErr error
 	success := false
 	newQ := query.MapParameter(q.ToParseTree(), func(field, value string, neg bool, ann query.Annotation) query.Node {
@@ -1212,7 +1412,11 @@ func substitutePredicates(q query.Basic, evaluate func(query.Predicate) (*Search
 				return nil
 			}
 		case query.FieldFile:
-			nodes, err = searchResultsToFileNodes(srr.Matches)
+			if p, ok := predicate.(*query.FileHasOwnerPredicate); ok {
+				nodes, err = searchResultsToOwnedFileNodes(ctx, srr.Matches, p.Owner)
+			} else {
+				nodes, err = searchResultsToFileNodes(srr.Matches)
+			}
 			if err != nil {
 				topErr = err
 				return nil
@@ -1455,10 +1659,24 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 		return &optionalWg
 	}
 
+	// Global searches start before repositories are resolved, so only other
+	// searches are looked up in the result cache. Streamed results are
+	// recorded in case they need to be cached.
+	resultCache := run.NewResultCache(conf.Get())
+	if args.Mode == search.ZoektGlobalSearch || explain.FromContext(ctx) != nil {
+		// Explained searches run every job to report what it searched.
+		resultCache = nil
+	}
+
 	// For streaming search we want to limit based on all results, not just
 	// per backend. This works better than batch based since we have higher
 	// defaults.
 	stream := r.stream
+	var recorder *run.ResultRecorder
+	if stream != nil && resultCache != nil {
+		recorder = run.NewResultRecorder(stream)
+		stream = recorder
+	}
 	if stream != nil {
 		var cancelOnLimit context.CancelFunc
 		ctx, stream, cancelOnLimit = streaming.WithLimit(ctx, stream, limit)
@@ -1593,6 +1811,27 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 	})
 	tr.LazyPrintf("sending first stats (repos %d, excluded repos %+v) - done", len(resolved.RepoSet), resolved.ExcludedRepos)
 
+	var cacheKey string
+	if resultCache != nil && len(resolved.MissingRepoRevs) == 0 {
+		key, err := resultCache.Key(ctx, args.Zoekt, args.Query, args.ResultTypes, limit, args.Repos)
+		if err != nil {
+			// The cache is an optimization, search without it.
+			tr.LazyPrintf("not caching results: %v", err)
+		}
+		cacheKey = key
+	}
+	if cacheKey != "" {
+		if matches, limitHit, ok := resultCache.Get(cacheKey); ok {
+			tr.LazyPrintf("serving %d matches from the result cache", len(matches))
+			agg.Send(streaming.SearchEvent{
+				Results: matches,
+				Stats:   streaming.Stats{IsLimitHit: limitHit},
+			})
+			hasStartedAllBackends = true
+			return r.toSearchResults(ctx, agg)
+		}
+	}
+
 	if args.ResultTypes.Has(result.TypeRepo) {
 		wg := waitGroup(true)
 		wg.Add(1)
@@ -1643,7 +1882,9 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 		}
 	}
 
-	if featureflag.FromContext(ctx).GetBoolOr("cc_commit_search", false) {
+	// Structural diff search runs comby over the diffs returned by git, which
+	// the gitserver commit search doesn't support yet.
+	if featureflag.FromContext(ctx).GetBoolOr("cc_commit_search", false) && !args.PatternInfo.IsStructuralPat {
 		addCommitSearch := func(diff bool) {
 			j, err := commit.NewSearchJob(args.Query, args.Repos, diff, int(args.PatternInfo.FileMatchLimit))
 			if err != nil {
@@ -1695,6 +1936,8 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 			return waitGroup(args.ResultTypes.Without(result.TypeCommit) == 0)
 		case "Structural":
 			return waitGroup(true)
+		case "Fuzzy":
+			return waitGroup(true)
 		default:
 			panic("unknown job name " + job.Name())
 		}
@@ -1724,9 +1967,27 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 	// Wait for remaining optional searches to finish or get cancelled.
 	optionalWg.Wait()
 
-	timer.Stop()
+	timeBudgetExceeded := !timer.Stop()
 
-	return r.toSearchResults(ctx, agg)
+	res, err = r.toSearchResults(ctx, agg)
+	if cacheKey != "" && !timeBudgetExceeded && shouldCacheResults(res, err) {
+		matches := res.Matches
+		if recorder != nil {
+			matches = recorder.Results()
+		}
+		resultCache.Set(cacheKey, matches, res.Stats.IsLimitHit)
+	}
+	return res, err
+}
+
+// shouldCacheResults returns true if res are all the results of a search, or
+// all results up to its limit.
+func shouldCacheResults(res *SearchResults, err error) bool {
+	if err != nil || res == nil || res.Alert != nil {
+		return false
+	}
+	return !res.Stats.IsIndexUnavailable &&
+		!res.Stats.Status.Any(search.RepoStatusCloning|search.RepoStatusMissing|search.RepoStatusTimedout)
 }
 
 // toSearchResults converts an Aggregator to SearchResults.
@@ -1826,9 +2087,11 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 			// or path names. We use ~ as the key for repo and
 			// paths,lexicographically last in ASCII.
 			return "~", "~", &r.Commit.Author.Date
+		case *result.OwnerMatch:
+			return string(r.Repo.Name), r.Handle, nil
 		}
 		// Unreachable.
-		panic("unreachable: compareSearchResults expects RepositoryResolver, FileMatchResolver, or CommitSearchResultResolver")
+		panic("unreachable: compareSearchResults expects RepositoryResolver, FileMatchResolver, CommitSearchResultResolver, or OwnerMatch")
 	}
 
 	arepo, afile, adate := sortKeys(left)
@@ -1847,6 +2110,10 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 }
 
 func (r *searchResolver) sortResults(results []result.Match) {
+	if r.PatternType == query.SearchTypeFuzzy {
+		// Fuzzy search results are already ranked by their score.
+		return
+	}
 	var exactPatterns map[string]struct{}
 	if getBoolPtr(r.UserSettings.SearchGlobbing, false) {
 		exactPatterns = r.getExactFilePatterns()
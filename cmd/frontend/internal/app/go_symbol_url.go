@@ -27,72 +27,129 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/gosrc"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/vfsutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // serveGoSymbolURL handles Go symbol URLs (e.g.,
 // https://sourcegraph.com/go/github.com/gorilla/mux/-/Vars) by
 // redirecting them to the file and line/column URL of the definition.
-func serveGoSymbolURL(w http.ResponseWriter, r *http.Request) error {
-	ctx := r.Context()
+func serveGoSymbolURL(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
 
-	spec, err := parseGoSymbolURLPath(r.URL.Path)
-	if err != nil {
-		return err
-	}
+		spec, err := parseGoSymbolURLPath(r.URL.Path)
+		if err != nil {
+			return err
+		}
 
-	dir, err := gosrc.ResolveImportPath(httpcli.ExternalDoer, spec.Pkg)
-	if err != nil {
-		return err
-	}
-	cloneURL := dir.CloneURL
+		modules, err := goModuleDependencies(ctx, db)
+		if err != nil {
+			return err
+		}
 
-	if cloneURL == "" || !strings.HasPrefix(cloneURL, "https://github.com") {
-		return errors.Errorf("non-github clone URL resolved for import path %s", spec.Pkg)
-	}
+		dir, err := gosrc.ResolveImportPath(httpcli.ExternalDoer, spec.Pkg, modules...)
+		if err != nil {
+			return err
+		}
 
-	repoName := api.RepoName(strings.TrimSuffix(strings.TrimPrefix(cloneURL, "https://"), ".git"))
-	repo, err := backend.Repos.GetByName(ctx, repoName)
-	if err != nil {
-		return err
-	}
+		// Packages of configured Go modules resolve to the synthetic repos of
+		// the modules, at the tag of the module version.
+		repoName, rev := dir.RepoName, ""
+		if repoName != "" {
+			rev = dir.Rev
+		} else {
+			cloneURL := dir.CloneURL
+			if cloneURL == "" || !strings.HasPrefix(cloneURL, "https://github.com") {
+				return errors.Errorf("non-github clone URL resolved for import path %s", spec.Pkg)
+			}
+			repoName = api.RepoName(strings.TrimSuffix(strings.TrimPrefix(cloneURL, "https://"), ".git"))
+		}
 
-	commitID, err := backend.Repos.ResolveRev(ctx, repo, "")
-	if err != nil {
-		return err
-	}
-	_ = commitID
+		repo, err := backend.Repos.GetByName(ctx, repoName)
+		if err != nil {
+			return err
+		}
 
-	vfs, err := repoVFS(r.Context(), repoName, commitID)
-	if err != nil {
-		return err
+		commitID, err := backend.Repos.ResolveRev(ctx, repo, rev)
+		if err != nil {
+			return err
+		}
+
+		vfs, err := repoVFS(r.Context(), repoName, commitID)
+		if err != nil {
+			return err
+		}
+
+		pkgPath := path.Join("/", dir.RepoPrefix, strings.TrimPrefix(dir.ImportPath, dir.ProjectRoot))
+		location, err := symbolLocation(r.Context(), vfs, commitID, spec, pkgPath)
+		if err != nil {
+			return err
+		}
+		if location == nil {
+			return &errcode.HTTPErr{
+				Status: http.StatusNotFound,
+				Err:    errors.New("symbol not found"),
+			}
+		}
+
+		uri, err := url.Parse(string(location.URI))
+		if err != nil {
+			return err
+		}
+		filePath := uri.Fragment
+		dest := &url.URL{
+			Path:     "/" + path.Join(repoRev(repo.Name, rev), "-/blob", filePath),
+			Fragment: fmt.Sprintf("L%d:%d$references", location.Range.Start.Line+1, location.Range.Start.Character+1),
+		}
+		http.Redirect(w, r, dest.String(), http.StatusFound)
+		return nil
 	}
+}
 
-	pkgPath := path.Join("/", dir.RepoPrefix, strings.TrimPrefix(dir.ImportPath, dir.ProjectRoot))
-	location, err := symbolLocation(r.Context(), vfs, commitID, spec, pkgPath)
+// goModuleDependencies returns the dependencies of all Go modules code host
+// connections.
+func goModuleDependencies(ctx context.Context, db dbutil.DB) ([]reposource.GoDependency, error) {
+	svcs, err := database.ExternalServices(db).List(ctx, database.ExternalServicesListOptions{
+		Kinds: []string{extsvc.KindGoModules},
+	})
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "list")
 	}
-	if location == nil {
-		return &errcode.HTTPErr{
-			Status: http.StatusNotFound,
-			Err:    errors.New("symbol not found"),
+
+	var dependencies []reposource.GoDependency
+	for _, svc := range svcs {
+		cfg, err := extsvc.ParseConfig(svc.Kind, svc.Config)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse config")
+		}
+		c, ok := cfg.(*schema.GoModulesConnection)
+		if !ok {
+			return nil, errors.Errorf("want *schema.GoModulesConnection but got %T", cfg)
+		}
+		for _, dep := range c.Dependencies {
+			dependency, err := reposource.ParseGoDependency(dep)
+			if err != nil {
+				// Invalid dependencies are reported by the repo syncer.
+				continue
+			}
+			dependencies = append(dependencies, dependency)
 		}
 	}
+	return dependencies, nil
+}
 
-	uri, err := url.Parse(string(location.URI))
-	if err != nil {
-		return err
-	}
-	filePath := uri.Fragment
-	dest := &url.URL{
-		Path:     "/" + path.Join(string(repo.Name), "-/blob", filePath),
-		Fragment: fmt.Sprintf("L%d:%d$references", location.Range.Start.Line+1, location.Range.Start.Character+1),
+func repoRev(name api.RepoName, rev string) string {
+	if rev == "" {
+		return string(name)
 	}
-	http.Redirect(w, r, dest.String(), http.StatusFound)
-	return nil
+	return string(name) + "@" + rev
 }
 
 type goSymbolSpec struct {
@@ -248,6 +305,10 @@ func repoVFS(ctx context.Context, name api.RepoName, rev api.CommitID) (ctxvfs.F
 	if strings.HasPrefix(string(name), "github.com/") {
 		return vfsutil.NewGitHubRepoVFS(string(name), string(rev))
 	}
+	if strings.HasPrefix(string(name), "go/") {
+		// The repos of Go modules only exist on gitserver.
+		return vfsutil.NewGitServer(name, rev), nil
+	}
 
 	// Fall back to a full git clone for non-github.com repos.
 	return nil, errors.Errorf("unable to fetch repo %s (only github.com repos are supported)", name)
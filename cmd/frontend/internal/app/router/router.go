@@ -5,10 +5,13 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 )
 
 const (
@@ -100,7 +103,7 @@ func newRouter() *mux.Router {
 	base.Path("/site-admin/pings/latest").Methods("GET").Name(LatestPing)
 
 	if envvar.SourcegraphDotComMode() {
-		base.PathPrefix("/go/").Methods("GET").Name(GoSymbolURL)
+		base.PathPrefix("/go/").Methods("GET").MatcherFunc(isGoSymbolURL).Name(GoSymbolURL)
 	}
 
 	repoPath := `/` + routevar.Repo
@@ -112,3 +115,12 @@ func newRouter() *mux.Router {
 
 	return base
 }
+
+// goSymbolURLPath matches Go symbol URLs, which refer to exported
+// identifiers. All other paths under /go/ are pages of the repos of Go
+// modules, like /go/golang.org/x/net/-/blob/go.mod.
+var goSymbolURLPath = lazyregexp.New(`^/go/.+/-/[A-Z]\w*(/\w+)?$`)
+
+func isGoSymbolURL(r *http.Request, _ *mux.RouteMatch) bool {
+	return goSymbolURLPath.MatchString(r.URL.Path)
+}
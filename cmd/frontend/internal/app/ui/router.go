@@ -80,6 +80,7 @@ const (
 	routeSearchStream   = "search.stream"
 	routeSearchConsole  = "search.console"
 	routeSearchNotebook = "search.notebook"
+	routeComputeStream  = "compute.stream"
 
 	// Legacy redirects
 	routeLegacyLogin                   = "login"
@@ -136,6 +137,7 @@ func newRouter() *mux.Router {
 	r.Path("/search/stream").Methods("GET").Name(routeSearchStream)
 	r.Path("/search/console").Methods("GET").Name(routeSearchConsole)
 	r.Path("/search/notebook").Methods("GET").Name(routeSearchNotebook)
+	r.Path("/compute/stream").Methods("GET").Name(routeComputeStream)
 	r.Path("/sign-in").Methods("GET").Name(uirouter.RouteSignIn)
 	r.Path("/sign-up").Methods("GET").Name(uirouter.RouteSignUp)
 	r.Path("/welcome").Methods("GET").Name(routeWelcome)
@@ -287,6 +289,9 @@ func initRouter(db dbutil.DB, router *mux.Router, codeIntelResolver graphqlbacke
 	// streaming search
 	router.Get(routeSearchStream).Handler(search.StreamHandler(db))
 
+	// streaming compute
+	router.Get(routeComputeStream).Handler(search.ComputeStreamHandler(db))
+
 	// search badge
 	router.Get(routeSearchBadge).Handler(searchBadgeHandler())
 
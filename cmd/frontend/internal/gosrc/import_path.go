@@ -8,7 +8,10 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	"golang.org/x/mod/semver"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 )
@@ -20,17 +23,27 @@ import (
 var RuntimeVersion = runtime.Version()
 
 type Directory struct {
-	ImportPath  string // the Go import path for this package
-	ProjectRoot string // import path prefix for all packages in the project
-	CloneURL    string // the VCS clone URL
-	RepoPrefix  string // the path to this directory inside the repo, if set
-	VCS         string // one of "git", "hg", "svn", etc.
-	Rev         string // the VCS revision specifier, if any
+	ImportPath  string       // the Go import path for this package
+	ProjectRoot string       // import path prefix for all packages in the project
+	CloneURL    string       // the VCS clone URL
+	RepoPrefix  string       // the path to this directory inside the repo, if set
+	VCS         string       // one of "git", "hg", "svn", etc.
+	Rev         string       // the VCS revision specifier, if any
+	RepoName    api.RepoName // the name of the synthetic Go module repo, if any
 }
 
 var errNoMatch = errors.New("no match")
 
-func ResolveImportPath(client httpcli.Doer, importPath string) (*Directory, error) {
+// ResolveImportPath resolves the repository of the package with the given
+// import path. Packages that are contained in one of the given modules
+// resolve to the synthetic repository of the module, which is synced from a
+// Go module proxy.
+func ResolveImportPath(client httpcli.Doer, importPath string, modules ...reposource.GoDependency) (*Directory, error) {
+	if !IsStdlibPkg(importPath) {
+		if d := resolveModuleImportPath(importPath, modules); d != nil {
+			return d, nil
+		}
+	}
 	if d, err := resolveStaticImportPath(importPath); err == nil {
 		return d, nil
 	} else if err != errNoMatch {
@@ -39,6 +52,34 @@ func ResolveImportPath(client httpcli.Doer, importPath string) (*Directory, erro
 	return resolveDynamicImportPath(client, importPath)
 }
 
+// resolveModuleImportPath returns the directory of the package in the
+// synthetic repository of the innermost module that contains it, or nil if
+// no module contains the package. If the module is given in multiple
+// versions, the latest version is used.
+func resolveModuleImportPath(importPath string, modules []reposource.GoDependency) *Directory {
+	var match *reposource.GoDependency
+	for i, m := range modules {
+		if !m.Contains(importPath) {
+			continue
+		}
+		if match == nil || len(m.Path) > len(match.Path) ||
+			(m.Path == match.Path && semver.Compare(m.Version, match.Version) > 0) {
+			match = &modules[i]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return &Directory{
+		ImportPath:  importPath,
+		ProjectRoot: match.Path,
+		CloneURL:    match.CloneURL(),
+		VCS:         "git",
+		Rev:         match.GitTagFromVersion(),
+		RepoName:    match.RepoName(),
+	}
+}
+
 func resolveStaticImportPath(importPath string) (*Directory, error) {
 	if IsStdlibPkg(importPath) {
 		return &Directory{
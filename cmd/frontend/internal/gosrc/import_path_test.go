@@ -13,6 +13,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 )
 
 type testTransport map[string]string
@@ -223,3 +225,91 @@ func TestResolveImportPath(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveImportPathModules(t *testing.T) {
+	var modules []reposource.GoDependency
+	for _, dep := range []string{
+		"golang.org/x/net@v0.0.1",
+		"golang.org/x/net@v0.0.2",
+		"alice.org/pkg@v1.0.0",
+		"alice.org/pkg/sub@v0.1.0",
+	} {
+		module, err := reposource.ParseGoDependency(dep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		modules = append(modules, module)
+	}
+
+	tests := []struct {
+		importPath string
+		dir        *Directory
+	}{
+		// stdlib packages are never part of a module
+		{"fmt", &Directory{
+			ImportPath:  "fmt",
+			ProjectRoot: "",
+			CloneURL:    "https://github.com/golang/go",
+			RepoPrefix:  "src",
+			VCS:         "git",
+			Rev:         runtime.Version(),
+		}},
+		// the latest version of a module is used
+		{"golang.org/x/net/html", &Directory{
+			ImportPath:  "golang.org/x/net/html",
+			ProjectRoot: "golang.org/x/net",
+			CloneURL:    "go/golang.org/x/net",
+			VCS:         "git",
+			Rev:         "v0.0.2",
+			RepoName:    "go/golang.org/x/net",
+		}},
+		// the innermost module is used
+		{"alice.org/pkg/sub/http", &Directory{
+			ImportPath:  "alice.org/pkg/sub/http",
+			ProjectRoot: "alice.org/pkg/sub",
+			CloneURL:    "go/alice.org/pkg/sub",
+			VCS:         "git",
+			Rev:         "v0.1.0",
+			RepoName:    "go/alice.org/pkg/sub",
+		}},
+		{"alice.org/pkg", &Directory{
+			ImportPath:  "alice.org/pkg",
+			ProjectRoot: "alice.org/pkg",
+			CloneURL:    "go/alice.org/pkg",
+			VCS:         "git",
+			Rev:         "v1.0.0",
+			RepoName:    "go/alice.org/pkg",
+		}},
+		// a module path is not a string prefix of an import path
+		{"alice.org/pkgs", nil},
+		// packages of other modules are resolved as usual
+		{"github.com/foo/bar", &Directory{
+			ImportPath:  "github.com/foo/bar",
+			ProjectRoot: "github.com/foo/bar",
+			CloneURL:    "https://github.com/foo/bar",
+			VCS:         "git",
+		}},
+	}
+
+	client := &http.Client{Transport: testTransport(map[string]string{})}
+
+	for _, tt := range tests {
+		dir, err := ResolveImportPath(client, tt.importPath, modules...)
+
+		if tt.dir == nil {
+			if err == nil {
+				t.Errorf("resolveImportPath(client, %q) did not return expected error", tt.importPath)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("resolveImportPath(client, %q) return unexpected error: %v", tt.importPath, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(dir, tt.dir) {
+			t.Errorf("resolveImportPath(client, %q) =\n     %+v,\nwant %+v", tt.importPath, dir, tt.dir)
+		}
+	}
+}
@@ -254,6 +254,63 @@ func serveSavedQueriesDeleteInfo(db dbutil.DB) func(w http.ResponseWriter, r *ht
 	}
 }
 
+func serveSavedQueriesGetCursor(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var key string
+		err := json.NewDecoder(r.Body).Decode(&key)
+		if err != nil {
+			return errors.Wrap(err, "Decode")
+		}
+		id, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			return errors.Wrap(err, "ParseInt")
+		}
+		cursor, err := database.QueryRunnerState(db).GetCursor(r.Context(), int32(id))
+		if err != nil {
+			return errors.Wrap(err, "SavedQueries.GetCursor")
+		}
+		var result *api.SavedSearchCursor
+		if cursor != nil {
+			result = &api.SavedSearchCursor{
+				Key:           key,
+				LatestResult:  cursor.LatestResult,
+				SeenResults:   cursor.SeenResults,
+				SlackThreadTS: cursor.SlackThreadTS,
+			}
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			return errors.Wrap(err, "Encode")
+		}
+		return nil
+	}
+}
+
+func serveSavedQueriesSetCursor(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var cursor *api.SavedSearchCursor
+		err := json.NewDecoder(r.Body).Decode(&cursor)
+		if err != nil {
+			return errors.Wrap(err, "Decode")
+		}
+		id, err := strconv.ParseInt(cursor.Key, 10, 32)
+		if err != nil {
+			return errors.Wrap(err, "ParseInt")
+		}
+		err = database.QueryRunnerState(db).SetCursor(r.Context(), &database.SavedSearchCursor{
+			SavedSearchID: int32(id),
+			LatestResult:  cursor.LatestResult,
+			SeenResults:   cursor.SeenResults,
+			SlackThreadTS: cursor.SlackThreadTS,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SavedQueries.SetCursor")
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("OK"))
+		return nil
+	}
+}
+
 func serveSettingsGetForSubject(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) error {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		var subject api.SettingsSubject
@@ -405,6 +462,8 @@ func serveGitTar(w http.ResponseWriter, r *http.Request) error {
 		Format:  "tar",
 	}
 
+	// The archive is served by the first healthy replica of repo. Resolving
+	// the revision above already failed over if the primary is unavailable.
 	location := gitserver.DefaultClient.ArchiveURL(repo, opts)
 
 	w.Header().Set("Location", location.String())
@@ -441,10 +500,7 @@ func serveGitExec(db dbutil.DB) func(http.ResponseWriter, *http.Request) error {
 			return errors.Wrap(err, "Encode")
 		}
 
-		// Find the correct shard to query
-		addr := gitserver.DefaultClient.AddrForRepo(repo.Name)
-
-		director := func(req *http.Request) {
+		director := func(req *http.Request, addr string) {
 			req.URL.Scheme = "http"
 			req.URL.Host = addr
 			req.URL.Path = "/exec"
@@ -452,16 +508,19 @@ func serveGitExec(db dbutil.DB) func(http.ResponseWriter, *http.Request) error {
 			req.ContentLength = int64(buf.Len())
 		}
 
-		gitserver.DefaultReverseProxy.ServeHTTP(repo.Name, "POST", "exec", director, w, r)
+		// Only commands which don't change the repo fail over to its replicas.
+		failover := gitserver.IsReadOnlyGitCommand(req.Args)
+		gitserver.DefaultReverseProxy.ServeHTTPFailover(gitserver.DefaultClient, repo.Name, "POST", "exec", failover, director, w, r)
 		return nil
 	}
 }
 
 // gitServiceHandler are handlers which redirect git clone requests to the
-// gitserver for the repo.
+// gitserver for the repo. Clones are read-only, so they are redirected to the
+// first healthy replica of the repo.
 type gitServiceHandler struct {
 	Gitserver interface {
-		AddrForRepo(api.RepoName) string
+		ReadAddrsForRepo(api.RepoName) []string
 	}
 }
 
@@ -478,7 +537,7 @@ func (s *gitServiceHandler) redirectToGitServer(w http.ResponseWriter, r *http.R
 
 	u := &url.URL{
 		Scheme:   "http",
-		Host:     s.Gitserver.AddrForRepo(api.RepoName(repo)),
+		Host:     s.Gitserver.ReadAddrsForRepo(api.RepoName(repo))[0],
 		Path:     path.Join("/git", repo, gitPath),
 		RawQuery: r.URL.RawQuery,
 	}
@@ -48,6 +48,6 @@ func TestGitServiceHandlers(t *testing.T) {
 
 type mockAddrForRepo struct{}
 
-func (mockAddrForRepo) AddrForRepo(name api.RepoName) string {
-	return strings.ReplaceAll(string(name), "/", ".") + ".gitserver"
+func (mockAddrForRepo) ReadAddrsForRepo(name api.RepoName) []string {
+	return []string{strings.ReplaceAll(string(name), "/", ".") + ".gitserver", "replica.gitserver"}
 }
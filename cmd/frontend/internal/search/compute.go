@@ -0,0 +1,266 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/compute"
+	computehttp "github.com/sourcegraph/sourcegraph/internal/compute/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// ComputeStreamHandler is an http handler which streams back the results of
+// a compute query as the underlying search streams in.
+func ComputeStreamHandler(db dbutil.DB) http.Handler {
+	return &computeStreamHandler{
+		streamHandler: &streamHandler{
+			db:                  db,
+			newSearchResolver:   defaultNewSearchResolver,
+			flushTickerInternal: 100 * time.Millisecond,
+			pingTickerInterval:  5 * time.Second,
+		},
+	}
+}
+
+type computeStreamHandler struct {
+	*streamHandler
+}
+
+func (h *computeStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "no query found", http.StatusBadRequest)
+		return
+	}
+	computeQuery, err := compute.Parse(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var aggregator *compute.Aggregator
+	switch computeQuery.(type) {
+	case *compute.MatchOnly, *compute.Output:
+	case *compute.CountBy, *compute.GroupBy:
+		if aggregator, err = compute.NewAggregator(computeQuery); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "unsupported compute query "+computeQuery.String(), http.StatusBadRequest)
+		return
+	}
+
+	tr, ctx := trace.New(ctx, "compute.ServeStream", q)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	eventWriter, err := streamhttp.NewWriter(w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Always send a final done event so clients know the stream is shutting
+	// down.
+	defer eventWriter.Event("done", map[string]interface{}{})
+
+	// Log events to trace
+	eventWriter.StatHook = eventStreamOTHook(tr.LogFields)
+
+	events, inputs, results := h.startSearch(ctx, &args{
+		Query:       compute.AggregationSearchQuery(q),
+		Version:     "V2",
+		PatternType: "regexp",
+	})
+	events = batchEvents(events, 50*time.Millisecond)
+
+	progress := progressAggregator{
+		Start:        time.Now(),
+		Limit:        inputs.MaxResults(),
+		Trace:        trace.URL(trace.ID(ctx)),
+		DisplayLimit: inputs.MaxResults(),
+	}
+
+	sendProgress := func() {
+		_ = eventWriter.Event("progress", progress.Current())
+	}
+
+	// Aggregates are sent as a snapshot of all aggregates computed so far,
+	// whenever they changed since the last snapshot.
+	aggregatesDirty := false
+	sendAggregates := func() {
+		if aggregator == nil {
+			return
+		}
+		aggregates := aggregator.Aggregates()
+		buf := make([]computehttp.EventAggregate, 0, len(aggregates))
+		for _, a := range aggregates {
+			buf = append(buf, computehttp.EventAggregate{Value: a.Value, Count: a.Count})
+		}
+		_ = eventWriter.Event("aggregates", &computehttp.EventAggregates{
+			Aggregates: buf,
+			LimitHit:   progress.Stats.IsLimitHit,
+		})
+		aggregatesDirty = false
+	}
+
+	// Store marshalled results and flush periodically or when we go over
+	// 32kb, like the search stream does with its matches.
+	resultsBuf := streamhttp.NewJSONArrayBuf(32*1024, func(data []byte) error {
+		return eventWriter.EventBytes("results", data)
+	})
+	resultsFlush := func() {
+		if err := resultsBuf.Flush(); err != nil {
+			// EOF
+			return
+		}
+
+		if aggregatesDirty {
+			sendAggregates()
+		}
+
+		if progress.Dirty {
+			sendProgress()
+		}
+	}
+	flushTicker := time.NewTicker(h.flushTickerInternal)
+	defer flushTicker.Stop()
+
+	pingTicker := time.NewTicker(h.pingTickerInterval)
+	defer pingTicker.Stop()
+
+	handleEvent := func(event streaming.SearchEvent) {
+		progress.Update(event)
+
+		repoMetadata, err := getEventRepoMetadata(ctx, h.db, event)
+		if err != nil {
+			log15.Error("failed to get repo metadata", "error", err)
+			return
+		}
+		for _, match := range event.Results {
+			// Don't send results for matches which we cannot map to a repo
+			// the actor has access to. See streamHandler.ServeHTTP.
+			if md, ok := repoMetadata[match.RepoName().ID]; !ok || md.Name != match.RepoName().Name {
+				continue
+			}
+
+			if aggregator != nil {
+				if fm, ok := match.(*result.FileMatch); ok {
+					aggregator.Add(fm)
+					aggregatesDirty = true
+				}
+				continue
+			}
+
+			computeResult, err := toComputeResult(ctx, computeQuery, match)
+			if err != nil {
+				log15.Error("failed to compute result", "error", err)
+				continue
+			}
+			if computeResult != nil {
+				_ = resultsBuf.Append(computeResult)
+			}
+		}
+	}
+
+LOOP:
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				break LOOP
+			}
+			handleEvent(event)
+		case <-flushTicker.C:
+			resultsFlush()
+		case <-pingTicker.C:
+			sendProgress()
+		}
+	}
+
+	resultsFlush()
+
+	resultsResolver, err := results()
+	if err != nil {
+		sendAggregates()
+		_ = eventWriter.Event("error", computehttp.EventError{Message: err.Error()})
+		return
+	}
+
+	// Like the GraphQL API, report the limit as hit if either the streamed
+	// stats or the final results say so.
+	if resultsResolver.LimitHit() {
+		progress.Stats.IsLimitHit = true
+	}
+	sendAggregates()
+
+	progress.Explain = resultsResolver.Explanation()
+	_ = eventWriter.Event("progress", progress.Final())
+}
+
+// toComputeResult returns the result of the non-aggregating compute query q
+// for match, or nil if q has no result for it.
+func toComputeResult(ctx context.Context, q compute.Query, match result.Match) (computehttp.EventResult, error) {
+	switch n := q.(type) {
+	case *compute.MatchOnly:
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			return nil, nil
+		}
+		mc := compute.FromFileMatch(fm, n.MatchPattern.(*compute.Regexp).Value)
+		if len(mc.Matches) == 0 {
+			return nil, nil
+		}
+		return &computehttp.EventMatchContext{
+			Type:         computehttp.MatchContextResultType,
+			Repository:   string(fm.Repo.Name),
+			RepositoryID: int32(fm.Repo.ID),
+			Commit:       string(fm.CommitID),
+			Path:         fm.Path,
+			Matches:      mc.Matches,
+		}, nil
+
+	case *compute.Output:
+		switch v := match.(type) {
+		case *result.FileMatch:
+			text, err := compute.OutputFromFileMatch(ctx, v, n)
+			if err != nil || text == nil {
+				return nil, err
+			}
+			return toEventText(v.Repo, string(v.CommitID), v.Path, text), nil
+		case *result.CommitMatch:
+			text, err := compute.OutputFromCommitMatch(ctx, v, n)
+			if err != nil || text == nil {
+				return nil, err
+			}
+			return toEventText(v.Repo, string(v.Commit.ID), "", text), nil
+		}
+	}
+	return nil, nil
+}
+
+func toEventText(repo types.RepoName, commit, path string, text *compute.Text) *computehttp.EventText {
+	return &computehttp.EventText{
+		Type:         computehttp.TextResultType,
+		Repository:   string(repo.Name),
+		RepositoryID: int32(repo.ID),
+		Commit:       commit,
+		Path:         path,
+		Kind:         text.Kind,
+		Value:        text.Value,
+	}
+}
@@ -0,0 +1,85 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+// toExportRows converts a match to the rows it is exported as.
+func toExportRows(match result.Match) []streamhttp.ExportRow {
+	switch v := match.(type) {
+	case *result.FileMatch:
+		return fileMatchToExportRows(v)
+	case *result.RepoMatch:
+		return []streamhttp.ExportRow{{
+			Type:       "repo",
+			Repository: string(v.Name),
+			Revision:   v.Rev,
+		}}
+	case *result.CommitMatch:
+		typ := "commit"
+		if v.DiffPreview != nil {
+			typ = "diff"
+		}
+		return []streamhttp.ExportRow{{
+			Type:       typ,
+			Repository: string(v.Repo.Name),
+			Revision:   string(v.Commit.ID),
+			Preview:    v.Commit.Message.Subject(),
+		}}
+	case *result.OwnerMatch:
+		return []streamhttp.ExportRow{{
+			Type:       "owner",
+			Repository: string(v.Repo.Name),
+			Revision:   exportRevision(v.InputRev, v.CommitID),
+			Preview:    v.Handle,
+		}}
+	default:
+		panic(fmt.Sprintf("unknown match type %T", v))
+	}
+}
+
+func fileMatchToExportRows(fm *result.FileMatch) []streamhttp.ExportRow {
+	row := streamhttp.ExportRow{
+		Repository: string(fm.Repo.Name),
+		Revision:   exportRevision(fm.InputRev, fm.CommitID),
+		Path:       fm.Path,
+	}
+
+	if len(fm.Symbols) > 0 {
+		rows := make([]streamhttp.ExportRow, 0, len(fm.Symbols))
+		for _, sym := range fm.Symbols {
+			row.Type = "symbol"
+			row.LineNumber = int32(sym.Symbol.Line)
+			row.Preview = sym.Symbol.Name
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	if len(fm.LineMatches) > 0 {
+		rows := make([]streamhttp.ExportRow, 0, len(fm.LineMatches))
+		for _, lm := range fm.LineMatches {
+			row.Type = "content"
+			row.LineNumber = lm.LineNumber + 1
+			row.Preview = lm.Preview
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	row.Type = "path"
+	return []streamhttp.ExportRow{row}
+}
+
+// exportRevision returns the revision the user asked for if any, otherwise
+// the commit that was searched.
+func exportRevision(inputRev *string, commitID api.CommitID) string {
+	if inputRev != nil && *inputRev != "" {
+		return *inputRev
+	}
+	return string(commitID)
+}
@@ -6,6 +6,7 @@ import (
 
 	sgapi "github.com/sourcegraph/sourcegraph/internal/api"
 	searchshared "github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 )
@@ -16,7 +17,8 @@ type progressAggregator struct {
 	Stats        streaming.Stats
 	Limit        int
 	DisplayLimit int
-	Trace        string // may be empty
+	Trace        string        // may be empty
+	Explain      *explain.Node // only set once the search is done
 
 	// Dirty is true if p has changed since the last call to Current.
 	Dirty bool
@@ -55,6 +57,7 @@ func (p *progressAggregator) currentStats() api.ProgressStats {
 		SuggestedLimit:      suggestedLimit,
 		Trace:               p.Trace,
 		DisplayLimit:        p.DisplayLimit,
+		Explain:             p.Explain,
 	}
 }
 
@@ -69,6 +69,11 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		tr.Finish()
 	}()
 
+	if args.Export != "" {
+		err = h.serveExport(ctx, w, args)
+		return
+	}
+
 	eventWriter, err := streamhttp.NewWriter(w)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -244,6 +249,7 @@ LOOP:
 		})
 	}
 
+	progress.Explain = resultsResolver.Explanation()
 	_ = eventWriter.Event("progress", progress.Final())
 
 	var status, alertType string
@@ -275,6 +281,47 @@ LOOP:
 	}
 }
 
+// serveExport writes every match of the search as a row of CSV or JSON lines.
+// Unlike the event stream, it ignores the display limit. The number of
+// results is only bounded by count:.
+func (h *streamHandler) serveExport(ctx context.Context, w http.ResponseWriter, args *args) error {
+	exportWriter, err := streamhttp.NewExportWriter(w, args.Export)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	events, _, results := h.startSearch(ctx, args)
+	events = batchEvents(events, 50*time.Millisecond)
+
+	for event := range events {
+		repoMetadata, err := getEventRepoMetadata(ctx, h.db, event)
+		if err != nil {
+			log15.Error("failed to get repo metadata", "error", err)
+			continue
+		}
+		for _, match := range event.Results {
+			// Don't export matches which we cannot map to a repo the actor
+			// has access to. See ServeHTTP.
+			if md, ok := repoMetadata[match.RepoName().ID]; !ok || md.Name != match.RepoName().Name {
+				continue
+			}
+			for _, row := range toExportRows(match) {
+				// Errors mean the client went away. We keep consuming events
+				// so that the search can shut down.
+				_ = exportWriter.Write(row)
+			}
+		}
+		_ = exportWriter.Flush()
+	}
+
+	if _, err := results(); err != nil {
+		exportWriter.Error(err)
+		return err
+	}
+	return exportWriter.Flush()
+}
+
 // startSearch will start a search. It returns the events channel which
 // streams out search events. Once events is closed you can call results which
 // will return the results resolver and error.
@@ -331,6 +378,10 @@ type args struct {
 	PatternType string
 	Display     int
 
+	// Export is the format to export all results in instead of streaming
+	// events. See streamhttp.ExportCSV and streamhttp.ExportJSONL.
+	Export string
+
 	// Optional decoration parameters for server-side rendering a result set
 	// or subset. Decorations may specify, e.g., highlighting results with
 	// HTML markup up-front, and/or including context lines around file results.
@@ -353,12 +404,17 @@ func parseURLQuery(q url.Values) (*args, error) {
 		Version:        get("v", "V2"),
 		PatternType:    get("t", ""),
 		DecorationKind: get("dk", "html"),
+		Export:         get("export", ""),
 	}
 
 	if a.Query == "" {
 		return nil, errors.New("no query found")
 	}
 
+	if a.Export != "" && !streamhttp.IsValidExportFormat(a.Export) {
+		return nil, errors.Errorf("export must be one of %q or %q, got %q", streamhttp.ExportCSV, streamhttp.ExportJSONL, a.Export)
+	}
+
 	display := get("display", "-1")
 	var err error
 	if a.Display, err = strconv.Atoi(display); err != nil {
@@ -419,6 +475,8 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo)
 		return fromRepository(v, repoCache)
 	case *result.CommitMatch:
 		return fromCommit(v, repoCache)
+	case *result.OwnerMatch:
+		return fromOwner(v, repoCache)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -575,6 +633,28 @@ func fromCommit(commit *result.CommitMatch, repoCache map[api.RepoID]*types.Sear
 	return commitEvent
 }
 
+func fromOwner(om *result.OwnerMatch, repoCache map[api.RepoID]*types.SearchedRepo) *streamhttp.EventOwnerMatch {
+	ownerEvent := &streamhttp.EventOwnerMatch{
+		Type:         streamhttp.OwnerMatchType,
+		Handle:       om.Handle,
+		FileCount:    om.FileCount,
+		Repository:   string(om.Repo.Name),
+		RepositoryID: int32(om.Repo.ID),
+		Commit:       string(om.CommitID),
+	}
+
+	if r, ok := repoCache[om.Repo.ID]; ok {
+		ownerEvent.RepoStars = r.Stars
+		ownerEvent.RepoLastFetched = r.LastFetched
+	}
+
+	if om.InputRev != nil {
+		ownerEvent.Branches = []string{*om.InputRev}
+	}
+
+	return ownerEvent
+}
+
 // eventStreamOTHook returns a StatHook which logs to log.
 func eventStreamOTHook(log func(...otlog.Field)) func(streamhttp.WriterStat) {
 	return func(stat streamhttp.WriterStat) {
@@ -6,10 +6,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -212,6 +214,97 @@ func TestDisplayLimit(t *testing.T) {
 	}
 }
 
+func TestServeStream_export(t *testing.T) {
+	database.Mocks.Repos.Metadata = func(ctx context.Context, ids ...api2.RepoID) (_ []*types.SearchedRepo, err error) {
+		res := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, &types.SearchedRepo{
+				ID:   id,
+				Name: api2.RepoName(fmt.Sprintf("repo%d", id)),
+			})
+		}
+		return res, nil
+	}
+	defer func() { database.Mocks.Repos.Metadata = nil }()
+
+	mock := &mockSearchResolver{
+		done: make(chan struct{}),
+	}
+
+	ts := httptest.NewServer(&streamHandler{
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		newSearchResolver: func(_ context.Context, _ dbutil.DB, args *graphqlbackend.SearchArgs) (searchResolver, error) {
+			mock.c = args.Stream
+			return mock, nil
+		}})
+	defer ts.Close()
+
+	req, _ := streamhttp.NewExportRequest(ts.URL, "foo", streamhttp.ExportJSONL)
+	q := req.URL.Query()
+	q.Add("display", "1") // ignored by exports
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var rows []streamhttp.ExportRow
+	g := errgroup.Group{}
+	g.Go(func() (err error) {
+		rows, err = streamhttp.ReadExportRows(resp.Body)
+		return err
+	})
+
+	mock.c.Send(streaming.SearchEvent{
+		Results: []result.Match{
+			mkRepoMatch(1),
+			&result.FileMatch{
+				File: result.File{
+					Repo:     types.RepoName{ID: 2, Name: "repo2"},
+					CommitID: "deadbeef",
+					Path:     "main.go",
+				},
+				LineMatches: []*result.LineMatch{
+					{Preview: "func main() {", LineNumber: 2},
+					{Preview: "}", LineNumber: 4},
+				},
+			},
+		},
+	})
+	mock.Close()
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []streamhttp.ExportRow{
+		{Type: "repo", Repository: "repo1"},
+		{Type: "content", Repository: "repo2", Revision: "deadbeef", Path: "main.go", LineNumber: 3, Preview: "func main() {"},
+		{Type: "content", Repository: "repo2", Revision: "deadbeef", Path: "main.go", LineNumber: 5, Preview: "}"},
+	}
+	if diff := cmp.Diff(want, rows); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("got content type %q", got)
+	}
+}
+
+func TestParseURLQuery_export(t *testing.T) {
+	if _, err := parseURLQuery(url.Values{"q": {"foo"}, "export": {"xml"}}); err == nil {
+		t.Fatal("expected error for unsupported export format")
+	}
+	a, err := parseURLQuery(url.Values{"q": {"foo"}, "export": {"csv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Export != streamhttp.ExportCSV {
+		t.Fatalf("got export %q, want csv", a.Export)
+	}
+}
+
 func mkRepoMatch(id int) *result.RepoMatch {
 	return &result.RepoMatch{
 		ID:   api2.RepoID(id),
@@ -393,19 +393,22 @@ func (s *Server) Janitor(interval time.Duration) {
 
 // SyncRepoState syncs state on disk to the database for all repos and is
 // expected to run in a background goroutine. We perform a full sync if the known
-// gitserver addresses has changed since the last run. Otherwise, we only sync
-// repos that have not yet been assigned a shard.
+// gitserver addresses or the replication factor have changed since the last
+// run. Otherwise, we only sync repos that have not yet been assigned a shard.
 func (s *Server) SyncRepoState(interval time.Duration, batchSize, perSecond int) {
 	var previousAddrs string
+	var previousReplicas int
 	for {
 		addrs := conf.Get().ServiceConnections.GitServers
+		replicas := conf.GitReplicationFactor()
 		// We turn addrs into a string here for easy comparison and storage of previous
 		// addresses since we'd need to take a copy of the slice anyway.
 		currentAddrs := strings.Join(addrs, ",")
-		fullSync := currentAddrs != previousAddrs
+		fullSync := currentAddrs != previousAddrs || replicas != previousReplicas
 		previousAddrs = currentAddrs
+		previousReplicas = replicas
 
-		if err := s.syncRepoState(addrs, batchSize, perSecond, fullSync); err != nil {
+		if err := s.syncRepoState(addrs, replicas, batchSize, perSecond, fullSync); err != nil {
 			log15.Error("Syncing repo state", "error ", err)
 		}
 
@@ -496,6 +499,30 @@ func (s *Server) hostnameMatch(addr string) bool {
 	return next == '.' || next == ':'
 }
 
+// replicaIndex returns the position of this gitserver among the gitservers
+// which store the repo, where 0 is the primary. It returns -1 if the repo
+// isn't stored on this gitserver.
+func (s *Server) replicaIndex(repo api.RepoName, addrs []string, replicas int) int {
+	for i, addr := range gitserver.AddrsForRepo(repo, addrs, replicas) {
+		if s.hostnameMatch(addr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isSecondaryReplica returns whether the repo is replicated to this
+// gitserver, but another gitserver is its primary. Only the primary writes
+// the row of a repo in gitserver_repos, the replicas report their clone
+// status in gitserver_repo_replicas.
+func (s *Server) isSecondaryReplica(repo api.RepoName) bool {
+	addrs := conf.Get().ServiceConnections.GitServers
+	if len(addrs) == 0 {
+		return false
+	}
+	return s.replicaIndex(repo, addrs, conf.GitReplicationFactor()) > 0
+}
+
 var (
 	repoSyncStateCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "src_repo_sync_state_counter",
@@ -511,7 +538,7 @@ var (
 	}, []string{"success"})
 )
 
-func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSync bool) error {
+func (s *Server) syncRepoState(addrs []string, replicas, batchSize, perSecond int, fullSync bool) error {
 	log15.Info("starting syncRepoState", "fullSync", fullSync)
 
 	// When fullSync is true we'll scan all repos in the database and ensure we set
@@ -520,6 +547,10 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 	//
 	// When fullSync is false, we assume that we only need to check repos that have
 	// not yet had their shard_id allocated.
+	//
+	// When repos are replicated, the primary of a repo assigns it to its shard and
+	// every replica, including the primary, reports its clone status in
+	// gitserver_repo_replicas.
 
 	// Sanity check our host exists in addrs before starting any work
 	var found bool
@@ -550,6 +581,29 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 	}
 
 	batch := make([]*types.GitserverRepo, 0)
+	replicaBatch := make([]*types.GitserverRepoReplica, 0)
+
+	writeReplicaBatch := func() {
+		if len(replicaBatch) == 0 {
+			return
+		}
+		// We always clear the batch
+		defer func() {
+			replicaBatch = replicaBatch[0:0]
+		}()
+		err := limiter.WaitN(ctx, len(replicaBatch))
+		if err != nil {
+			log15.Error("Waiting for rate limiter", "error", err)
+			return
+		}
+
+		if err := store.UpsertReplicas(ctx, replicaBatch...); err != nil {
+			repoStateUpsertCounter.WithLabelValues("false").Add(float64(len(replicaBatch)))
+			log15.Error("Upserting GitserverRepoReplicas", "error", err)
+			return
+		}
+		repoStateUpsertCounter.WithLabelValues("true").Add(float64(len(replicaBatch)))
+	}
 
 	writeBatch := func() {
 		if len(batch) == 0 {
@@ -589,7 +643,8 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 
 		repoSyncStateCounter.WithLabelValues("check").Inc()
 		// Ensure we're only dealing with repos we are responsible for
-		if addr := gitserver.AddrForRepo(repo.Name, addrs); !s.hostnameMatch(addr) {
+		replica := s.replicaIndex(repo.Name, addrs, replicas)
+		if replica < 0 {
 			repoSyncStateCounter.WithLabelValues("other_shard").Inc()
 			return nil
 		}
@@ -598,6 +653,23 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 		dir := s.dir(repo.Name)
 		cloned := repoCloned(dir)
 		_, cloning := s.locker.Status(dir)
+		cloneStatus := cloneStatus(cloned, cloning)
+
+		if replicas > 1 {
+			replicaBatch = append(replicaBatch, &types.GitserverRepoReplica{
+				RepoID:      repo.ID,
+				ShardID:     s.Hostname,
+				CloneStatus: cloneStatus,
+			})
+			if len(replicaBatch) >= batchSize {
+				writeReplicaBatch()
+			}
+		}
+
+		// Only the primary updates the row of the repo in gitserver_repos.
+		if replica > 0 {
+			return nil
+		}
 
 		var shouldUpdate bool
 		if repo.GitserverRepo == nil {
@@ -610,7 +682,6 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 			repo.ShardID = s.Hostname
 			shouldUpdate = true
 		}
-		cloneStatus := cloneStatus(cloned, cloning)
 		if repo.CloneStatus != cloneStatus {
 			repo.CloneStatus = cloneStatus
 			shouldUpdate = true
@@ -631,6 +702,7 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 
 	// Attempt final write
 	writeBatch()
+	writeReplicaBatch()
 
 	return err
 }
@@ -1465,14 +1537,14 @@ func (s *Server) p4exec(w http.ResponseWriter, r *http.Request, req *protocol.P4
 }
 
 func (s *Server) setLastError(ctx context.Context, name api.RepoName, error string) (err error) {
-	if s.DB == nil {
+	if s.DB == nil || s.isSecondaryReplica(name) {
 		return nil
 	}
 	return database.GitserverRepos(s.DB).SetLastError(ctx, name, error, s.Hostname)
 }
 
 func (s *Server) setLastFetched(ctx context.Context, name api.RepoName) error {
-	if s.DB == nil {
+	if s.DB == nil || s.isSecondaryReplica(name) {
 		return nil
 	}
 
@@ -1510,7 +1582,16 @@ func (s *Server) setCloneStatus(ctx context.Context, name api.RepoName, status t
 	if s.DB == nil {
 		return nil
 	}
-	return database.GitserverRepos(s.DB).SetCloneStatus(ctx, name, status, s.Hostname)
+	store := database.GitserverRepos(s.DB)
+	if conf.GitReplicationFactor() > 1 {
+		if err := store.SetReplicaCloneStatus(ctx, name, status, s.Hostname); err != nil {
+			return err
+		}
+	}
+	if s.isSecondaryReplica(name) {
+		return nil
+	}
+	return store.SetCloneStatus(ctx, name, status, s.Hostname)
 }
 
 // setCloneStatusNonFatal is the same as setCloneStatus but only logs errors
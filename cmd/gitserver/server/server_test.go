@@ -28,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -946,7 +947,7 @@ func TestSyncRepoState(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = s.syncRepoState([]string{hostname}, 10, 10, true)
+	err = s.syncRepoState([]string{hostname}, 1, 10, 10, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -961,6 +962,88 @@ func TestSyncRepoState(t *testing.T) {
 	}
 }
 
+func TestSyncRepoState_Replicated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbtesting.GetDB(t)
+	remoteDir := t.TempDir()
+
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remoteDir, name, arg...)
+	}
+
+	// Setup a repo with a commit so we can see if we can clone it.
+	cmd("git", "init", ".")
+	cmd("sh", "-c", "echo hello world > hello.txt")
+	cmd("git", "add", "hello.txt")
+	cmd("git", "commit", "-m", "hello")
+
+	reposDir := t.TempDir()
+	repoName := api.RepoName("example.com/foo/bar")
+	hostname := "test"
+
+	// The primary of the repo is "primary", it is replicated to hostname.
+	addrs := []string{"primary", hostname}
+	if diff := cmp.Diff(addrs, gitserver.AddrsForRepo(repoName, addrs, 2)); diff != "" {
+		t.Fatalf("unexpected replicas (-want +got):\n%s", diff)
+	}
+
+	s := makeTestServer(ctx, reposDir, remoteDir, db)
+	s.Hostname = hostname
+	s.ctx = ctx
+
+	dbRepo := &types.Repo{
+		Name:        repoName,
+		URI:         string(repoName),
+		Description: "Test",
+	}
+
+	// Insert the repo into our database
+	err := database.Repos(db).Create(ctx, dbRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.cloneRepo(ctx, repoName, &cloneOptions{Block: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := &types.GitserverRepo{
+		RepoID:      dbRepo.ID,
+		ShardID:     "primary",
+		CloneStatus: types.CloneStatusNotCloned,
+	}
+	if err := database.GitserverRepos(db).Upsert(ctx, primary); err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.syncRepoState(addrs, 2, 10, 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The replica reports its own clone status...
+	replicas, err := database.GitserverRepos(db).ListReplicas(ctx, dbRepo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas) != 1 || replicas[0].ShardID != hostname || replicas[0].CloneStatus != types.CloneStatusCloned {
+		t.Fatalf("unexpected replicas %+v", replicas)
+	}
+
+	// ...but leaves the status of the repo to the primary.
+	gr, err := database.GitserverRepos(db).GetByID(ctx, dbRepo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gr.ShardID != primary.ShardID || gr.CloneStatus != primary.CloneStatus {
+		t.Fatalf("Want %v on %q, got %v on %q", primary.CloneStatus, primary.ShardID, gr.CloneStatus, gr.ShardID)
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	if !testing.Verbose() {
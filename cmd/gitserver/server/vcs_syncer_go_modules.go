@@ -0,0 +1,170 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// sourcegraphGoDependency is used to set GIT_AUTHOR_NAME for git commands
+// that don't create commits or tags. The name of this dependency should never
+// be publicly visible so it can have any random value.
+var sourcegraphGoDependency = reposource.GoDependency{
+	GoModule: reposource.GoModule{
+		Path: "sourcegraph.com/sourcegraph",
+	},
+	Version: "v1.0.0",
+}
+
+// NewGoModulesSyncer returns a VCSSyncer for repos of Go modules.
+func NewGoModulesSyncer(config *schema.GoModulesConnection, client *goproxy.Client) VCSSyncer {
+	return &vcsPackagesSyncer{
+		typ:         "go_modules",
+		placeholder: sourcegraphGoDependency,
+		source:      &goModulesSource{config: config, client: client},
+	}
+}
+
+type goModulesSource struct {
+	config *schema.GoModulesConnection
+	client *goproxy.Client
+}
+
+var _ packagesSource = &goModulesSource{}
+
+func (s *goModulesSource) Dependencies(ctx context.Context, repoURLPath string) ([]reposource.PackageDependency, error) {
+	module, err := reposource.ParseGoModuleFromRepoURL(repoURLPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []reposource.GoDependency
+	for _, dependency := range s.config.Dependencies {
+		dependency, err := reposource.ParseGoDependency(dependency)
+		if err != nil {
+			return nil, err
+		}
+		if dependency.GoModule != module {
+			continue
+		}
+		// Silently ignore non-existent dependencies because they are
+		// already logged out in the `GetRepo` method in
+		// internal/repos/go_modules.go.
+		if s.client.Exists(ctx, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return nil, errors.Errorf("no Go dependencies for URL path %s", repoURLPath)
+	}
+
+	reposource.SortGoDependencies(dependencies)
+	packageDependencies := make([]reposource.PackageDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		packageDependencies = append(packageDependencies, dependency)
+	}
+	return packageDependencies, nil
+}
+
+// Download fetches the module zip file of the given dependency and extracts
+// it into the given directory.
+func (s *goModulesSource) Download(ctx context.Context, dir string, dependency reposource.PackageDependency) error {
+	goDependency := dependency.(reposource.GoDependency)
+
+	zipPath, err := s.downloadZip(ctx, goDependency)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(zipPath)
+
+	if err := unzipGoModule(zipPath, goDependency, dir); err != nil {
+		return errors.Wrapf(err, "failed to unzip module zip file for %s", goDependency.PackageManagerSyntax())
+	}
+	return nil
+}
+
+// downloadZip downloads the zip file of the given dependency to a temporary
+// file, which the caller must remove.
+func (s *goModulesSource) downloadZip(ctx context.Context, dependency reposource.GoDependency) (_ string, err error) {
+	zip, err := s.client.GetZip(ctx, dependency)
+	if err != nil {
+		return "", err
+	}
+	defer zip.Close()
+
+	file, err := os.CreateTemp("", "gomod-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err1 := file.Close()
+		if err == nil {
+			err = err1
+		}
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+
+	if _, err := io.Copy(file, zip); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// unzipGoModule extracts the files of the given module zip file to the
+// destination directory. All files of a module zip are under the
+// "module@version/" directory, which is stripped from the extracted paths.
+func unzipGoModule(zipPath string, dependency reposource.GoDependency, destination string) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	destinationDirectory := strings.TrimSuffix(destination, string(os.PathSeparator)) + string(os.PathSeparator)
+	prefix := dependency.PackageManagerSyntax() + "/"
+
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, prefix) {
+			// Module zips must not contain files outside of the
+			// module directory.
+			continue
+		}
+		name := strings.TrimPrefix(file.Name, prefix)
+		if name == ".git" || strings.HasPrefix(name, ".git/") {
+			// For security reasons, don't unzip files under the `.git/`
+			// directory. See https://github.com/sourcegraph/security-issues/issues/163
+			continue
+		}
+		if name == "" || strings.HasSuffix(name, "/") {
+			// Skip directory entries.
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			// For security reasons, only unzip regular files.
+			continue
+		}
+		outputPath := path.Join(destination, name)
+		if !strings.HasPrefix(outputPath, destinationDirectory) {
+			// For security reasons, skip file if it's not a child
+			// of the target directory. See "Zip Slip Vulnerability".
+			continue
+		}
+
+		if err := copyZipFileEntry(file, outputPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
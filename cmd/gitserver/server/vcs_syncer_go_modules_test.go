@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+)
+
+// exampleGoProxyModule is the path of the module github.com/Example/example
+// with uppercase letters escaped like proxies expect it.
+const exampleGoProxyModule = "/github.com/!example/example/@v/"
+
+// goProxyStandIn serves the version info and zips of the given versions of
+// the module github.com/Example/example like a Go module proxy.
+func goProxyStandIn(t *testing.T, zips map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, exampleGoProxyModule) {
+			http.NotFound(w, r)
+			return
+		}
+		file := strings.TrimPrefix(r.URL.Path, exampleGoProxyModule)
+		switch {
+		case strings.HasSuffix(file, ".info"):
+			version := strings.TrimSuffix(file, ".info")
+			if _, ok := zips[version]; !ok {
+				http.Error(w, "not found", http.StatusGone)
+				return
+			}
+			assert.Nil(t, json.NewEncoder(w).Encode(goproxy.VersionInfo{Version: version}))
+		case strings.HasSuffix(file, ".zip"):
+			zip, ok := zips[strings.TrimSuffix(file, ".zip")]
+			if !ok {
+				http.Error(w, "not found", http.StatusGone)
+				return
+			}
+			_, _ = w.Write(zip)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// hgFastExportCommand is the command of https://github.com/frej/fast-export,
+// which converts the changesets of a Mercurial repository to Git commits.
+const hgFastExportCommand = "hg-fast-export.sh"
+
+// HgRepoSyncer is a syncer for Mercurial repositories. The Mercurial
+// repository is pulled into the "hg" directory of the bare Git repository and
+// its changesets are converted to Git commits with hg-fast-export, which
+// records the already converted changesets in the Git directory. Fetches
+// therefore only pull and convert the new changesets.
+//
+// Named branches are converted to Git branches, where the "default" branch
+// becomes "master", and tags are converted to Git tags.
+type HgRepoSyncer struct{}
+
+var _ VCSSyncer = &HgRepoSyncer{}
+
+func (s *HgRepoSyncer) Type() string {
+	return "hg"
+}
+
+// hgCommand returns a command that runs hg with the given arguments. HGPLAIN
+// disables user configuration which changes the output of hg, and prompts are
+// disabled because no one could answer them.
+func hgCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "hg", append([]string{"--noninteractive"}, args...)...)
+	cmd.Env = append(os.Environ(), "HGPLAIN=1", "HGENCODING=utf-8")
+	return cmd
+}
+
+// hgRepositoryPath returns the path of the Mercurial repository inside of
+// the Git directory.
+func hgRepositoryPath(dir GitDir) string {
+	return dir.Path("hg")
+}
+
+// IsCloneable checks to see if the Mercurial remote URL is cloneable.
+func (s *HgRepoSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	cmd := hgCommand(ctx, "identify", remoteURL.String())
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns the command to be executed for cloning a Mercurial
+// repository as a Git repository. Like for JVM packages, the actual cloning
+// and conversion happens inside this method and the returned command is a
+// no-op.
+func (s *HgRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "clone setup failed")
+	}
+
+	if err := s.Fetch(ctx, remoteURL, GitDir(tmpPath)); err != nil {
+		return nil, err
+	}
+
+	// no-op command to satisfy VCSSyncer interface, see docstring for more details.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch pulls the new changesets of a Mercurial repository and converts them
+// to Git commits.
+func (s *HgRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
+	hgPath := hgRepositoryPath(dir)
+	if _, err := os.Stat(hgPath); os.IsNotExist(err) {
+		// We pull into an empty repository instead of cloning, because
+		// cloning would store the remote URL with its credentials in the
+		// configuration of the repository.
+		if output, err := runWith(ctx, hgCommand(ctx, "init", hgPath), false, nil); err != nil {
+			return errors.Wrapf(err, "failed to create Mercurial repository with output %q", string(output))
+		}
+	} else if err != nil {
+		return err
+	}
+
+	cmd := hgCommand(ctx, "pull", "--repository", hgPath, remoteURL.String())
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to pull with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	// --force converts branches with multiple heads, whose most recent head
+	// becomes the Git branch, instead of aborting the conversion.
+	cmd = exec.CommandContext(ctx, hgFastExportCommand, "-r", hgPath, "--force")
+	cmd.Env = append(os.Environ(), "HGPLAIN=1", "HGENCODING=utf-8")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to convert Mercurial changesets with output %q", string(output))
+	}
+
+	return nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing the Git
+// remote of a Mercurial repository.
+func (s *HgRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	// The Git repository has no remote, so HEAD falls back to "master", which
+	// is the converted "default" branch.
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
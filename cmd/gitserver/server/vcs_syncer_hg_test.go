@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestHgCloneCommand(t *testing.T) {
+	for _, command := range []string{"hg", hgFastExportCommand} {
+		if _, err := exec.LookPath(command); err != nil {
+			t.Skipf("command not in PATH: %s", command)
+		}
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	remote := filepath.Join(dir, "remote")
+	if err := os.Mkdir(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	hg := func(args ...string) {
+		t.Helper()
+		cmd := hgCommand(ctx, args...)
+		cmd.Dir = remote
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("hg %v failed: %s\n%s", args, err, out)
+		}
+	}
+	writeFile := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(remote, "README"), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hg("init")
+	writeFile("first\n")
+	hg("add", "README")
+	hg("commit", "--user", "Alice <alice@example.com>", "--message", "first")
+
+	remoteURL, err := vcs.ParseURL("file://" + remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := HgRepoSyncer{}
+	bareGitDirectory := filepath.Join(dir, "git")
+	cmd, err := s.CloneCommand(ctx, remoteURL, bareGitDirectory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertCommandOutput(t,
+		exec.Command("git", "show", "master:README"),
+		bareGitDirectory,
+		"first\n",
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "log", "--format=%an <%ae> %s", "master"),
+		bareGitDirectory,
+		"Alice <alice@example.com> first\n",
+	)
+
+	// Fetches only convert the new changesets.
+	writeFile("second\n")
+	hg("commit", "--user", "Bob <bob@example.com>", "--message", "second")
+	hg("tag", "--user", "Bob <bob@example.com>", "--message", "tag v1.0.0", "v1.0.0")
+	hg("branch", "feature")
+	writeFile("feature\n")
+	hg("commit", "--user", "Bob <bob@example.com>", "--message", "feature")
+
+	if err := s.Fetch(ctx, remoteURL, GitDir(bareGitDirectory)); err != nil {
+		t.Fatal(err)
+	}
+	assertCommandOutput(t,
+		exec.Command("git", "show", "v1.0.0:README"),
+		bareGitDirectory,
+		"second\n",
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "feature:README"),
+		bareGitDirectory,
+		"feature\n",
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "log", "--format=%s", "master"),
+		bareGitDirectory,
+		"tag v1.0.0\nsecond\nfirst\n",
+	)
+}
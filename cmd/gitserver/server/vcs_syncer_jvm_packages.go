@@ -26,9 +26,9 @@ import (
 )
 
 const (
-	// DO NOT CHANGE. This timestamp needs to be stable so that JVM package
+	// DO NOT CHANGE. This timestamp needs to be stable so that package
 	// repos consistently produce the same git revhash.  Changing this
-	// timestamp will cause links to JVM package repos to return 404s
+	// timestamp will cause links to package repos to return 404s
 	// because Sourcegraph URLs can optionally include the git commit sha.
 	stableGitCommitDate = "Thu Apr 8 14:24:52 2021 +0200"
 
@@ -472,8 +472,8 @@ func roundJVMVersionToNearestStableVersion(javaVersion int) int {
 	return javaVersion
 }
 
-func runCommandInDirectory(ctx context.Context, cmd *exec.Cmd, workingDirectory string, dependency reposource.MavenDependency) (string, error) {
-	gitName := dependency.MavenModule.CoursierSyntax() + " authors"
+func runCommandInDirectory(ctx context.Context, cmd *exec.Cmd, workingDirectory string, dependency reposource.PackageDependency) (string, error) {
+	gitName := dependency.PackageSyntax() + " authors"
 	gitEmail := "code-intel@sourcegraph.com"
 	cmd.Dir = workingDirectory
 	cmd.Env = append(cmd.Env, "EMAIL="+gitEmail)
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages/npm"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// sourcegraphNpmDependency is used to set GIT_AUTHOR_NAME for git commands
+// that don't create commits or tags. The name of this dependency should never
+// be publicly visible so it can have any random value.
+var sourcegraphNpmDependency = reposource.NpmDependency{
+	NpmPackage: reposource.NpmPackage{
+		Scope: "sourcegraph",
+		Name:  "sourcegraph",
+	},
+	Version: "1.0.0",
+}
+
+// NewNpmPackagesSyncer returns a VCSSyncer for repos of npm packages.
+func NewNpmPackagesSyncer(config *schema.NpmPackagesConnection, client *npm.Client) VCSSyncer {
+	return &vcsPackagesSyncer{
+		typ:         "npm_packages",
+		placeholder: sourcegraphNpmDependency,
+		source:      &npmPackagesSource{config: config, client: client},
+	}
+}
+
+type npmPackagesSource struct {
+	config *schema.NpmPackagesConnection
+	client *npm.Client
+}
+
+var _ packagesSource = &npmPackagesSource{}
+
+func (s *npmPackagesSource) Dependencies(ctx context.Context, repoURLPath string) ([]reposource.PackageDependency, error) {
+	pkg, err := reposource.ParseNpmPackageFromRepoURL(repoURLPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []reposource.NpmDependency
+	for _, dependency := range s.config.Dependencies {
+		dependency, err := reposource.ParseNpmDependency(dependency)
+		if err != nil {
+			return nil, err
+		}
+		if dependency.NpmPackage != pkg {
+			continue
+		}
+		// Silently ignore non-existent dependencies because they are
+		// already logged out in the `GetRepo` method in
+		// internal/repos/npm_packages.go.
+		if s.client.Exists(ctx, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return nil, errors.Errorf("no npm dependencies for URL path %s", repoURLPath)
+	}
+
+	reposource.SortNpmDependencies(dependencies)
+	packageDependencies := make([]reposource.PackageDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		packageDependencies = append(packageDependencies, dependency)
+	}
+	return packageDependencies, nil
+}
+
+// Download fetches the tarball of the given dependency and extracts it into
+// the given directory.
+func (s *npmPackagesSource) Download(ctx context.Context, dir string, dependency reposource.PackageDependency) error {
+	npmDependency := dependency.(reposource.NpmDependency)
+
+	tarball, err := s.client.FetchTarball(ctx, npmDependency)
+	if err != nil {
+		return err
+	}
+	defer tarball.Close()
+
+	if err := extractTgz(tarball, dir); err != nil {
+		return errors.Wrapf(err, "failed to extract tarball for %s", npmDependency.PackageManagerSyntax())
+	}
+	return nil
+}
+
+// extractTgz extracts the regular files of the given gzipped tarball to the
+// destination directory. npm tarballs contain a single top-level directory,
+// usually named "package", which is stripped from the extracted paths.
+func extractTgz(tgz io.Reader, destination string) error {
+	gzipReader, err := gzip.NewReader(tgz)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	destinationDirectory := strings.TrimSuffix(destination, string(os.PathSeparator)) + string(os.PathSeparator)
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			// For security reasons, only extract regular files. Symbolic
+			// and hard links could point outside of the destination.
+			continue
+		}
+		if strings.HasPrefix(header.Name, "/") {
+			// Skip absolute paths.
+			continue
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == ".git" || strings.HasPrefix(name, ".git/") {
+			// For security reasons, don't extract files under the `.git/`
+			// directory. See https://github.com/sourcegraph/security-issues/issues/163
+			continue
+		}
+		outputPath := path.Join(destination, name)
+		if !strings.HasPrefix(outputPath, destinationDirectory) {
+			// For security reasons, skip file if it's not a child
+			// of the target directory. See "Zip Slip Vulnerability".
+			continue
+		}
+
+		if err := copyTarFileEntry(tarReader, outputPath); err != nil {
+			return err
+		}
+	}
+}
+
+func copyTarFileEntry(tarReader io.Reader, outputPath string) (err error) {
+	if err = os.MkdirAll(path.Dir(outputPath), 0700); err != nil {
+		return err
+	}
+	outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err1 := outputFile.Close()
+		if err == nil {
+			err = err1
+		}
+	}()
+
+	_, err = io.Copy(outputFile, tarReader)
+	return err
+}
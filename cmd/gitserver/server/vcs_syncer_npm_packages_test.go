@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages/npm"
+)
+
+const (
+	exampleNpmRegistryPackage  = "/@example/example/"
+	exampleNpmRegistryTarballs = "/tarballs/"
+)
+
+// npmRegistryStandIn serves the metadata and tarballs of the given versions
+// of the package @example/example like an npm registry.
+func npmRegistryStandIn(t *testing.T, tarballs map[string][]byte) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, exampleNpmRegistryPackage):
+			version := strings.TrimPrefix(r.URL.Path, exampleNpmRegistryPackage)
+			if _, ok := tarballs[version]; !ok {
+				http.NotFound(w, r)
+				return
+			}
+			var metadata npm.PackageVersion
+			metadata.Name = "@example/example"
+			metadata.Version = version
+			metadata.Dist.Tarball = server.URL + exampleNpmRegistryTarballs + version + ".tgz"
+			assert.Nil(t, json.NewEncoder(w).Encode(metadata))
+		case strings.HasPrefix(r.URL.Path, exampleNpmRegistryTarballs):
+			version := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, exampleNpmRegistryTarballs), ".tgz")
+			tarball, ok := tarballs[version]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server
+}
@@ -0,0 +1,295 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages/pypi"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type archiveEntry struct {
+	name     string
+	contents string
+	typeflag byte
+	linkname string
+}
+
+func createTgz(t *testing.T, entries ...archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		typeflag := entry.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		assert.Nil(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Mode:     0644,
+			Size:     int64(len(entry.contents)),
+			Typeflag: typeflag,
+			Linkname: entry.linkname,
+		}))
+		_, err := tarWriter.Write([]byte(entry.contents))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func createZip(t *testing.T, entries ...archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		contents := entry.contents
+		if entry.typeflag == tar.TypeSymlink {
+			// Zip files store the target of symbolic links as contents.
+			header.SetMode(os.ModeSymlink | 0777)
+			contents = entry.linkname
+		}
+		w, err := zipWriter.CreateHeader(header)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(contents))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zipWriter.Close())
+	return buf.Bytes()
+}
+
+func runCloneCommand(t *testing.T, s VCSSyncer, repoURLPath, bareGitDirectory string) {
+	url := vcs.URL{
+		URL: url.URL{Path: repoURLPath},
+	}
+	cmd, err := s.CloneCommand(context.Background(), &url, bareGitDirectory)
+	assert.Nil(t, err)
+	assert.Nil(t, cmd.Run())
+}
+
+func TestPackagesCloneCommand(t *testing.T) {
+	const (
+		exampleFileContents  = "1\n"
+		exampleFileContents2 = "2\n"
+	)
+
+	tests := []struct {
+		name        string
+		repoURLPath string
+		filePath    string
+		// dependencies are the dependencies of two published versions of
+		// the example package and of a version which isn't published.
+		dependencies [3]string
+		tags         [2]string
+		// newSyncer starts a stand-in for the package host that serves the
+		// published versions with the example file at filePath, and returns
+		// a syncer for it along with its configured dependencies.
+		newSyncer func(t *testing.T, filePath string) (VCSSyncer, *[]string)
+	}{
+		{
+			name:         "npm",
+			repoURLPath:  "npm/example/example",
+			filePath:     "index.js",
+			dependencies: [3]string{"@example/example@1.0.0", "@example/example@2.0.0", "@example/example@3.0.0"},
+			tags:         [2]string{"v1.0.0", "v2.0.0"},
+			newSyncer: func(t *testing.T, filePath string) (VCSSyncer, *[]string) {
+				server := npmRegistryStandIn(t, map[string][]byte{
+					"1.0.0": createTgz(t, archiveEntry{name: "package/" + filePath, contents: exampleFileContents}),
+					"2.0.0": createTgz(t, archiveEntry{name: "package/" + filePath, contents: exampleFileContents2}),
+				})
+				t.Cleanup(server.Close)
+
+				config := &schema.NpmPackagesConnection{Registry: server.URL}
+				return NewNpmPackagesSyncer(config, npm.NewClient(config, http.DefaultClient)), &config.Dependencies
+			},
+		},
+		{
+			name:         "go modules",
+			repoURLPath:  "go/github.com/Example/example",
+			filePath:     "example.go",
+			dependencies: [3]string{"github.com/Example/example@v1.0.0", "github.com/Example/example@v2.0.0+incompatible", "github.com/Example/example@v3.0.0+incompatible"},
+			tags:         [2]string{"v1.0.0", "v2.0.0+incompatible"},
+			newSyncer: func(t *testing.T, filePath string) (VCSSyncer, *[]string) {
+				prefix := "github.com/Example/example@"
+				server := goProxyStandIn(t, map[string][]byte{
+					"v1.0.0":              createZip(t, archiveEntry{name: prefix + "v1.0.0/" + filePath, contents: exampleFileContents}),
+					"v2.0.0+incompatible": createZip(t, archiveEntry{name: prefix + "v2.0.0+incompatible/" + filePath, contents: exampleFileContents2}),
+				})
+				t.Cleanup(server.Close)
+
+				// The first proxy never has any modules, so all requests fall
+				// back to the second one.
+				empty := httptest.NewServer(http.NotFoundHandler())
+				t.Cleanup(empty.Close)
+
+				config := &schema.GoModulesConnection{Urls: []string{empty.URL, server.URL}}
+				return NewGoModulesSyncer(config, goproxy.NewClient(config, http.DefaultClient)), &config.Dependencies
+			},
+		},
+		{
+			name:         "python packages",
+			repoURLPath:  "python/example",
+			filePath:     "example/__init__.py",
+			dependencies: [3]string{"Example==1.0.0", "example==2.0.0", "example==3.0.0"},
+			tags:         [2]string{"v1.0.0", "v2.0.0"},
+			newSyncer: func(t *testing.T, filePath string) (VCSSyncer, *[]string) {
+				server := pythonIndexStandIn(t, map[string][]byte{
+					// A source distribution, which has a top-level directory.
+					"example-1.0.0.tar.gz": createTgz(t, archiveEntry{name: "example-1.0.0/" + filePath, contents: exampleFileContents}),
+					// A wheel, which has the package at the top level.
+					"example-2.0.0-py3-none-any.whl": createZip(t, archiveEntry{name: filePath, contents: exampleFileContents2}),
+				})
+				t.Cleanup(server.Close)
+
+				config := &schema.PythonPackagesConnection{Urls: []string{server.URL + "/simple"}}
+				return NewPythonPackagesSyncer(config, pypi.NewClient(config, http.DefaultClient)), &config.Dependencies
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			s, dependencies := test.newSyncer(t, test.filePath)
+			bareGitDirectory := path.Join(dir, "git")
+
+			*dependencies = []string{test.dependencies[0]}
+			runCloneCommand(t, s, test.repoURLPath, bareGitDirectory)
+			assertCommandOutput(t,
+				exec.Command("git", "tag", "--list"),
+				bareGitDirectory,
+				test.tags[0]+"\n",
+			)
+			assertCommandOutput(t,
+				exec.Command("git", "show", fmt.Sprintf("%s:%s", test.tags[0], test.filePath)),
+				bareGitDirectory,
+				exampleFileContents,
+			)
+
+			*dependencies = test.dependencies[:]
+			runCloneCommand(t, s, test.repoURLPath, bareGitDirectory)
+			assertCommandOutput(t,
+				exec.Command("git", "tag", "--list"),
+				bareGitDirectory,
+				test.tags[0]+"\n"+test.tags[1]+"\n", // verify that the second tag got added and the unpublished version is skipped
+			)
+			assertCommandOutput(t,
+				exec.Command("git", "show", fmt.Sprintf("latest:%s", test.filePath)),
+				bareGitDirectory,
+				exampleFileContents2,
+			)
+			assertCommandOutput(t,
+				exec.Command("git", "show", fmt.Sprintf("%s:%s", test.tags[0], test.filePath)),
+				bareGitDirectory,
+				exampleFileContents,
+			)
+
+			*dependencies = []string{test.dependencies[0]}
+			runCloneCommand(t, s, test.repoURLPath, bareGitDirectory)
+			assertCommandOutput(t,
+				exec.Command("git", "tag", "--list"),
+				bareGitDirectory,
+				test.tags[0]+"\n", // verify that the second tag has been removed.
+			)
+		})
+	}
+}
+
+func TestPackagesNoMaliciousFiles(t *testing.T) {
+	writeArchive := func(t *testing.T, name string, archive []byte) string {
+		archivePath := path.Join(t.TempDir(), name)
+		assert.Nil(t, os.WriteFile(archivePath, archive, 0644))
+		return archivePath
+	}
+
+	tests := []struct {
+		name string
+		// prefix is the top-level directory of the archive, which is
+		// stripped from the extracted paths.
+		prefix  string
+		extract func(t *testing.T, destination string, entries []archiveEntry) error
+	}{
+		{
+			name:   "npm tarball",
+			prefix: "package/",
+			extract: func(t *testing.T, destination string, entries []archiveEntry) error {
+				return extractTgz(bytes.NewReader(createTgz(t, entries...)), destination)
+			},
+		},
+		{
+			name:   "go module zip",
+			prefix: "github.com/Example/example@v1.0.0/",
+			extract: func(t *testing.T, destination string, entries []archiveEntry) error {
+				dependency, err := reposource.ParseGoDependency("github.com/Example/example@v1.0.0")
+				assert.Nil(t, err)
+				return unzipGoModule(writeArchive(t, "module.zip", createZip(t, entries...)), dependency, destination)
+			},
+		},
+		{
+			name:   "python zip source distribution",
+			prefix: "example-1.0.0/",
+			extract: func(t *testing.T, destination string, entries []archiveEntry) error {
+				f := pypi.File{Name: "example-1.0.0.zip"}
+				return extractPythonDistribution(f, writeArchive(t, f.Name, createZip(t, entries...)), destination)
+			},
+		},
+		{
+			name:   "python tar.gz source distribution",
+			prefix: "example-1.0.0/",
+			extract: func(t *testing.T, destination string, entries []archiveEntry) error {
+				f := pypi.File{Name: "example-1.0.0.tar.gz"}
+				return extractPythonDistribution(f, writeArchive(t, f.Name, createTgz(t, entries...)), destination)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			extractPath := path.Join(dir, "extracted")
+			assert.Nil(t, os.Mkdir(extractPath, os.ModePerm))
+
+			assert.Nil(t, test.extract(t, extractPath, []archiveEntry{
+				{name: "/burger", contents: "absolute"},
+				{name: test.prefix + "../../burger", contents: "zip slip"},
+				{name: test.prefix + ".git/config", contents: "git"},
+				{name: test.prefix + "link", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+				{name: test.prefix + "sample/burger", contents: "burger"},
+			}))
+
+			files, err := os.ReadDir(extractPath)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(files))
+			assert.Equal(t, "sample", files[0].Name())
+
+			_, err = os.Stat(path.Join(dir, "burger"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
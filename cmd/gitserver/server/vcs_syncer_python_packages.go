@@ -0,0 +1,206 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages/pypi"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// sourcegraphPythonDependency is used to set GIT_AUTHOR_NAME for git commands
+// that don't create commits or tags. The name of this dependency should never
+// be publicly visible so it can have any random value.
+var sourcegraphPythonDependency = reposource.PythonDependency{
+	PythonPackage: reposource.PythonPackage{
+		Name: "sourcegraph",
+	},
+	Version: "1.0.0",
+}
+
+// NewPythonPackagesSyncer returns a VCSSyncer for repos of Python packages.
+func NewPythonPackagesSyncer(config *schema.PythonPackagesConnection, client *pypi.Client) VCSSyncer {
+	return &vcsPackagesSyncer{
+		typ:         "python_packages",
+		placeholder: sourcegraphPythonDependency,
+		source:      &pythonPackagesSource{config: config, client: client},
+	}
+}
+
+type pythonPackagesSource struct {
+	config *schema.PythonPackagesConnection
+	client *pypi.Client
+}
+
+var _ packagesSource = &pythonPackagesSource{}
+
+func (s *pythonPackagesSource) Dependencies(ctx context.Context, repoURLPath string) ([]reposource.PackageDependency, error) {
+	pkg, err := reposource.ParsePythonPackageFromRepoURL(repoURLPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []reposource.PythonDependency
+	for _, dependency := range s.config.Dependencies {
+		dependency, err := reposource.ParsePythonDependency(dependency)
+		if err != nil {
+			return nil, err
+		}
+		if dependency.PythonPackage != pkg {
+			continue
+		}
+		// Silently ignore non-existent dependencies because they are
+		// already logged out in the `GetRepo` method in
+		// internal/repos/python_packages.go.
+		if s.client.Exists(ctx, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return nil, errors.Errorf("no Python dependencies for URL path %s", repoURLPath)
+	}
+
+	reposource.SortPythonDependencies(dependencies)
+	packageDependencies := make([]reposource.PackageDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		packageDependencies = append(packageDependencies, dependency)
+	}
+	return packageDependencies, nil
+}
+
+// Download fetches a distribution file of the given dependency and extracts
+// it into the given directory.
+func (s *pythonPackagesSource) Download(ctx context.Context, dir string, dependency reposource.PackageDependency) error {
+	pythonDependency := dependency.(reposource.PythonDependency)
+
+	file, err := s.client.Version(ctx, pythonDependency)
+	if err != nil {
+		return err
+	}
+
+	distributionPath, err := s.downloadDistribution(ctx, file)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(distributionPath)
+
+	if err := extractPythonDistribution(file, distributionPath, dir); err != nil {
+		return errors.Wrapf(err, "failed to extract %s for %s", file.Name, pythonDependency.PackageManagerSyntax())
+	}
+	return nil
+}
+
+// downloadDistribution downloads the given distribution file to a temporary
+// file, which the caller must remove. The hash of the downloaded file is
+// verified if the index provides it.
+func (s *pythonPackagesSource) downloadDistribution(ctx context.Context, f pypi.File) (_ string, err error) {
+	body, err := s.client.Download(ctx, f)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	file, err := os.CreateTemp("", "pypi-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err1 := file.Close()
+		if err == nil {
+			err = err1
+		}
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), body); err != nil {
+		return "", err
+	}
+	if f.SHA256 != "" && !strings.EqualFold(f.SHA256, hex.EncodeToString(hash.Sum(nil))) {
+		return "", errors.Errorf("SHA-256 hash mismatch for %s", f.URL)
+	}
+	return file.Name(), nil
+}
+
+// extractPythonDistribution extracts the files of the given distribution file
+// to the destination directory. Source distributions contain a single
+// top-level directory, usually named "package-version", which is stripped
+// from the extracted paths. Wheels contain the files of the package at the
+// top level.
+func extractPythonDistribution(f pypi.File, distributionPath, destination string) error {
+	switch {
+	case f.IsWheel():
+		return unzipPythonDistribution(distributionPath, destination, false)
+	case strings.HasSuffix(f.Name, ".zip"):
+		return unzipPythonDistribution(distributionPath, destination, true)
+	case strings.HasSuffix(f.Name, ".tar.gz"):
+		tgz, err := os.Open(distributionPath)
+		if err != nil {
+			return err
+		}
+		defer tgz.Close()
+		return extractTgz(tgz, destination)
+	default:
+		return errors.Errorf("unsupported distribution file %s", f.Name)
+	}
+}
+
+// unzipPythonDistribution extracts the regular files of the given zip file to
+// the destination directory. When stripTopLevelDirectory is true, the first
+// component of the paths in the zip file is stripped.
+func unzipPythonDistribution(zipPath, destination string, stripTopLevelDirectory bool) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	destinationDirectory := strings.TrimSuffix(destination, string(os.PathSeparator)) + string(os.PathSeparator)
+
+	for _, file := range reader.File {
+		if !file.Mode().IsRegular() {
+			// For security reasons, only unzip regular files.
+			continue
+		}
+		if strings.HasPrefix(file.Name, "/") {
+			// Skip absolute paths.
+			continue
+		}
+		name := file.Name
+		if stripTopLevelDirectory {
+			i := strings.Index(name, "/")
+			if i < 0 {
+				continue
+			}
+			name = name[i+1:]
+		}
+		if name == ".git" || strings.HasPrefix(name, ".git/") {
+			// For security reasons, don't unzip files under the `.git/`
+			// directory. See https://github.com/sourcegraph/security-issues/issues/163
+			continue
+		}
+		outputPath := path.Join(destination, name)
+		if !strings.HasPrefix(outputPath, destinationDirectory) {
+			// For security reasons, skip file if it's not a child
+			// of the target directory. See "Zip Slip Vulnerability".
+			continue
+		}
+
+		if err := copyZipFileEntry(file, outputPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
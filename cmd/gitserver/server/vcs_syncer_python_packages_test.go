@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages/pypi"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	examplePythonIndexPackage = "/simple/example/"
+	examplePythonIndexFiles   = "/files/"
+)
+
+// pythonIndexStandIn serves a project page that links the given distribution
+// files of the package example, like a PEP 503 simple repository.
+func pythonIndexStandIn(t *testing.T, files map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == examplePythonIndexPackage:
+			var page strings.Builder
+			page.WriteString("<!DOCTYPE html><html><body>\n")
+			for name, contents := range files {
+				hash := sha256.Sum256(contents)
+				fmt.Fprintf(&page, "<a href=\"../..%s%s#sha256=%s\">%s</a>\n", examplePythonIndexFiles, name, hex.EncodeToString(hash[:]), name)
+			}
+			page.WriteString("</body></html>\n")
+			_, _ = w.Write([]byte(page.String()))
+		case strings.HasPrefix(r.URL.Path, examplePythonIndexFiles):
+			contents, ok := files[strings.TrimPrefix(r.URL.Path, examplePythonIndexFiles)]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(contents)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestPythonHashMismatch(t *testing.T) {
+	server := pythonIndexStandIn(t, map[string][]byte{
+		"example-1.0.0.tar.gz": createTgz(t, archiveEntry{name: "example-1.0.0/example/__init__.py", contents: "X = 1\n"}),
+	})
+	defer server.Close()
+
+	config := &schema.PythonPackagesConnection{Urls: []string{server.URL + "/simple"}}
+	s := pythonPackagesSource{
+		config: config,
+		client: pypi.NewClient(config, http.DefaultClient),
+	}
+	_, err := s.downloadDistribution(context.Background(), pypi.File{
+		Name:   "example-1.0.0.tar.gz",
+		URL:    server.URL + examplePythonIndexFiles + "example-1.0.0.tar.gz",
+		SHA256: "0000",
+	})
+	assert.NotNil(t, err)
+}
@@ -118,6 +118,11 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send unsubscribed Slack notification.", "recipient", removedRecipient, "error", err)
 			}
 		}
+		if removedRecipient.teams {
+			if err := teamsNotifyUnsubscribed(ctx, removedRecipient, oldValue); err != nil {
+				log15.Error("Failed to send unsubscribed Microsoft Teams notification.", "recipient", removedRecipient, "error", err)
+			}
+		}
 	}
 	for _, addedRecipient := range addedRecipients {
 		if addedRecipient.email {
@@ -130,6 +135,11 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send subscribed Slack notification.", "recipient", addedRecipient, "error", err)
 			}
 		}
+		if addedRecipient.teams {
+			if err := teamsNotifySubscribed(ctx, addedRecipient, newValue); err != nil {
+				log15.Error("Failed to send subscribed Microsoft Teams notification.", "recipient", addedRecipient, "error", err)
+			}
+		}
 	}
 	return nil
 }
@@ -159,10 +169,20 @@ func serveTestNotification(w http.ResponseWriter, r *http.Request) {
 		}
 		testNotificationAlert := fmt.Sprintf(`It worked! This is a test notification for the Sourcegraph saved search <%s|"%s">.`, searchURL(args.SavedSearch.Config.Query, utmSourceSlack), args.SavedSearch.Config.Description)
 		if err := slackNotify(context.Background(), recipient,
-			testNotificationAlert, args.SavedSearch.Config.SlackWebhookURL); err != nil {
+			testNotificationAlert, args.SavedSearch.Config); err != nil {
 			writeError(w, errors.Errorf("error sending slack notifications to %s: %s", recipient.spec, err))
 			return
 		}
+		testTeamsCard := newTeamsMessageCard(
+			fmt.Sprintf(`Test notification for the saved search "%s"`, args.SavedSearch.Config.Description),
+			fmt.Sprintf(`It worked! This is a test notification for the Sourcegraph saved search "%s".`, escapeTeams(args.SavedSearch.Config.Description)),
+			searchURL(args.SavedSearch.Config.Query, utmSourceTeams),
+			"View saved search",
+		)
+		if err := teamsNotify(context.Background(), recipient, testTeamsCard, args.SavedSearch.Config.TeamsWebhookURL); err != nil {
+			writeError(w, errors.Errorf("error sending Microsoft Teams notifications to %s: %s", recipient.spec, err))
+			return
+		}
 	}
 
 	log15.Info("saved query test notification sent", "spec", args.SavedSearch.Spec, "key", args.SavedSearch.Spec.Key)
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// advanceCursor returns the results of a search `after:<cursor.LatestResult>`
+// which subscribers have not been notified of yet, and the cursor to use for
+// the next search.
+//
+// after: filters include results at the given time and the dates of commits
+// are not strictly ordered, so a search may return results which a previous
+// search returned, too. These are recognized by the SeenResults of cursor,
+// which is why the next cursor remembers every result of this search.
+func advanceCursor(cursor *api.SavedSearchCursor, results []*gqlSearchResult) (newResults []*gqlSearchResult, next *api.SavedSearchCursor) {
+	seen := make(map[string]struct{}, len(cursor.SeenResults))
+	for _, key := range cursor.SeenResults {
+		seen[key] = struct{}{}
+	}
+
+	next = &api.SavedSearchCursor{
+		Key:           cursor.Key,
+		LatestResult:  cursor.LatestResult,
+		SlackThreadTS: cursor.SlackThreadTS,
+	}
+	for _, r := range results {
+		t, err := extractTime(r)
+		if err != nil {
+			log15.Warn("executor: skipping search result", "error", err)
+			continue
+		}
+		key := r.key()
+		next.SeenResults = append(next.SeenResults, key)
+		if t.After(next.LatestResult) {
+			next.LatestResult = t
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		newResults = append(newResults, r)
+	}
+
+	if len(next.SeenResults) == 0 {
+		// Nothing was found, so the next search returns the same results as
+		// this one.
+		next.SeenResults = cursor.SeenResults
+	}
+	return newResults, next
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestAdvanceCursor(t *testing.T) {
+	result := func(repo, oid, date string) *gqlSearchResult {
+		r := &gqlSearchResult{Typename: "CommitSearchResult", Commit: &gqlCommit{OID: oid}}
+		r.Commit.Repository.Name = repo
+		r.Commit.Author.Date = date
+		return r
+	}
+	keys := func(results []*gqlSearchResult) []string {
+		var keys []string
+		for _, r := range results {
+			keys = append(keys, r.key())
+		}
+		return keys
+	}
+	mustParse := func(s string) time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	cursor := &api.SavedSearchCursor{
+		Key:           "1",
+		LatestResult:  mustParse("2021-10-01T10:00:00Z"),
+		SeenResults:   []string{"a@1"},
+		SlackThreadTS: "1633082400.000100",
+	}
+
+	t.Run("new results", func(t *testing.T) {
+		newResults, next := advanceCursor(cursor, []*gqlSearchResult{
+			result("b", "2", "2021-10-02T10:00:00Z"),
+			result("a", "1", "2021-10-01T10:00:00Z"),
+			result("a", "3", "2021-10-01T10:00:00Z"),
+		})
+		if got, want := keys(newResults), []string{"b@2", "a@3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got new results %v, want %v", got, want)
+		}
+		want := &api.SavedSearchCursor{
+			Key:           "1",
+			LatestResult:  mustParse("2021-10-02T10:00:00Z"),
+			SeenResults:   []string{"b@2", "a@1", "a@3"},
+			SlackThreadTS: "1633082400.000100",
+		}
+		if !reflect.DeepEqual(next, want) {
+			t.Errorf("got cursor %+v, want %+v", next, want)
+		}
+	})
+
+	t.Run("no new results", func(t *testing.T) {
+		newResults, next := advanceCursor(cursor, []*gqlSearchResult{
+			result("a", "1", "2021-10-01T10:00:00Z"),
+		})
+		if len(newResults) != 0 {
+			t.Errorf("got new results %v, want none", keys(newResults))
+		}
+		if !reflect.DeepEqual(next, cursor) {
+			t.Errorf("got cursor %+v, want %+v", next, cursor)
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		newResults, next := advanceCursor(cursor, nil)
+		if len(newResults) != 0 {
+			t.Errorf("got new results %v, want none", keys(newResults))
+		}
+		if !reflect.DeepEqual(next, cursor) {
+			t.Errorf("got cursor %+v, want %+v", next, cursor)
+		}
+	})
+}
@@ -30,6 +30,16 @@ func (n *notifier) emailNotify(ctx context.Context) {
 		return
 	}
 
+	var results []emailResult
+	for _, r := range n.listedResults() {
+		results = append(results, emailResult{
+			Title:   r.Commit.Repository.Name + "@" + r.Commit.AbbreviatedOID,
+			Subject: r.Commit.Subject,
+			Author:  r.Commit.Author.Person.DisplayName,
+			URL:     resultURL(r, utmSourceEmail),
+		})
+	}
+
 	// Send tx emails asynchronously.
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -45,25 +55,27 @@ func (n *notifier) emailNotify(ctx context.Context) {
 			}
 
 			plural := ""
-			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+			if len(n.results) != 1 {
 				plural = "s"
 			}
 			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
-				URL                    string
-				SavedSearchPageURL     string
-				Description            string
-				Query                  string
-				ApproximateResultCount string
-				Ownership              string
-				PluralResults          string
+				URL                string
+				SavedSearchPageURL string
+				Description        string
+				Query              string
+				ResultCount        int
+				Results            []emailResult
+				Ownership          string
+				PluralResults      string
 			}{
-				URL:                    searchURL(n.newQuery, utmSourceEmail),
-				SavedSearchPageURL:     savedSearchListPageURL(utmSourceEmail),
-				Description:            n.query.Description,
-				Query:                  n.query.Query,
-				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
-				Ownership:              ownership,
-				PluralResults:          plural,
+				URL:                searchURL(n.newQuery, utmSourceEmail),
+				SavedSearchPageURL: savedSearchListPageURL(utmSourceEmail),
+				Description:        n.query.Description,
+				Query:              n.query.Query,
+				ResultCount:        len(n.results),
+				Results:            results,
+				Ownership:          ownership,
+				PluralResults:      plural,
 			}); err != nil {
 				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
 			}
@@ -71,20 +83,35 @@ func (n *notifier) emailNotify(ctx context.Context) {
 	}()
 }
 
+// emailResult is a new result of a saved search listed in an email.
+type emailResult struct {
+	Title   string
+	Subject string
+	Author  string
+	URL     string
+}
+
 var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
-	Subject: `[{{.ApproximateResultCount}} new result{{.PluralResults}}] {{.Description}}`,
+	Subject: `[{{.ResultCount}} new result{{.PluralResults}}] {{.Description}}`,
 	Text: `
-{{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+{{.ResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
   "{{.Description}}"
-
+{{range .Results}}
+  {{.Title}}: {{.Subject}} ({{.Author}})
+  {{.URL}}
+{{end}}
 View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
 `,
 	HTML: `
-<strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+<strong>{{.ResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
 
+<ul>
+{{range .Results}}  <li><a href="{{.URL}}">{{.Title}}</a>: {{.Subject}} ({{.Author}})</li>
+{{end}}</ul>
+
 <p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
 
 <p><a href="{{.SavedSearchPageURL}}">Edit your saved searches on Sourcegraph</a></p>
@@ -4,10 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/url"
-	"runtime"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -79,6 +77,8 @@ const gqlSearchQuery = `query Search(
 						}
 						oid
 						abbreviatedOID
+						subject
+						url
 						author {
 							person {
 								displayName
@@ -113,13 +113,53 @@ type gqlSearchResponse struct {
 				ApproximateResultCount string
 				Cloning                []*api.Repo
 				Timedout               []*api.Repo
-				Results                []interface{}
+				Results                []*gqlSearchResult
 			}
 		}
 	}
 	Errors []interface{}
 }
 
+// gqlSearchResult is a search result of gqlSearchQuery. Only the fields of
+// commit search results are decoded, because the query-runner only runs
+// type:diff and type:commit queries.
+type gqlSearchResult struct {
+	Typename       string `json:"__typename"`
+	MessagePreview *gqlHighlightedString
+	DiffPreview    *gqlHighlightedString
+	Commit         *gqlCommit
+}
+
+type gqlHighlightedString struct {
+	Value string
+}
+
+type gqlCommit struct {
+	Repository struct {
+		Name string
+	}
+	OID            string
+	AbbreviatedOID string
+	Subject        string
+	URL            string
+	Author         struct {
+		Person struct {
+			DisplayName string
+			AvatarURL   string
+		}
+		Date string
+	}
+	Message string
+}
+
+// key identifies the result across searches.
+func (r *gqlSearchResult) key() string {
+	if r.Commit == nil {
+		return ""
+	}
+	return r.Commit.Repository.Name + "@" + r.Commit.OID
+}
+
 func search(ctx context.Context, query string) (*gqlSearchResponse, error) {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(graphQLQuery{
@@ -170,36 +210,14 @@ func gqlURL(queryName string) (string, error) {
 }
 
 // extractTime extracts the time from the given search result.
-func extractTime(result interface{}) (t *time.Time, err error) {
-	// Use recover because we assume the data structure here a lot, for less
-	// error checking.
-	defer func() {
-		if r := recover(); r != nil {
-			// Same as net/http
-			const size = 64 << 10
-			buf := make([]byte, size)
-			buf = buf[:runtime.Stack(buf, false)]
-			log.Printf("failed to extract time from search result: %v\n%s", r, buf)
-		}
-		err = errors.Errorf("failed to extract time from search result")
-	}()
-
-	m := result.(map[string]interface{})
-	typeName := m["__typename"].(string)
-	switch typeName {
-	case "CommitSearchResult":
-		commit := m["commit"].(map[string]interface{})
-		author := commit["author"].(map[string]interface{})
-		date := author["date"].(string)
-
-		// For now, our graphql API commit authorship date is in Go default time format.
-		goTimeFormat := "2006-01-02 15:04:05.999999999 -0700 MST"
-		t, err := time.Parse(date, goTimeFormat)
-		if err != nil {
-			return nil, err
-		}
-		return &t, nil
-	default:
-		return nil, errors.Errorf("unexpected result __typename %q", typeName)
+func extractTime(result *gqlSearchResult) (time.Time, error) {
+	if result.Typename != "CommitSearchResult" || result.Commit == nil {
+		return time.Time{}, errors.Errorf("unexpected result __typename %q", result.Typename)
+	}
+	// Signature dates are formatted as RFC 3339 by our GraphQL API.
+	t, err := time.Parse(time.RFC3339, result.Commit.Author.Date)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to extract time from search result")
 	}
+	return t, nil
 }
@@ -12,6 +12,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -100,6 +101,11 @@ var executor = &executorT{}
 
 type executorT struct {
 	forceRunInterval *time.Duration
+
+	// notifying holds the keys of the saved searches whose notifications are
+	// being sent. They are not run in the meantime, since sending the
+	// notifications may update their cursor.
+	notifying sync.Map
 }
 
 func (e *executorT) run(ctx context.Context) error {
@@ -151,7 +157,7 @@ func (e *executorT) run(ctx context.Context) error {
 // runQuery runs the given query if an appropriate amount of time has elapsed
 // since it last ran.
 func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery) error {
-	if !query.Notify && !query.NotifySlack {
+	if !query.Notify && !query.NotifySlack && !query.NotifyTeams {
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
@@ -160,6 +166,9 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		// queries, since those do not support the after:"time" operator.
 		return nil
 	}
+	if _, ok := e.notifying.Load(spec.Key); ok {
+		return nil
+	}
 
 	info, err := api.InternalClient.SavedQueriesGetInfo(ctx, query.Query)
 	if err != nil {
@@ -188,21 +197,24 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		}
 	}
 
-	// Construct a new query which finds search results introduced after the
-	// last time we queried.
-	var latestKnownResult time.Time
-	if info != nil {
-		latestKnownResult = info.LatestResult
-	} else {
-		// We've never executed this search query before, so use the current
+	cursor, err := api.InternalClient.SavedQueriesGetCursor(ctx, spec.Key)
+	if err != nil {
+		return errors.Wrap(err, "SavedQueriesGetCursor")
+	}
+	if cursor == nil {
+		// We've never executed this saved search before, so use the current
 		// time. We'll most certainly find nothing, which is okay.
-		latestKnownResult = time.Now()
+		cursor = &api.SavedSearchCursor{Key: spec.Key, LatestResult: time.Now()}
 	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
+
+	// Construct a new query which finds search results introduced after the
+	// latest result subscribers were notified of.
+	afterTime := cursor.LatestResult.UTC().Format(time.RFC3339)
 	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
 	if debugPretendSavedQueryResultsExist {
 		debugPretendSavedQueryResultsExist = false
 		newQuery = query.Query
+		cursor.SeenResults = nil
 	}
 
 	// Perform the search and mark the saved query as having been executed in
@@ -211,10 +223,15 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// constantly and potentially causing harm to the system. We'll retry at
 	// our normal interval, regardless of errors.
 	v, execDuration, searchErr := performSearch(ctx, newQuery)
+	var newResults []*gqlSearchResult
+	next := cursor
+	if searchErr == nil {
+		newResults, next = advanceCursor(cursor, v.Data.Search.Results.Results)
+	}
 	if err := api.InternalClient.SavedQueriesSetInfo(ctx, &api.SavedQueryInfo{
 		Query:        query.Query,
 		LastExecuted: time.Now(),
-		LatestResult: latestResultTime(info, v, searchErr),
+		LatestResult: next.LatestResult,
 		ExecDuration: execDuration,
 	}); err != nil {
 		return errors.Wrap(err, "SavedQueriesSetInfo")
@@ -224,13 +241,33 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		return searchErr
 	}
 
+	// Advance the cursor before sending notifications, so that failing to
+	// send them doesn't cause the same results to be sent over and over.
+	if err := api.InternalClient.SavedQueriesSetCursor(ctx, next); err != nil {
+		return errors.Wrap(err, "SavedQueriesSetCursor")
+	}
+	if len(newResults) == 0 {
+		return nil
+	}
+
 	// Send notifications for new search results in a separate goroutine, so
 	// that we don't block other search queries from running in sequence (which
 	// is done intentionally, to ensure no overloading of searcher/gitserver).
+	e.notifying.Store(spec.Key, struct{}{})
 	go func() {
-		if err := notify(context.Background(), spec, query, newQuery, v); err != nil {
+		defer e.notifying.Delete(spec.Key)
+
+		ctx := context.Background()
+		threadTS, err := notify(ctx, spec, query, newQuery, newResults, next.SlackThreadTS)
+		if err != nil {
 			log15.Error("executor: failed to send notifications", "error", err)
 		}
+		if threadTS != next.SlackThreadTS {
+			next.SlackThreadTS = threadTS
+			if err := api.InternalClient.SavedQueriesSetCursor(ctx, next); err != nil {
+				log15.Error("executor: failed to store Slack thread of saved search", "error", err)
+			}
+		}
 	}()
 	return nil
 }
@@ -267,41 +304,20 @@ func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, exe
 	}
 }
 
-func latestResultTime(prevInfo *api.SavedQueryInfo, v *gqlSearchResponse, searchErr error) time.Time {
-	if searchErr != nil || len(v.Data.Search.Results.Results) == 0 {
-		// Error performing the search, or there were no results. Assume the
-		// previous info's result time.
-		if prevInfo != nil {
-			return prevInfo.LatestResult
-		}
-		return time.Now()
-	}
-
-	// Results are ordered chronologically, so first result is the latest.
-	t, err := extractTime(v.Data.Search.Results.Results[0])
-	if err != nil {
-		// Error already logged by extractTime.
-		return time.Now()
-	}
-	return *t
-}
-
 var externalURL *url.URL
 
-// notify handles sending notifications for new search results.
-func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse) error {
-	if len(results.Data.Search.Results.Results) == 0 {
-		return nil
-	}
-	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "description", query.Description)
+// notify handles sending notifications for new search results. It returns the
+// timestamp of the Slack message that notifications of the saved search are
+// threaded under, given the one of the previous notifications.
+func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results []*gqlSearchResult, slackThreadTS string) (string, error) {
+	log15.Info("sending notifications", "new_results", len(results), "description", query.Description)
 
 	// Determine which users to notify.
 	recipients, err := getNotificationRecipients(ctx, spec, query)
 	if err != nil {
-		return err
+		return slackThreadTS, err
 	}
 
-	// Send slack notifications.
 	n := &notifier{
 		spec:       spec,
 		query:      query,
@@ -310,23 +326,49 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		recipients: recipients,
 	}
 
-	// Send Slack and email notifications.
-	n.slackNotify(ctx)
+	// Send Slack, Microsoft Teams and email notifications.
+	slackThreadTS = n.slackNotify(ctx, slackThreadTS)
+	n.teamsNotify(ctx)
 	n.emailNotify(ctx)
-	return nil
+	return slackThreadTS, nil
 }
 
 type notifier struct {
 	spec       api.SavedQueryIDSpec
 	query      api.ConfigSavedQuery
 	newQuery   string
-	results    *gqlSearchResponse
+	results    []*gqlSearchResult
 	recipients recipients
 }
 
+// maxNotificationResults is the maximum number of new results listed in a
+// notification. Notifications link to the search for the rest.
+const maxNotificationResults = 10
+
+// listedResults returns the new results that are listed in notifications.
+func (n *notifier) listedResults() []*gqlSearchResult {
+	var results []*gqlSearchResult
+	for _, r := range n.results {
+		if r.Commit == nil {
+			continue
+		}
+		if len(results) == maxNotificationResults {
+			break
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// resultURL returns the URL of the commit of r.
+func resultURL(r *gqlSearchResult, utmSource string) string {
+	return sourcegraphURL(r.Commit.URL, "", utmSource)
+}
+
 const (
 	utmSourceEmail = "saved-search-email"
 	utmSourceSlack = "saved-search-slack"
+	utmSourceTeams = "saved-search-teams"
 )
 
 func searchURL(query, utmSource string) string {
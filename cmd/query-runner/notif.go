@@ -26,10 +26,11 @@ type recipient struct {
 	spec  recipientSpec // the recipient's identity
 	email bool          // send an email to the recipient
 	slack bool          // post a Slack message to the recipient
+	teams bool          // post a Microsoft Teams message to the recipient
 }
 
 func (r *recipient) String() string {
-	return fmt.Sprintf("{%s email:%v slack:%v}", r.spec, r.email, r.slack)
+	return fmt.Sprintf("{%s email:%v slack:%v teams:%v}", r.spec, r.email, r.slack, r.teams)
 }
 
 // getNotificationRecipients retrieves the list of recipients who should receive notifications for
@@ -44,6 +45,7 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 			spec:  recipientSpec{userID: *spec.Subject.User},
 			email: query.Notify,
 			slack: query.NotifySlack,
+			teams: query.NotifyTeams,
 		})
 
 	case spec.Subject.Org != nil:
@@ -64,6 +66,7 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 		recipients.add(recipient{
 			spec:  recipientSpec{orgID: *spec.Subject.Org},
 			slack: query.NotifySlack,
+			teams: query.NotifyTeams,
 		})
 	}
 
@@ -80,6 +83,7 @@ func (rs *recipients) add(r recipient) {
 			// Merge into existing recipient.
 			r2.email = r2.email || r.email
 			r2.slack = r2.slack || r.slack
+			r2.teams = r2.teams || r.teams
 			return
 		}
 	}
@@ -116,6 +120,7 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			spec:  spec,
 			email: old.email && !new.email,
 			slack: old.slack && !new.slack,
+			teams: old.teams && !new.teams,
 		}
 		if *removed == empty {
 			removed = nil
@@ -124,6 +129,7 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			spec:  spec,
 			email: new.email && !old.email,
 			slack: new.slack && !old.slack,
+			teams: new.teams && !old.teams,
 		}
 		if *added == empty {
 			added = nil
@@ -32,6 +32,23 @@ func TestGetNotificationRecipients(t *testing.T) {
 		}
 	})
 
+	t.Run("user with Microsoft Teams", func(t *testing.T) {
+		recipients, err := getNotificationRecipients(ctx,
+			api.SavedQueryIDSpec{
+				Subject: api.SettingsSubject{User: &onetwothree},
+			},
+			api.ConfigSavedQuery{
+				NotifyTeams: true,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []*recipient{{spec: recipientSpec{userID: 123}, teams: true}}; !reflect.DeepEqual(recipients, want) {
+			t.Errorf("got %+v, want %+v", recipients, want)
+		}
+	})
+
 	t.Run("org", func(t *testing.T) {
 		api.MockOrgsListUsers = func(orgID int32) (users []int32, err error) {
 			if want := int32(123); orgID != want {
@@ -128,6 +145,18 @@ func TestDiffNotificationRecipients(t *testing.T) {
 			wantRemoved: recipients{{spec: recipientSpec{userID: 1}, email: true}},
 			wantAdded:   recipients{{spec: recipientSpec{orgID: 2}, slack: true}},
 		},
+		{
+			old:         recipients{{spec: recipientSpec{orgID: 2}, slack: true}},
+			new:         recipients{{spec: recipientSpec{orgID: 2}, slack: true, teams: true}},
+			wantRemoved: nil,
+			wantAdded:   recipients{{spec: recipientSpec{orgID: 2}, teams: true}},
+		},
+		{
+			old:         recipients{{spec: recipientSpec{orgID: 2}, teams: true}},
+			new:         recipients{{spec: recipientSpec{orgID: 2}, slack: true}},
+			wantRemoved: recipients{{spec: recipientSpec{orgID: 2}, teams: true}},
+			wantAdded:   recipients{{spec: recipientSpec{orgID: 2}, slack: true}},
+		},
 	}
 	for i, test := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
@@ -3,41 +3,94 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/slack"
 )
 
-func (n *notifier) slackNotify(ctx context.Context) {
+// slackNotify posts the new results to Slack, in the thread of the message
+// with the timestamp threadTS if it is set. It returns the timestamp of the
+// message the notifications of the saved search are threaded under from now on.
+func (n *notifier) slackNotify(ctx context.Context, threadTS string) string {
+	payload := n.slackPayload()
+	for _, recipient := range n.recipients {
+		ts, err := slackNotifyThreaded(ctx, recipient, payload, n.query, threadTS)
+		if err != nil {
+			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", payload.Text, "error", err)
+			continue
+		}
+		if ts != "" {
+			threadTS = ts
+		}
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "SavedSearchSlackNotificationSent", "results")
+	return threadTS
+}
+
+// slackPayload returns the Slack message for the new results, which has an
+// attachment for each of the listed results.
+func (n *notifier) slackPayload() *slack.Payload {
 	plural := ""
-	if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+	if len(n.results) != 1 {
 		plural = "s"
 	}
-
-	text := fmt.Sprintf(`*%s* new result%s found for saved search <%s|"%s">`,
-		n.results.Data.Search.Results.ApproximateResultCount,
+	text := fmt.Sprintf(`*%d* new result%s found for saved search <%s|"%s">`,
+		len(n.results),
 		plural,
 		searchURL(n.newQuery, utmSourceSlack),
-		n.query.Description,
+		escapeSlack(n.query.Description),
 	)
-	for _, recipient := range n.recipients {
-		if err := slackNotify(ctx, recipient, text, n.query.SlackWebhookURL); err != nil {
-			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
+
+	results := n.listedResults()
+	attachments := make([]*slack.Attachment, 0, len(results))
+	for _, r := range results {
+		title := r.Commit.Repository.Name + "@" + r.Commit.AbbreviatedOID
+		text := escapeSlack(r.Commit.Subject)
+		if r.DiffPreview != nil {
+			text = "```" + escapeSlack(truncateLines(r.DiffPreview.Value, maxSlackDiffLines)) + "```"
 		}
+		var timestamp int64
+		if t, err := extractTime(r); err == nil {
+			timestamp = t.Unix()
+		}
+		attachments = append(attachments, &slack.Attachment{
+			AuthorIcon: r.Commit.Author.Person.AvatarURL,
+			AuthorName: r.Commit.Author.Person.DisplayName,
+			Fallback:   title + ": " + r.Commit.Subject,
+			MarkdownIn: []string{"text"},
+			Text:       text,
+			Timestamp:  timestamp,
+			Title:      title,
+			TitleLink:  resultURL(r, utmSourceSlack),
+		})
+	}
+
+	return &slack.Payload{
+		Username:    "saved-search-bot",
+		IconEmoji:   ":mag:",
+		UnfurlLinks: false,
+		UnfurlMedia: false,
+		Text:        text,
+		Attachments: attachments,
 	}
-	// TODO(Dan): find all users in the recipient list and log events for all of them
-	logEvent(0, "SavedSearchSlackNotificationSent", "results")
 }
 
+// maxSlackDiffLines is the maximum number of lines of a diff shown in a Slack
+// attachment.
+const maxSlackDiffLines = 20
+
 func slackNotifySubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
 	text := fmt.Sprintf(`Slack notifications enabled for the saved search <%s|"%s">. Notifications will be sent here when new results are available.`,
 		searchURL(query.Config.Query, utmSourceSlack),
 		query.Config.Description,
 	)
-	if err := slackNotify(ctx, recipient, text, query.Config.SlackWebhookURL); err != nil {
+	if err := slackNotify(ctx, recipient, text, query.Config); err != nil {
 		return err
 	}
 	// TODO(Dan): find all users in the recipient list and log events for all of them
@@ -50,7 +103,7 @@ func slackNotifyUnsubscribed(ctx context.Context, recipient *recipient, query ap
 		searchURL(query.Config.Query, utmSourceSlack),
 		query.Config.Description,
 	)
-	if err := slackNotify(ctx, recipient, text, query.Config.SlackWebhookURL); err != nil {
+	if err := slackNotify(ctx, recipient, text, query.Config); err != nil {
 		return err
 	}
 	// TODO(Dan): find all users in the recipient list and log events for all of them
@@ -58,15 +111,15 @@ func slackNotifyUnsubscribed(ctx context.Context, recipient *recipient, query ap
 	return nil
 }
 
-func slackNotify(ctx context.Context, recipient *recipient, text string, slackWebhookURL *string) error {
+func slackNotify(ctx context.Context, recipient *recipient, text string, query api.ConfigSavedQuery) error {
 	if !recipient.slack {
 		return nil
 	}
 
-	if slackWebhookURL == nil || *slackWebhookURL == "" {
-		return errors.Errorf("unable to send Slack notification because recipient (%s) has no Slack webhook URL configured", recipient.spec)
+	client, err := newSlackClient(recipient, query)
+	if err != nil {
+		return err
 	}
-
 	payload := &slack.Payload{
 		Username:    "saved-search-bot",
 		IconEmoji:   ":mag:",
@@ -74,6 +127,62 @@ func slackNotify(ctx context.Context, recipient *recipient, text string, slackWe
 		UnfurlMedia: false,
 		Text:        text,
 	}
-	client := slack.New(*slackWebhookURL)
 	return client.Post(ctx, payload)
 }
+
+// slackNotifyThreaded posts payload in the thread of the message with the
+// timestamp threadTS, or as a new message if threadTS is empty or replying in
+// the thread fails, for example because the Slack channel of the saved search
+// was changed. It returns the timestamp of the message the payload was
+// threaded under, which is only known if the payload is posted as the Slack
+// bot of the site. See newSlackClient.
+func slackNotifyThreaded(ctx context.Context, recipient *recipient, payload *slack.Payload, query api.ConfigSavedQuery, threadTS string) (string, error) {
+	if !recipient.slack {
+		return "", nil
+	}
+
+	client, err := newSlackClient(recipient, query)
+	if err != nil {
+		return "", err
+	}
+	if threadTS != "" && client.BotToken != "" {
+		reply := *payload
+		reply.ThreadTS = threadTS
+		_, err := client.PostMessage(ctx, &reply)
+		if err == nil {
+			return threadTS, nil
+		}
+		log15.Warn("Failed to reply in Slack thread of saved search, starting a new thread.", "recipient", recipient, "error", err)
+	}
+	return client.PostMessage(ctx, payload)
+}
+
+// newSlackClient returns the Slack client notifications of the saved search
+// are posted with. If the site configures a Slack bot token and the saved
+// search has a Slack channel, they are posted to the channel as the bot, which
+// allows threading them. Otherwise they are posted to the Slack webhook URL of
+// the saved search.
+func newSlackClient(recipient *recipient, query api.ConfigSavedQuery) (*slack.Client, error) {
+	if botToken := conf.Get().SavedSearchesSlackBotToken; botToken != "" && query.SlackChannel != nil && *query.SlackChannel != "" {
+		return slack.NewBot(botToken, *query.SlackChannel), nil
+	}
+
+	if query.SlackWebhookURL == nil || *query.SlackWebhookURL == "" {
+		return nil, errors.Errorf("unable to send Slack notification because recipient (%s) has no Slack webhook URL configured", recipient.spec)
+	}
+	return slack.New(*query.SlackWebhookURL), nil
+}
+
+// escapeSlack escapes the control characters of Slack's message formatting.
+func escapeSlack(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// truncateLines returns the first n lines of s.
+func truncateLines(s string, n int) string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "") + "…"
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// teamsMessageCard is a Microsoft Teams message card, the message format of
+// incoming webhooks, defined at:
+// https://docs.microsoft.com/outlook/actionable-messages/message-card-reference
+type teamsMessageCard struct {
+	Type            string                `json:"@type"`
+	Context         string                `json:"@context"`
+	Summary         string                `json:"summary"`
+	ThemeColor      string                `json:"themeColor,omitempty"`
+	Title           string                `json:"title,omitempty"`
+	Text            string                `json:"text,omitempty"`
+	Sections        []*teamsCardSection   `json:"sections,omitempty"`
+	PotentialAction []*teamsOpenURIAction `json:"potentialAction,omitempty"`
+}
+
+type teamsCardSection struct {
+	ActivityTitle    string `json:"activityTitle,omitempty"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+	ActivityImage    string `json:"activityImage,omitempty"`
+	Text             string `json:"text,omitempty"`
+}
+
+type teamsOpenURIAction struct {
+	Type    string           `json:"@type"`
+	Name    string           `json:"name"`
+	Targets []teamsURITarget `json:"targets"`
+}
+
+type teamsURITarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+func newTeamsMessageCard(summary, text, url, urlName string) *teamsMessageCard {
+	return &teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    summary,
+		ThemeColor: "00B4F2",
+		Text:       text,
+		PotentialAction: []*teamsOpenURIAction{{
+			Type:    "OpenUri",
+			Name:    urlName,
+			Targets: []teamsURITarget{{OS: "default", URI: url}},
+		}},
+	}
+}
+
+func (n *notifier) teamsNotify(ctx context.Context) {
+	card := n.teamsCard()
+	for _, recipient := range n.recipients {
+		if err := teamsNotify(ctx, recipient, card, n.query.TeamsWebhookURL); err != nil {
+			log15.Error("Failed to post Microsoft Teams notification message.", "recipient", recipient, "text", card.Text, "error", err)
+		}
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "SavedSearchTeamsNotificationSent", "results")
+}
+
+// teamsCard returns the Microsoft Teams message for the new results, which
+// has a section for each of the listed results.
+func (n *notifier) teamsCard() *teamsMessageCard {
+	plural := ""
+	if len(n.results) != 1 {
+		plural = "s"
+	}
+	summary := fmt.Sprintf(`%d new result%s found for saved search "%s"`, len(n.results), plural, n.query.Description)
+	card := newTeamsMessageCard(
+		summary,
+		fmt.Sprintf(`**%d** new result%s found for saved search "%s"`, len(n.results), plural, escapeTeams(n.query.Description)),
+		searchURL(n.newQuery, utmSourceTeams),
+		"View results",
+	)
+	for _, r := range n.listedResults() {
+		subtitle := r.Commit.Author.Person.DisplayName
+		if t, err := extractTime(r); err == nil {
+			subtitle += " committed on " + t.Format("2006-01-02")
+		}
+		card.Sections = append(card.Sections, &teamsCardSection{
+			ActivityTitle:    fmt.Sprintf("[%s@%s](%s)", r.Commit.Repository.Name, r.Commit.AbbreviatedOID, resultURL(r, utmSourceTeams)),
+			ActivitySubtitle: subtitle,
+			ActivityImage:    r.Commit.Author.Person.AvatarURL,
+			Text:             escapeTeams(r.Commit.Subject),
+		})
+	}
+	return card
+}
+
+func teamsNotifySubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
+	card := newTeamsMessageCard(
+		fmt.Sprintf(`Microsoft Teams notifications enabled for the saved search "%s"`, query.Config.Description),
+		fmt.Sprintf(`Microsoft Teams notifications enabled for the saved search "%s". Notifications will be sent here when new results are available.`, escapeTeams(query.Config.Description)),
+		searchURL(query.Config.Query, utmSourceTeams),
+		"View saved search",
+	)
+	if err := teamsNotify(ctx, recipient, card, query.Config.TeamsWebhookURL); err != nil {
+		return err
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "SavedSearchTeamsNotificationSent", "enabled")
+	return nil
+}
+
+func teamsNotifyUnsubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
+	card := newTeamsMessageCard(
+		fmt.Sprintf(`Microsoft Teams notifications for the saved search "%s" disabled`, query.Config.Description),
+		fmt.Sprintf(`Microsoft Teams notifications for the saved search "%s" disabled.`, escapeTeams(query.Config.Description)),
+		searchURL(query.Config.Query, utmSourceTeams),
+		"View saved search",
+	)
+	if err := teamsNotify(ctx, recipient, card, query.Config.TeamsWebhookURL); err != nil {
+		return err
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "SavedSearchTeamsNotificationSent", "disabled")
+	return nil
+}
+
+func teamsNotify(ctx context.Context, recipient *recipient, card *teamsMessageCard, teamsWebhookURL *string) error {
+	if !recipient.teams {
+		return nil
+	}
+
+	if teamsWebhookURL == nil || *teamsWebhookURL == "" {
+		return errors.Errorf("unable to send Microsoft Teams notification because recipient (%s) has no Microsoft Teams webhook URL configured", recipient.spec)
+	}
+
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		return errors.Wrap(err, "teams: marshal json")
+	}
+	req, err := http.NewRequest("POST", *teamsWebhookURL, bytes.NewReader(cardJSON))
+	if err != nil {
+		return errors.Wrap(err, "teams: create post request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	resp, err := httpcli.ExternalDoer.Do(req.WithContext(timeoutCtx))
+	if err != nil {
+		return errors.Wrap(err, "teams: http request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("teams: %s failed with %d %s", cardJSON, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// escapeTeams escapes the characters of the Markdown subset supported by
+// Microsoft Teams message cards.
+func escapeTeams(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;").Replace(s)
+}
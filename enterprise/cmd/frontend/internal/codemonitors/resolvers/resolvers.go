@@ -169,12 +169,23 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}
 
 	// Get all action IDs of the monitor.
-	actionIDs, err := r.actionIDsForMonitorIDInt64(ctx, monitorID)
+	actions, err := r.allActions(ctx, nil, monitorID)
 	if err != nil {
 		return nil, err
 	}
+	actionIDs := make([]graphql.ID, 0, len(actions))
+	for _, a := range actions {
+		id, err := actionID(a)
+		if err != nil {
+			return nil, err
+		}
+		actionIDs = append(actionIDs, id)
+	}
 
 	toCreate, toDelete, err := splitActionIDs(ctx, args, actionIDs)
+	if err != nil {
+		return nil, err
+	}
 	if len(toDelete) == len(actionIDs) {
 		return nil, errors.Errorf("you tried to delete all actions, but every monitor must be connected to at least 1 action")
 	}
@@ -187,7 +198,7 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}
 	defer func() { err = tx.store.Done(err) }()
 
-	err = tx.store.DeleteActionsInt64(ctx, toDelete, monitorID)
+	err = tx.deleteActions(ctx, toDelete, monitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -257,92 +268,75 @@ func sendTestEmail(ctx context.Context, recipient graphql.ID, description string
 	return email.SendEmailForNewSearchResult(ctx, userID, data)
 }
 
-func (r *Resolver) actionIDsForMonitorIDInt64(ctx context.Context, monitorID int64) (actionIDs []graphql.ID, err error) {
-	limit := 50
-	var (
-		ids   []graphql.ID
-		after *string
-		q     *sqlf.Query
-	)
-	// Paging.
-	for {
-		q, err = r.store.ReadActionEmailQuery(ctx, monitorID, &graphqlbackend.ListActionArgs{
-			First: int32(limit),
-			After: after,
-		})
-		if err != nil {
-			return nil, err
-		}
-		es, cur, err := r.actionIDsForMonitorIDINT64SinglePage(ctx, q, limit)
-		if err != nil {
-			return nil, err
-		}
-		ids = append(ids, es...)
-		if cur == nil {
-			break
-		}
-		after = cur
-	}
-	return ids, nil
-}
-
-func (r *Resolver) actionIDsForMonitorIDINT64SinglePage(ctx context.Context, q *sqlf.Query, limit int) (ids []graphql.ID, cursor *string, err error) {
-	var rows *sql.Rows
-	rows, err = r.store.Query(ctx, q)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer rows.Close()
-
-	var es []*cm.MonitorEmail
-	es, err = cm.ScanEmails(rows)
-	if err != nil {
-		return nil, nil, err
-	}
-	ids = make([]graphql.ID, 0, len(es))
-	for _, e := range es {
-		ids = append(ids, (&monitorEmail{MonitorEmail: e}).ID())
-	}
-
-	// Set the cursor if the result size equals limit.
-	if len(ids) == limit {
-		stringID := string(ids[len(ids)-1])
-		cursor = &stringID
-	}
-	return ids, cursor, nil
-}
-
 // splitActionIDs splits actions into three buckets: create, delete and update.
 // Note: args is mutated. After splitActionIDs, args only contains actions to be updated.
-func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []int64, err error) {
+func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []graphql.ID, err error) {
 	aMap := make(map[graphql.ID]struct{}, len(actionIDs))
 	for _, id := range actionIDs {
 		aMap[id] = struct{}{}
 	}
 	var toUpdateActions []*graphqlbackend.EditActionArgs
-	for _, a := range args.Actions {
-		if a.Email.Id == nil {
-			toCreate = append(toCreate, &graphqlbackend.CreateActionArgs{Email: a.Email.Update})
+	for i, a := range args.Actions {
+		var (
+			id     *graphql.ID
+			create *graphqlbackend.CreateActionArgs
+		)
+		switch {
+		case a.Email != nil:
+			id, create = a.Email.Id, &graphqlbackend.CreateActionArgs{Email: a.Email.Update}
+		case a.SlackWebhook != nil:
+			id, create = a.SlackWebhook.Id, &graphqlbackend.CreateActionArgs{SlackWebhook: a.SlackWebhook.Update}
+		case a.Webhook != nil:
+			id, create = a.Webhook.Id, &graphqlbackend.CreateActionArgs{Webhook: a.Webhook.Update}
+		default:
+			return nil, nil, errors.Errorf("missing action object for action %d", i)
+		}
+		if id == nil {
+			toCreate = append(toCreate, create)
 			continue
 		}
-		if _, ok := aMap[*a.Email.Id]; !ok {
-			return nil, nil, errors.Errorf("unknown ID=%s for action", *a.Email.Id)
+		if _, ok := aMap[*id]; !ok {
+			return nil, nil, errors.Errorf("unknown ID=%s for action", *id)
 		}
 		toUpdateActions = append(toUpdateActions, a)
-		delete(aMap, *a.Email.Id)
+		delete(aMap, *id)
 	}
-	var actionID int64
 	for k := range aMap {
-		err = relay.UnmarshalSpec(k, &actionID)
-		if err != nil {
-			return nil, nil, err
-		}
-		toDelete = append(toDelete, actionID)
+		toDelete = append(toDelete, k)
 	}
 	args.Actions = toUpdateActions
 	return toCreate, toDelete, nil
 }
 
+// deleteActions deletes the actions with the given IDs, which can be of any
+// action kind.
+func (r *Resolver) deleteActions(ctx context.Context, actionIDs []graphql.ID, monitorID int64) error {
+	var emailIDs, slackWebhookIDs, webhookIDs []int64
+	for _, id := range actionIDs {
+		var intID int64
+		if err := relay.UnmarshalSpec(id, &intID); err != nil {
+			return err
+		}
+		switch kind := relay.UnmarshalKind(id); kind {
+		case monitorActionEmailKind:
+			emailIDs = append(emailIDs, intID)
+		case monitorActionSlackWebhookKind:
+			slackWebhookIDs = append(slackWebhookIDs, intID)
+		case monitorActionWebhookKind:
+			webhookIDs = append(webhookIDs, intID)
+		default:
+			return errors.Errorf("unknown action kind %q", kind)
+		}
+	}
+	if err := r.store.DeleteActionsInt64(ctx, emailIDs, monitorID); err != nil {
+		return err
+	}
+	if err := r.store.DeleteActionSlackWebhooks(ctx, slackWebhookIDs, monitorID); err != nil {
+		return err
+	}
+	return r.store.DeleteActionWebhooks(ctx, webhookIDs, monitorID)
+}
+
 func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs) (m graphqlbackend.MonitorResolver, err error) {
 	// Update monitor.
 	var mo *cm.Monitor
@@ -365,24 +359,36 @@ func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	var emailID int64
 	var e *cm.MonitorEmail
 	for i, action := range args.Actions {
-		if action.Email == nil {
-			return nil, errors.Errorf("missing email object for action %d", i)
-		}
-		err = relay.UnmarshalSpec(*action.Email.Id, &emailID)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.DeleteRecipients(ctx, emailID)
-		if err != nil {
-			return nil, err
-		}
-		e, err = r.store.UpdateActionEmail(ctx, mo.ID, action)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
-		if err != nil {
-			return nil, err
+		switch {
+		case action.Email != nil:
+			err = relay.UnmarshalSpec(*action.Email.Id, &emailID)
+			if err != nil {
+				return nil, err
+			}
+			err = r.store.DeleteRecipients(ctx, emailID)
+			if err != nil {
+				return nil, err
+			}
+			e, err = r.store.UpdateActionEmail(ctx, mo.ID, action)
+			if err != nil {
+				return nil, err
+			}
+			err = r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
+			if err != nil {
+				return nil, err
+			}
+		case action.SlackWebhook != nil:
+			_, err = r.store.UpdateActionSlackWebhook(ctx, mo.ID, action.SlackWebhook)
+			if err != nil {
+				return nil, err
+			}
+		case action.Webhook != nil:
+			_, err = r.store.UpdateActionWebhook(ctx, mo.ID, action.Webhook)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.Errorf("missing action object for action %d", i)
 		}
 	}
 	return &monitor{
@@ -526,8 +532,11 @@ const (
 	monitorTriggerQueryKind         = "CodeMonitorTriggerQuery"
 	monitorTriggerEventKind         = "CodeMonitorTriggerEvent"
 	monitorActionEmailKind          = "CodeMonitorActionEmail"
+	monitorActionSlackWebhookKind   = "CodeMonitorActionSlackWebhook"
+	monitorActionWebhookKind        = "CodeMonitorActionWebhook"
 	monitorActionEventKind          = "CodeMonitorActionEmailEvent"
 	monitorActionEmailRecipientKind = "CodeMonitorActionEmailRecipient"
+	monitorResultKind               = "CodeMonitorResult"
 )
 
 func (m *monitor) ID() graphql.ID {
@@ -550,6 +559,13 @@ func (m *monitor) Enabled() bool {
 	return m.Monitor.Enabled
 }
 
+func (m *monitor) QuietHours() graphqlbackend.MonitorQuietHoursResolver {
+	if m.Monitor.QuietHours == nil {
+		return nil
+	}
+	return &monitorQuietHours{m.Monitor.QuietHours}
+}
+
 func (m *monitor) Owner(ctx context.Context) (n graphqlbackend.NamespaceResolver, err error) {
 	if m.NamespaceOrgID == nil {
 		n.Namespace, err = graphqlbackend.UserByIDInt32(ctx, m.store.Handle().DB(), *m.NamespaceUserID)
@@ -571,27 +587,66 @@ func (m *monitor) Actions(ctx context.Context, args *graphqlbackend.ListActionAr
 	return m.actionConnectionResolverWithTriggerID(ctx, nil, m.Monitor.ID, args)
 }
 
+func (m *monitor) ResultHistory(ctx context.Context, args *graphqlbackend.ListMonitorResultsArgs) (graphqlbackend.MonitorResultConnectionResolver, error) {
+	rs, err := m.store.ResultHistoryForMonitorIDInt64(ctx, m.Monitor.ID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalCountResultHistory(ctx, m.Monitor.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]graphqlbackend.MonitorResultResolver, 0, len(rs))
+	for _, r := range rs {
+		results = append(results, &monitorResult{r})
+	}
+	return &monitorResultConnection{results: results, totalCount: totalCount}, nil
+}
+
 func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, triggerEventID *int, monitorID int64, args *graphqlbackend.ListActionArgs) (graphqlbackend.MonitorActionConnectionResolver, error) {
-	// For now, we only support emails as actions. Once we add other actions such as
-	// webhooks, we have to query those tables here too.
-	q, err := r.store.ReadActionEmailQuery(ctx, monitorID, args)
+	actions, err := r.allActions(ctx, triggerEventID, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	rows, err := r.store.Query(ctx, q)
+	totalCount := int32(len(actions))
+
+	// A monitor only has a handful of actions of different kinds, so we page
+	// through them in memory.
+	if args.After != nil {
+		for i, a := range actions {
+			id, err := actionID(a)
+			if err != nil {
+				return nil, err
+			}
+			if string(id) == *args.After {
+				actions = actions[i+1:]
+				break
+			}
+		}
+	}
+	if args.First >= 0 && int(args.First) < len(actions) {
+		actions = actions[:args.First]
+	}
+	return &monitorActionConnection{actions: actions, totalCount: totalCount}, nil
+}
+
+// allActions returns all actions of the monitor, emails first, then Slack
+// webhooks, then webhooks.
+func (r *Resolver) allActions(ctx context.Context, triggerEventID *int, monitorID int64) ([]graphqlbackend.MonitorAction, error) {
+	es, err := r.store.AllActionEmailsForMonitorIDInt64(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	es, err := cm.ScanEmails(rows)
+	sws, err := r.store.AllActionSlackWebhooksForMonitorIDInt64(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	totalCount, err := r.store.TotalCountActionEmails(ctx, monitorID)
+	ws, err := r.store.AllActionWebhooksForMonitorIDInt64(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	actions := make([]graphqlbackend.MonitorAction, 0, len(es))
+
+	actions := make([]graphqlbackend.MonitorAction, 0, len(es)+len(sws)+len(ws))
 	for _, e := range es {
 		actions = append(actions, &action{
 			email: &monitorEmail{
@@ -601,7 +656,139 @@ func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, tr
 			},
 		})
 	}
-	return &monitorActionConnection{actions: actions, totalCount: totalCount}, nil
+	for _, w := range sws {
+		actions = append(actions, &action{
+			slackWebhook: &monitorSlackWebhook{
+				Resolver:            r,
+				MonitorSlackWebhook: w,
+				triggerEventID:      triggerEventID,
+			},
+		})
+	}
+	for _, w := range ws {
+		actions = append(actions, &action{
+			webhook: &monitorWebhook{
+				Resolver:       r,
+				MonitorWebhook: w,
+				triggerEventID: triggerEventID,
+			},
+		})
+	}
+	return actions, nil
+}
+
+// actionID returns the ID of the action, whichever kind it is.
+func actionID(a graphqlbackend.MonitorAction) (graphql.ID, error) {
+	if email, ok := a.ToMonitorEmail(); ok {
+		return email.ID(), nil
+	}
+	if w, ok := a.ToMonitorSlackWebhook(); ok {
+		return w.ID(), nil
+	}
+	if w, ok := a.ToMonitorWebhook(); ok {
+		return w.ID(), nil
+	}
+	return "", errors.Errorf("unknown action type")
+}
+
+//
+// MonitorQuietHours
+//
+type monitorQuietHours struct {
+	*cm.QuietHours
+}
+
+func (q *monitorQuietHours) Start() int32 {
+	return int32(q.QuietHours.Start)
+}
+
+func (q *monitorQuietHours) End() int32 {
+	return int32(q.QuietHours.End)
+}
+
+func (q *monitorQuietHours) Timezone() string {
+	return q.QuietHours.Timezone
+}
+
+//
+// MonitorResultConnection
+//
+type monitorResultConnection struct {
+	results    []graphqlbackend.MonitorResultResolver
+	totalCount int32
+}
+
+func (c *monitorResultConnection) Nodes(ctx context.Context) ([]graphqlbackend.MonitorResultResolver, error) {
+	return c.results, nil
+}
+
+func (c *monitorResultConnection) TotalCount(ctx context.Context) (int32, error) {
+	return c.totalCount, nil
+}
+
+func (c *monitorResultConnection) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	if len(c.results) == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(string(c.results[len(c.results)-1].ID())), nil
+}
+
+//
+// MonitorResult
+//
+type monitorResult struct {
+	*cm.MonitorResult
+}
+
+func (r *monitorResult) ID() graphql.ID {
+	return relay.MarshalID(monitorResultKind, r.Id)
+}
+
+func (r *monitorResult) Repository() string {
+	return r.MonitorResult.Repository
+}
+
+func (r *monitorResult) URL() string {
+	return r.MonitorResult.URL
+}
+
+func (r *monitorResult) Commit() *string {
+	return nonEmpty(r.MonitorResult.Commit)
+}
+
+func (r *monitorResult) Message() *string {
+	return nonEmpty(r.MonitorResult.Message)
+}
+
+func (r *monitorResult) Author() *string {
+	return nonEmpty(r.MonitorResult.Author)
+}
+
+func (r *monitorResult) Path() *string {
+	return nonEmpty(r.MonitorResult.Path)
+}
+
+func (r *monitorResult) LineNumber() *int32 {
+	if r.MonitorResult.LineNumber == 0 {
+		return nil
+	}
+	n := int32(r.MonitorResult.LineNumber)
+	return &n
+}
+
+func (r *monitorResult) Preview() *string {
+	return nonEmpty(r.MonitorResult.Preview)
+}
+
+func (r *monitorResult) Timestamp() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.CreatedAt}
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
 
 //
@@ -739,24 +926,34 @@ func (a *monitorActionConnection) PageInfo(ctx context.Context) (*graphqlutil.Pa
 	if len(a.actions) == 0 {
 		return graphqlutil.HasNextPage(false), nil
 	}
-	last := a.actions[len(a.actions)-1]
-	if email, ok := last.ToMonitorEmail(); ok {
-		return graphqlutil.NextPageCursor(string(email.ID())), nil
+	id, err := actionID(a.actions[len(a.actions)-1])
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.Errorf("we only support email actions for now")
+	return graphqlutil.NextPageCursor(string(id)), nil
 }
 
 //
 // Action <<UNION>>
 //
 type action struct {
-	email graphqlbackend.MonitorEmailResolver
+	email        graphqlbackend.MonitorEmailResolver
+	slackWebhook graphqlbackend.MonitorSlackWebhookResolver
+	webhook      graphqlbackend.MonitorWebhookResolver
 }
 
 func (a *action) ToMonitorEmail() (graphqlbackend.MonitorEmailResolver, bool) {
 	return a.email, a.email != nil
 }
 
+func (a *action) ToMonitorSlackWebhook() (graphqlbackend.MonitorSlackWebhookResolver, bool) {
+	return a.slackWebhook, a.slackWebhook != nil
+}
+
+func (a *action) ToMonitorWebhook() (graphqlbackend.MonitorWebhookResolver, bool) {
+	return a.webhook, a.webhook != nil
+}
+
 //
 // Email
 //
@@ -814,6 +1011,10 @@ func (m *monitorEmail) Priority() string {
 	return m.MonitorEmail.Priority
 }
 
+func (m *monitorEmail) Digest() string {
+	return m.MonitorEmail.Digest
+}
+
 func (m *monitorEmail) Header() string {
 	return m.MonitorEmail.Header
 }
@@ -838,6 +1039,88 @@ func (m *monitorEmail) Events(ctx context.Context, args *graphqlbackend.ListEven
 	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
 }
 
+//
+// Slack webhook
+//
+type monitorSlackWebhook struct {
+	*Resolver
+	*cm.MonitorSlackWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorSlackWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionSlackWebhookKind, m.Id)
+}
+
+func (m *monitorSlackWebhook) Enabled() bool {
+	return m.MonitorSlackWebhook.Enabled
+}
+
+func (m *monitorSlackWebhook) URL() string {
+	return m.MonitorSlackWebhook.URL
+}
+
+func (m *monitorSlackWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
+}
+
+//
+// Webhook
+//
+type monitorWebhook struct {
+	*Resolver
+	*cm.MonitorWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionWebhookKind, m.Id)
+}
+
+func (m *monitorWebhook) Enabled() bool {
+	return m.MonitorWebhook.Enabled
+}
+
+func (m *monitorWebhook) URL() string {
+	return m.MonitorWebhook.URL
+}
+
+func (m *monitorWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
+}
+
 //
 // MonitorActionEmailRecipientConnection
 //
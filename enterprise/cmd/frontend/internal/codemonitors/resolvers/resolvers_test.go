@@ -1168,6 +1168,138 @@ query($userName: String!, $actionCursor:String!, $actionEventCursor:String!){
 }
 `
 
+func TestWebhookActions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := actor.WithInternalActor(context.Background())
+	db := dbtesting.GetDB(t)
+	r := newTestResolver(t, db)
+
+	userID := insertTestUser(t, db, "cm-user1", true)
+	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+
+	actionOpt := WithActions([]*graphqlbackend.CreateActionArgs{
+		{
+			Email: &graphqlbackend.CreateActionEmailArgs{
+				Enabled:    true,
+				Priority:   "NORMAL",
+				Recipients: []graphql.ID{relay.MarshalID("User", userID)},
+				Header:     "test header",
+			},
+		},
+		{
+			SlackWebhook: &graphqlbackend.CreateActionSlackWebhookArgs{
+				Enabled: true,
+				URL:     "https://hooks.slack.com/services/foo",
+			},
+		},
+		{
+			Webhook: &graphqlbackend.CreateActionWebhookArgs{
+				Enabled: false,
+				URL:     "https://example.com/webhook",
+			},
+		},
+	})
+	m, err := r.insertTestMonitorWithOpts(ctx, t, actionOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listActions := func(args *graphqlbackend.ListActionArgs) []graphql.ID {
+		t.Helper()
+		c, err := m.Actions(ctx, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, err := c.Nodes(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]graphql.ID, 0, len(nodes))
+		for _, n := range nodes {
+			id, err := actionID(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	emailID := relay.MarshalID(monitorActionEmailKind, 1)
+	slackWebhookID := relay.MarshalID(monitorActionSlackWebhookKind, 1)
+	webhookID := relay.MarshalID(monitorActionWebhookKind, 1)
+
+	if diff := cmp.Diff([]graphql.ID{emailID, slackWebhookID, webhookID}, listActions(&graphqlbackend.ListActionArgs{First: 10})); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Actions of different kinds are paged through together.
+	after := string(emailID)
+	if diff := cmp.Diff([]graphql.ID{slackWebhookID}, listActions(&graphqlbackend.ListActionArgs{First: 1, After: &after})); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Delete the Slack webhook and update the webhook.
+	trigger, err := m.Trigger(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _ := trigger.ToMonitorQuery()
+	_, err = r.UpdateCodeMonitor(ctx, &graphqlbackend.UpdateCodeMonitorArgs{
+		Monitor: &graphqlbackend.EditMonitorArgs{
+			Id: m.ID(),
+			Update: &graphqlbackend.CreateMonitorArgs{
+				Namespace:   relay.MarshalID("User", userID),
+				Description: "test monitor",
+				Enabled:     true,
+			},
+		},
+		Trigger: &graphqlbackend.EditTriggerArgs{
+			Id:     query.ID(),
+			Update: &graphqlbackend.CreateTriggerArgs{Query: "repo:foo"},
+		},
+		Actions: []*graphqlbackend.EditActionArgs{
+			{
+				Email: &graphqlbackend.EditActionEmailArgs{
+					Id: &emailID,
+					Update: &graphqlbackend.CreateActionEmailArgs{
+						Enabled:    true,
+						Priority:   "NORMAL",
+						Recipients: []graphql.ID{relay.MarshalID("User", userID)},
+						Header:     "test header",
+					},
+				},
+			},
+			{
+				Webhook: &graphqlbackend.EditActionWebhookArgs{
+					Id: &webhookID,
+					Update: &graphqlbackend.CreateActionWebhookArgs{
+						Enabled: true,
+						URL:     "https://example.com/other-webhook",
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]graphql.ID{emailID, webhookID}, listActions(&graphqlbackend.ListActionArgs{First: 10})); diff != "" {
+		t.Fatal(diff)
+	}
+	w, err := r.store.ActionWebhookByIDInt64(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.Enabled || w.URL != "https://example.com/other-webhook" {
+		t.Fatalf("webhook was not updated: %+v", w)
+	}
+}
+
 func TestTriggerTestEmailAction(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
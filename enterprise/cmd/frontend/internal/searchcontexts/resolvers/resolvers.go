@@ -6,6 +6,7 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -92,6 +93,7 @@ func (r *Resolver) CreateSearchContext(ctx context.Context, args graphqlbackend.
 			Public:          args.SearchContext.Public,
 			NamespaceUserID: namespaceUserID,
 			NamespaceOrgID:  namespaceOrgID,
+			Query:           fromStrPtr(args.SearchContext.Query),
 		},
 		repositoryRevisions,
 	)
@@ -121,6 +123,7 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 	updated.Name = args.SearchContext.Name
 	updated.Description = args.SearchContext.Description
 	updated.Public = args.SearchContext.Public
+	updated.Query = fromStrPtr(args.SearchContext.Query)
 
 	searchContext, err := searchcontexts.UpdateSearchContextWithRepositoryRevisions(
 		ctx,
@@ -134,6 +137,13 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 	return &searchContextResolver{searchContext, r.db}, nil
 }
 
+func fromStrPtr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 func repositoryByID(ctx context.Context, id graphql.ID, db dbutil.DB) (*graphqlbackend.RepositoryResolver, error) {
 	var repoID api.RepoID
 	if err := relay.UnmarshalSpec(id, &repoID); err != nil {
@@ -183,6 +193,19 @@ func (r *Resolver) DeleteSearchContext(ctx context.Context, args graphqlbackend.
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
+func (r *Resolver) SearchContextsFile(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: The file contains all search contexts, including private ones, so only site admins may export it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return "", err
+	}
+
+	file, err := searchcontexts.ExportSearchContextsFile(ctx, r.db)
+	if err != nil {
+		return "", err
+	}
+	return string(file), nil
+}
+
 func unmarshalSearchContextCursor(cursor *string) (int32, error) {
 	var after int32
 	if cursor == nil {
@@ -375,9 +398,20 @@ func (r *searchContextResolver) Namespace(ctx context.Context) (*graphqlbackend.
 	return nil, nil
 }
 
+func (r *searchContextResolver) Query() *string {
+	if r.sc.Query == "" {
+		return nil
+	}
+	return &r.sc.Query
+}
+
 func (r *searchContextResolver) ViewerCanManage(ctx context.Context) bool {
 	hasWriteAccess := searchcontexts.ValidateSearchContextWriteAccessForCurrentUser(ctx, r.db, r.sc.NamespaceUserID, r.sc.NamespaceOrgID, r.sc.Public) == nil
-	return !searchcontexts.IsAutoDefinedSearchContext(r.sc) && hasWriteAccess
+	return !searchcontexts.IsAutoDefinedSearchContext(r.sc) && !r.sc.Managed && hasWriteAccess
+}
+
+func (r *searchContextResolver) Managed() bool {
+	return r.sc.Managed
 }
 
 func (r *searchContextResolver) Repositories(ctx context.Context) ([]graphqlbackend.SearchContextRepositoryRevisionsResolver, error) {
@@ -385,6 +419,19 @@ func (r *searchContextResolver) Repositories(ctx context.Context) ([]graphqlback
 		return []graphqlbackend.SearchContextRepositoryRevisionsResolver{}, nil
 	}
 
+	if r.sc.Query != "" {
+		repoRevs, err := searchcontexts.GetRepositoryRevisions(ctx, r.db, r.sc)
+		if err != nil {
+			return nil, err
+		}
+		searchContextRepositories := make([]graphqlbackend.SearchContextRepositoryRevisionsResolver, len(repoRevs))
+		for idx, repoRev := range repoRevs {
+			// Search contexts defined by a query search the default branch.
+			searchContextRepositories[idx] = &searchContextRepositoryRevisionsResolver{graphqlbackend.NewRepositoryResolver(r.db, repoRev.Repo.ToRepo()), []string{"HEAD"}}
+		}
+		return searchContextRepositories, nil
+	}
+
 	repoRevs, err := database.SearchContexts(r.db).GetSearchContextRepositoryRevisions(ctx, r.sc.ID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,200 @@
+package searchcontexts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	defaultSearchContextsFilePath     = "search-contexts.yaml"
+	defaultSearchContextsFileRevision = "HEAD"
+	defaultSyncIntervalSeconds        = 300
+
+	// maxSearchContextsFileSize is the maximum size of a search contexts file
+	// which is read from the repository.
+	maxSearchContextsFileSize = 1024 * 1024
+)
+
+// syncReports stores the report and the time of the last sync, which are
+// shared by all frontend replicas. The time is stored under its own key so
+// that whether a sync is due doesn't depend on decoding the report.
+var syncReports = rcache.New("search_contexts_file_sync")
+
+const (
+	syncReportKey = "report"
+	syncedAtKey   = "synced_at"
+)
+
+// startSyncingSearchContextsFile periodically reconciles the search contexts
+// with the file configured in the "search.contexts.source" site
+// configuration. Every frontend replica runs the loop, but a sync only runs
+// on one replica at a time and at most once per interval.
+func startSyncingSearchContextsFile(db dbutil.DB) {
+	ctx := actor.WithInternalActor(context.Background())
+	for {
+		source := conf.Get().SearchContextsSource
+		if source != nil {
+			if err := syncSearchContextsFileIfDue(ctx, db, source); err != nil {
+				log15.Error("searchcontexts: failed to sync search contexts file", "repository", source.Repository, "path", source.Path, "error", err)
+			}
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
+func syncSearchContextsFileIfDue(ctx context.Context, db dbutil.DB, source *schema.SearchContextsSource) error {
+	ctx, release, ok := rcache.TryAcquireMutex(ctx, "search-contexts-file-sync", rcache.MutexOptions{})
+	if !ok {
+		// Another replica is syncing.
+		return nil
+	}
+	defer release()
+
+	interval := time.Duration(source.IntervalSeconds) * time.Second
+	if interval == 0 {
+		interval = defaultSyncIntervalSeconds * time.Second
+	}
+	if syncedAt, ok := lastSyncedAt(); ok && time.Since(syncedAt) < interval {
+		return nil
+	}
+
+	report, syncErr := syncSearchContextsFile(ctx, db, source)
+	if syncErr != nil {
+		// Keep the error in the report so that site admins are alerted.
+		report = &searchcontexts.SearchContextsSyncReport{
+			DryRun: source.DryRun,
+			Errors: []string{syncErr.Error()},
+		}
+	}
+
+	syncedAt, err := time.Now().MarshalText()
+	if err != nil {
+		return err
+	}
+	syncReports.Set(syncedAtKey, syncedAt)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	syncReports.Set(syncReportKey, data)
+	return syncErr
+}
+
+func syncSearchContextsFile(ctx context.Context, db dbutil.DB, source *schema.SearchContextsSource) (*searchcontexts.SearchContextsSyncReport, error) {
+	path, revision := source.Path, source.Revision
+	if path == "" {
+		path = defaultSearchContextsFilePath
+	}
+	if revision == "" {
+		revision = defaultSearchContextsFileRevision
+	}
+
+	repo := api.RepoName(source.Repository)
+	commit, err := git.ResolveRevision(ctx, repo, revision, git.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving revision %q of %s", revision, repo)
+	}
+	data, err := git.ReadFile(ctx, repo, commit, path, maxSearchContextsFileSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s in %s@%s", path, repo, revision)
+	}
+
+	file, err := searchcontexts.ParseSearchContextsFile(data)
+	if err != nil {
+		return nil, err
+	}
+	return searchcontexts.SyncSearchContextsFile(ctx, db, file, source.DryRun)
+}
+
+// lastSyncedAt returns the time of the last sync, and false if there was no
+// sync yet.
+func lastSyncedAt() (time.Time, bool) {
+	data, ok := syncReports.Get(syncedAtKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	var syncedAt time.Time
+	if err := syncedAt.UnmarshalText(data); err != nil {
+		log15.Warn("searchcontexts: failed to decode search contexts sync time", "error", err)
+		return time.Time{}, false
+	}
+	return syncedAt, true
+}
+
+// lastSyncReport returns the report of the last sync, or nil if there was no
+// sync yet.
+func lastSyncReport() *searchcontexts.SearchContextsSyncReport {
+	data, ok := syncReports.Get(syncReportKey)
+	if !ok {
+		return nil
+	}
+	var report searchcontexts.SearchContextsSyncReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		log15.Warn("searchcontexts: failed to decode search contexts sync report", "error", err)
+		return nil
+	}
+	return &report
+}
+
+// searchContextsSyncAlerts reports the errors of the last sync and, in dry-run
+// mode, the drift between the search contexts and the file.
+func searchContextsSyncAlerts(args graphqlbackend.AlertFuncArgs) []*graphqlbackend.Alert {
+	// Only site admins can act on this alert, so only show it to site admins.
+	if !args.IsSiteAdmin {
+		return nil
+	}
+
+	if conf.Get().SearchContextsSource == nil {
+		return nil
+	}
+
+	report := lastSyncReport()
+	if report == nil {
+		return nil
+	}
+
+	var alerts []*graphqlbackend.Alert
+	if len(report.Errors) > 0 {
+		alerts = append(alerts, &graphqlbackend.Alert{
+			TypeValue:    graphqlbackend.AlertTypeError,
+			MessageValue: fmt.Sprintf("Failed to sync search contexts with the search contexts file: %s. Update the file or the `search.contexts.source` [**site configuration**](/site-admin/configuration).", strings.Join(report.Errors, "; ")),
+		})
+	}
+	if report.DryRun && report.HasDrift() {
+		var drift []string
+		for _, d := range []struct {
+			verb  string
+			specs []string
+		}{
+			{"created", report.Created},
+			{"updated", report.Updated},
+			{"deleted", report.Deleted},
+		} {
+			if len(d.specs) > 0 {
+				drift = append(drift, fmt.Sprintf("%s would be %s", strings.Join(d.specs, ", "), d.verb))
+			}
+		}
+		alerts = append(alerts, &graphqlbackend.Alert{
+			TypeValue:    graphqlbackend.AlertTypeWarning,
+			MessageValue: fmt.Sprintf("The search contexts differ from the search contexts file: %s. Disable `dryRun` in the `search.contexts.source` [**site configuration**](/site-admin/configuration) to apply the file.", strings.Join(drift, "; ")),
+		})
+	}
+	return alerts
+}
@@ -18,6 +18,7 @@ type MonitorEmail struct {
 	Monitor   int64
 	Enabled   bool
 	Priority  string
+	Digest    string
 	Header    string
 	CreatedBy int32
 	CreatedAt time.Time
@@ -67,19 +68,8 @@ func (s *Store) DeleteActionsInt64(ctx context.Context, actionIDs []int64, monit
 	return nil
 }
 
-const totalCountActionEmailsFmtStr = `
-SELECT COUNT(*)
-FROM cm_emails
-WHERE monitor = %s;
-`
-
-func (s *Store) TotalCountActionEmails(ctx context.Context, monitorID int64) (count int32, err error) {
-	err = s.QueryRow(ctx, sqlf.Sprintf(totalCountActionEmailsFmtStr, monitorID)).Scan(&count)
-	return count, err
-}
-
 const actionEmailByIDFmtStr = `
-SELECT id, monitor, enabled, priority, header, created_by, created_at, changed_by, changed_at
+SELECT id, monitor, enabled, priority, digest, header, created_by, created_at, changed_by, changed_at
 FROM cm_emails
 WHERE id = %s
 `
@@ -108,6 +98,7 @@ const updateActionEmailFmtStr = `
 UPDATE cm_emails
 SET enabled = %s,
 	priority = %s,
+	digest = %s,
 	header = %s,
 	changed_by = %s,
 	changed_at = %s
@@ -131,6 +122,7 @@ func (s *Store) updateActionEmailQuery(ctx context.Context, monitorID int64, arg
 		updateActionEmailFmtStr,
 		args.Update.Enabled,
 		args.Update.Priority,
+		digestOrNone(args.Update.Digest),
 		args.Update.Header,
 		a.UID,
 		now,
@@ -140,31 +132,26 @@ func (s *Store) updateActionEmailQuery(ctx context.Context, monitorID int64, arg
 	), nil
 }
 
-const readActionEmailFmtStr = `
-SELECT id, monitor, enabled, priority, header, created_by, created_at, changed_by, changed_at
+const allActionEmailsForMonitorIDInt64FmtStr = `
+SELECT id, monitor, enabled, priority, digest, header, created_by, created_at, changed_by, changed_at
 FROM cm_emails
 WHERE monitor = %s
-AND id > %s
-LIMIT %s;
+ORDER BY id ASC
 `
 
-func (s *Store) ReadActionEmailQuery(ctx context.Context, monitorID int64, args *graphqlbackend.ListActionArgs) (*sqlf.Query, error) {
-	after, err := unmarshalAfter(args.After)
+func (s *Store) AllActionEmailsForMonitorIDInt64(ctx context.Context, monitorID int64) ([]*MonitorEmail, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(allActionEmailsForMonitorIDInt64FmtStr, monitorID))
 	if err != nil {
 		return nil, err
 	}
-	return sqlf.Sprintf(
-		readActionEmailFmtStr,
-		monitorID,
-		after,
-		args.First,
-	), nil
+	defer rows.Close()
+	return ScanEmails(rows)
 }
 
 const createActionEmailFmtStr = `
 INSERT INTO cm_emails
-(monitor, enabled, priority, header, created_by, created_at, changed_by, changed_at)
-VALUES (%s,%s,%s,%s,%s,%s,%s,%s)
+(monitor, enabled, priority, digest, header, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s,%s)
 RETURNING %s;
 `
 
@@ -176,6 +163,7 @@ func (s *Store) createActionEmailQuery(ctx context.Context, monitorID int64, arg
 		monitorID,
 		args.Enabled,
 		args.Priority,
+		digestOrNone(args.Digest),
 		args.Header,
 		a.UID,
 		now,
@@ -185,6 +173,21 @@ func (s *Store) createActionEmailQuery(ctx context.Context, monitorID int64, arg
 	), nil
 }
 
+// Digests of email actions. DigestNone sends an email for each trigger event.
+const (
+	DigestNone   = "NONE"
+	DigestHourly = "HOURLY"
+	DigestDaily  = "DAILY"
+)
+
+// digestOrNone returns DigestNone for email actions created without a digest.
+func digestOrNone(digest string) string {
+	if digest == "" {
+		return DigestNone
+	}
+	return digest
+}
+
 const deleteActionEmailFmtStr = `DELETE FROM cm_emails WHERE id in (%s) AND MONITOR = %s`
 
 func deleteActionsEmailQuery(ctx context.Context, actionIDs []int64, monitorID int64) (*sqlf.Query, error) {
@@ -204,6 +207,7 @@ var EmailsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_emails.monitor"),
 	sqlf.Sprintf("cm_emails.enabled"),
 	sqlf.Sprintf("cm_emails.priority"),
+	sqlf.Sprintf("cm_emails.digest"),
 	sqlf.Sprintf("cm_emails.header"),
 	sqlf.Sprintf("cm_emails.created_by"),
 	sqlf.Sprintf("cm_emails.created_at"),
@@ -219,6 +223,7 @@ func ScanEmails(rows *sql.Rows) (ms []*MonitorEmail, err error) {
 			&m.Monitor,
 			&m.Enabled,
 			&m.Priority,
+			&m.Digest,
 			&m.Header,
 			&m.CreatedBy,
 			&m.CreatedAt,
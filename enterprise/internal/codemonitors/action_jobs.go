@@ -3,6 +3,7 @@ package codemonitors
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -14,8 +15,14 @@ import (
 )
 
 type ActionJob struct {
-	Id           int
-	Email        int64
+	Id int
+
+	// Exactly one of Email, SlackWebhook and Webhook is set, depending on the
+	// kind of action this job executes.
+	Email        *int64
+	SlackWebhook *int64
+	Webhook      *int64
+
 	TriggerEvent int
 
 	// Fields demanded by any dbworker.
@@ -40,11 +47,16 @@ type ActionJobMetadata struct {
 
 	// The query with after: filter.
 	Query string
+
+	// A summary of the new search results.
+	Results []*SearchResult
 }
 
 var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.id"),
 	sqlf.Sprintf("cm_action_jobs.email"),
+	sqlf.Sprintf("cm_action_jobs.slack_webhook"),
+	sqlf.Sprintf("cm_action_jobs.webhook"),
 	sqlf.Sprintf("cm_action_jobs.trigger_event"),
 	sqlf.Sprintf("cm_action_jobs.state"),
 	sqlf.Sprintf("cm_action_jobs.failure_message"),
@@ -56,8 +68,8 @@ var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.log_contents"),
 }
 
-const readActionEmailEventsFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+const readActionEventsFmtStr = `
+SELECT id, email, slack_webhook, webhook, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
 FROM cm_action_jobs
 WHERE %s
 AND id > %s
@@ -66,18 +78,24 @@ LIMIT %s;
 `
 
 func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
+	return s.readActionEvents(ctx, actionEventsWhere("email", emailID, triggerEventID), args)
+}
+
+func (s *Store) ReadActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
+	return s.readActionEvents(ctx, actionEventsWhere("slack_webhook", slackWebhookID, triggerEventID), args)
+}
+
+func (s *Store) ReadActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
+	return s.readActionEvents(ctx, actionEventsWhere("webhook", webhookID, triggerEventID), args)
+}
+
+func (s *Store) readActionEvents(ctx context.Context, where *sqlf.Query, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
 	var rows *sql.Rows
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
 		return nil, err
 	}
-	rows, err = s.Query(ctx, sqlf.Sprintf(readActionEmailEventsFmtStr, where, after, args.First))
+	rows, err = s.Query(ctx, sqlf.Sprintf(readActionEventsFmtStr, where, after, args.First))
 	if err != nil {
 		return nil, err
 	}
@@ -85,66 +103,205 @@ func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, trigge
 	return scanActionJobs(rows, err)
 }
 
-const totalActionEmailEventsFmtStr = `
+const totalActionEventsFmtStr = `
 SELECT COUNT(*)
 FROM cm_action_jobs
 WHERE %s
 `
 
 func (s *Store) TotalActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int) (totalCount int32, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
-	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEmailEventsFmtStr, where)).Scan(&totalCount)
+	return s.totalActionEvents(ctx, actionEventsWhere("email", emailID, triggerEventID))
+}
+
+func (s *Store) TotalActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int) (totalCount int32, err error) {
+	return s.totalActionEvents(ctx, actionEventsWhere("slack_webhook", slackWebhookID, triggerEventID))
+}
+
+func (s *Store) TotalActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int) (totalCount int32, err error) {
+	return s.totalActionEvents(ctx, actionEventsWhere("webhook", webhookID, triggerEventID))
+}
+
+func (s *Store) totalActionEvents(ctx context.Context, where *sqlf.Query) (totalCount int32, err error) {
+	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEventsFmtStr, where)).Scan(&totalCount)
 	if err != nil {
 		return -1, err
 	}
 	return totalCount, nil
 }
 
+// actionEventsWhere returns the condition matching the jobs of the action
+// with the given ID, whose kind is identified by column. If triggerEventID is
+// not nil, only the jobs of that trigger event match.
+func actionEventsWhere(column string, actionID int64, triggerEventID *int) *sqlf.Query {
+	if triggerEventID == nil {
+		return sqlf.Sprintf("%s = %s", sqlf.Sprintf(column), actionID)
+	}
+	return sqlf.Sprintf("%s = %s AND trigger_event = %s", sqlf.Sprintf(column), actionID, *triggerEventID)
+}
+
 const enqueueActionEmailFmtStr = `
 WITH due AS (
-	SELECT e.id, e.monitor, e.enabled, e.priority, e.header, e.created_by, e.created_at, e.changed_by, e.changed_at
+	SELECT e.id, e.digest
 	FROM cm_emails e INNER JOIN cm_queries q ON e.monitor = q.monitor
 	WHERE q.id = %s AND e.enabled = true
 ),
 busy AS (
     SELECT DISTINCT email as id FROM cm_action_jobs
-    WHERE state = 'queued'
-    OR state = 'processing'
+    WHERE email IS NOT NULL
+    AND (state = 'queued' OR state = 'processing')
 )
-INSERT INTO cm_action_jobs (email, trigger_event)
-SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+INSERT INTO cm_action_jobs (email, trigger_event, process_after)
+SELECT id, %s::integer, CASE digest
+	WHEN 'HOURLY' THEN %s::timestamptz + interval '1 hour'
+	WHEN 'DAILY' THEN %s::timestamptz + interval '1 day'
+END
+FROM due
+WHERE id NOT IN (SELECT id FROM busy)
+ORDER BY id
 `
 
+// EnqueueActionEmailsForQueryIDInt64 enqueues a job for each enabled email
+// action of the monitor of the query. The jobs of digests are processed at the
+// end of their window. Until then, no other jobs are enqueued for the email
+// action, and the job collects the results of later trigger events.
 func (s *Store) EnqueueActionEmailsForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
-	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionEmailFmtStr, queryID, triggerEventID, triggerEventID))
+	now := s.Now()
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionEmailFmtStr, queryID, triggerEventID, now, now))
+}
+
+const enqueueActionSlackWebhookFmtStr = `
+WITH due AS (
+	SELECT w.id
+	FROM cm_slack_webhooks w INNER JOIN cm_queries q ON w.monitor = q.monitor
+	WHERE q.id = %s AND w.enabled = true
+),
+busy AS (
+    SELECT DISTINCT slack_webhook as id FROM cm_action_jobs
+    WHERE slack_webhook IS NOT NULL
+    AND (state = 'queued' OR state = 'processing')
+)
+INSERT INTO cm_action_jobs (slack_webhook, trigger_event)
+SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+`
+
+func (s *Store) EnqueueActionSlackWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionSlackWebhookFmtStr, queryID, triggerEventID, triggerEventID))
+}
+
+const enqueueActionWebhookFmtStr = `
+WITH due AS (
+	SELECT w.id
+	FROM cm_webhooks w INNER JOIN cm_queries q ON w.monitor = q.monitor
+	WHERE q.id = %s AND w.enabled = true
+),
+busy AS (
+    SELECT DISTINCT webhook as id FROM cm_action_jobs
+    WHERE webhook IS NOT NULL
+    AND (state = 'queued' OR state = 'processing')
+)
+INSERT INTO cm_action_jobs (webhook, trigger_event)
+SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+`
+
+func (s *Store) EnqueueActionWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionWebhookFmtStr, queryID, triggerEventID, triggerEventID))
+}
+
+// EnqueueActionJobsForQueryIDInt64 enqueues a job for each enabled action of
+// the monitor of the query, regardless of its kind.
+func (s *Store) EnqueueActionJobsForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	for _, enqueue := range []func(context.Context, int64, int) error{
+		s.EnqueueActionEmailsForQueryIDInt64,
+		s.EnqueueActionSlackWebhooksForQueryIDInt64,
+		s.EnqueueActionWebhooksForQueryIDInt64,
+	} {
+		if err = enqueue(ctx, queryID, triggerEventID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 const getActionJobMetadataFmtStr = `
-select cm.description, ctj.query_string, cm.id as monitorID, ctj.num_results from
+select cm.description, ctj.query_string, cm.id as monitorID, ctj.num_results, ctj.search_results from
 cm_action_jobs caj
-inner join cm_trigger_jobs ctj on caj.trigger_event = ctj.id
+inner join cm_trigger_jobs first on caj.trigger_event = first.id
+inner join cm_trigger_jobs ctj on ctj.query = first.query
 inner join cm_queries cq on cq.id = ctj.query
 inner join cm_monitors cm on cm.id = cq.monitor
 where caj.id = %s
+and (ctj.id = first.id or (ctj.id > first.id and ctj.num_results > 0))
+-- Skip the trigger events a later job of the same action reports.
+and not exists (
+	select 1 from cm_action_jobs later
+	where later.id > caj.id
+	and later.trigger_event <= ctj.id
+	and later.email is not distinct from caj.email
+	and later.slack_webhook is not distinct from caj.slack_webhook
+	and later.webhook is not distinct from caj.webhook
+)
+order by ctj.id asc
 `
 
+// GetActionJobMetadata returns the metadata of the trigger event of the action
+// job, together with the results of the trigger events which found results
+// while the job was waiting to be processed, for example during the window of
+// a digest or during quiet hours. The query is the one of the first trigger
+// event, so its after: filter covers the results of all of them.
 func (s *Store) GetActionJobMetadata(ctx context.Context, recordID int) (m *ActionJobMetadata, err error) {
-	row := s.Store.QueryRow(ctx, sqlf.Sprintf(getActionJobMetadataFmtStr, recordID))
-	m = &ActionJobMetadata{}
-	err = row.Scan(&m.Description, &m.Query, &m.MonitorID, &m.NumResults)
+	rows, err := s.Store.Query(ctx, sqlf.Sprintf(getActionJobMetadataFmtStr, recordID))
 	if err != nil {
 		return nil, err
 	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var (
+			query, description string
+			monitorID          int64
+			numResults         *int
+			results            []byte
+		)
+		if err = rows.Scan(&description, &query, &monitorID, &numResults, &results); err != nil {
+			return nil, err
+		}
+		if m == nil {
+			m = &ActionJobMetadata{
+				Description: description,
+				Query:       query,
+				MonitorID:   monitorID,
+			}
+		}
+		if numResults != nil {
+			total := zeroOrVal(m.NumResults) + *numResults
+			m.NumResults = &total
+		}
+		if len(results) > 0 {
+			var rs []*SearchResult
+			if err = json.Unmarshal(results, &rs); err != nil {
+				return nil, err
+			}
+			m.Results = append(m.Results, rs...)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, sql.ErrNoRows
+	}
 	return m, nil
 }
 
+func zeroOrVal(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
 const actionJobForIDFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+SELECT id, email, slack_webhook, webhook, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
 FROM cm_action_jobs
 WHERE id = %s
 `
@@ -190,6 +347,8 @@ func scanActionJobs(rows *sql.Rows, err error) ([]*ActionJob, error) {
 		if err := rows.Scan(
 			&aj.Id,
 			&aj.Email,
+			&aj.SlackWebhook,
+			&aj.Webhook,
 			&aj.TriggerEvent,
 			&aj.State,
 			&aj.FailureMessage,
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/graph-gophers/graphql-go"
 	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 )
 
 func TestEnqueueActionEmailsForQueryIDInt64QueryByRecordID(t *testing.T) {
@@ -35,9 +38,10 @@ func TestEnqueueActionEmailsForQueryIDInt64QueryByRecordID(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	emailID := int64(1)
 	want := &ActionJob{
 		Id:             1,
-		Email:          1,
+		Email:          &emailID,
 		TriggerEvent:   1,
 		State:          "queued",
 		FailureMessage: nil,
@@ -98,6 +102,77 @@ func TestGetActionJobMetadata(t *testing.T) {
 	}
 }
 
+func TestEnqueueActionEmailDigest(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := newTestStore(t)
+	_, _, namespace, userCTX := newTestUser(ctx, t)
+	_, err := s.CreateCodeMonitor(userCTX, &graphqlbackend.CreateCodeMonitorArgs{
+		Monitor: &graphqlbackend.CreateMonitorArgs{
+			Namespace:   namespace,
+			Description: testDescription,
+			Enabled:     true,
+		},
+		Trigger: &graphqlbackend.CreateTriggerArgs{Query: testQuery},
+		Actions: []*graphqlbackend.CreateActionArgs{{
+			Email: &graphqlbackend.CreateActionEmailArgs{
+				Enabled:    true,
+				Priority:   "NORMAL",
+				Digest:     DigestDaily,
+				Recipients: []graphql.ID{namespace},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two trigger events find results while the job of the first one waits
+	// for the end of the window of the digest.
+	for i, numResults := range []int{2, 3} {
+		triggerEvent := i + 1
+		err = s.EnqueueTriggerQueries(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.LogSearch(ctx, testQuery, numResults, triggerEvent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.EnqueueActionEmailsForQueryIDInt64(ctx, 1, triggerEvent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Exec(ctx, sqlf.Sprintf(setToCompletedFmtStr, s.Now(), s.Now(), triggerEvent))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	job, err := s.ActionJobForIDInt(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantProcessAfter := s.Now().Add(24 * time.Hour)
+	if job.ProcessAfter == nil || !job.ProcessAfter.Equal(wantProcessAfter) {
+		t.Fatalf("got process_after %v, want %v", job.ProcessAfter, wantProcessAfter)
+	}
+	_, err = s.ActionJobForIDInt(ctx, 2)
+	if err == nil {
+		t.Fatal("expected no job for the second trigger event")
+	}
+
+	m, err := s.GetActionJobMetadata(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := zeroOrVal(m.NumResults); got != 5 {
+		t.Fatalf("got %d results, want 5", got)
+	}
+}
+
 func TestScanActionJobs(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
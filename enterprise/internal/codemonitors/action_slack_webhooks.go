@@ -0,0 +1,196 @@
+package codemonitors
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+type MonitorSlackWebhook struct {
+	Id        int64
+	Monitor   int64
+	Enabled   bool
+	URL       string
+	CreatedBy int32
+	CreatedAt time.Time
+	ChangedBy int32
+	ChangedAt time.Time
+}
+
+// ValidateSlackWebhookURL returns an error if rawURL is not a Slack incoming
+// webhook URL. Slack webhook actions are posted to by the server, so we only
+// allow Slack's host to avoid sending requests to internal addresses.
+func ValidateSlackWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid Slack webhook URL")
+	}
+	if u.Scheme != "https" || u.Host != "hooks.slack.com" || u.User != nil {
+		return errors.Errorf("invalid Slack webhook URL %q: must start with https://hooks.slack.com/", rawURL)
+	}
+	return nil
+}
+
+const createActionSlackWebhookFmtStr = `
+INSERT INTO cm_slack_webhooks
+(monitor, enabled, url, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,%s,%s)
+RETURNING %s;
+`
+
+func (s *Store) CreateActionSlackWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.CreateActionSlackWebhookArgs) (*MonitorSlackWebhook, error) {
+	if err := ValidateSlackWebhookURL(args.URL); err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	return s.runSlackWebhookQuery(ctx, sqlf.Sprintf(
+		createActionSlackWebhookFmtStr,
+		monitorID,
+		args.Enabled,
+		args.URL,
+		a.UID,
+		now,
+		a.UID,
+		now,
+		sqlf.Join(SlackWebhooksColumns, ", "),
+	))
+}
+
+const updateActionSlackWebhookFmtStr = `
+UPDATE cm_slack_webhooks
+SET enabled = %s,
+	url = %s,
+	changed_by = %s,
+	changed_at = %s
+WHERE id = %s
+AND monitor = %s
+RETURNING %s;
+`
+
+func (s *Store) UpdateActionSlackWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.EditActionSlackWebhookArgs) (*MonitorSlackWebhook, error) {
+	if args.Id == nil {
+		return nil, errors.Errorf("nil is not a valid action ID")
+	}
+	if err := ValidateSlackWebhookURL(args.Update.URL); err != nil {
+		return nil, err
+	}
+	var actionID int64
+	err := relay.UnmarshalSpec(*args.Id, &actionID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	return s.runSlackWebhookQuery(ctx, sqlf.Sprintf(
+		updateActionSlackWebhookFmtStr,
+		args.Update.Enabled,
+		args.Update.URL,
+		a.UID,
+		now,
+		actionID,
+		monitorID,
+		sqlf.Join(SlackWebhooksColumns, ", "),
+	))
+}
+
+const deleteActionSlackWebhooksFmtStr = `DELETE FROM cm_slack_webhooks WHERE id in (%s) AND monitor = %s`
+
+func (s *Store) DeleteActionSlackWebhooks(ctx context.Context, actionIDs []int64, monitorID int64) error {
+	if len(actionIDs) == 0 {
+		return nil
+	}
+	deleteIDs := make([]*sqlf.Query, 0, len(actionIDs))
+	for _, id := range actionIDs {
+		deleteIDs = append(deleteIDs, sqlf.Sprintf("%d", id))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(deleteActionSlackWebhooksFmtStr, sqlf.Join(deleteIDs, ", "), monitorID))
+}
+
+const actionSlackWebhookByIDFmtStr = `
+SELECT %s
+FROM cm_slack_webhooks
+WHERE id = %s
+`
+
+func (s *Store) ActionSlackWebhookByIDInt64(ctx context.Context, slackWebhookID int64) (*MonitorSlackWebhook, error) {
+	return s.runSlackWebhookQuery(ctx, sqlf.Sprintf(actionSlackWebhookByIDFmtStr, sqlf.Join(SlackWebhooksColumns, ", "), slackWebhookID))
+}
+
+const allActionSlackWebhooksForMonitorIDInt64FmtStr = `
+SELECT %s
+FROM cm_slack_webhooks
+WHERE monitor = %s
+ORDER BY id ASC
+`
+
+func (s *Store) AllActionSlackWebhooksForMonitorIDInt64(ctx context.Context, monitorID int64) ([]*MonitorSlackWebhook, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(allActionSlackWebhooksForMonitorIDInt64FmtStr, sqlf.Join(SlackWebhooksColumns, ", "), monitorID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSlackWebhooks(rows)
+}
+
+func (s *Store) runSlackWebhookQuery(ctx context.Context, q *sqlf.Query) (*MonitorSlackWebhook, error) {
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ws, err := scanSlackWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(ws) == 0 {
+		return nil, errors.Errorf("operation failed. Query should have returned 1 row")
+	}
+	return ws[0], nil
+}
+
+var SlackWebhooksColumns = []*sqlf.Query{
+	sqlf.Sprintf("cm_slack_webhooks.id"),
+	sqlf.Sprintf("cm_slack_webhooks.monitor"),
+	sqlf.Sprintf("cm_slack_webhooks.enabled"),
+	sqlf.Sprintf("cm_slack_webhooks.url"),
+	sqlf.Sprintf("cm_slack_webhooks.created_by"),
+	sqlf.Sprintf("cm_slack_webhooks.created_at"),
+	sqlf.Sprintf("cm_slack_webhooks.changed_by"),
+	sqlf.Sprintf("cm_slack_webhooks.changed_at"),
+}
+
+func scanSlackWebhooks(rows *sql.Rows) (ws []*MonitorSlackWebhook, err error) {
+	for rows.Next() {
+		w := &MonitorSlackWebhook{}
+		if err = rows.Scan(
+			&w.Id,
+			&w.Monitor,
+			&w.Enabled,
+			&w.URL,
+			&w.CreatedBy,
+			&w.CreatedAt,
+			&w.ChangedBy,
+			&w.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
@@ -0,0 +1,46 @@
+package codemonitors
+
+import "testing"
+
+func TestValidateWebhookURL(t *testing.T) {
+	cases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{"http://localhost:8080/hook", true},
+		{"", false},
+		{"hooks.slack.com/services/T000", false},
+		{"ftp://example.com/hook", false},
+		{"https://", false},
+		{"://example.com", false},
+	}
+	for _, c := range cases {
+		if err := validateWebhookURL(c.url); (err == nil) != c.valid {
+			t.Errorf("validateWebhookURL(%q) returned %v, want valid=%v", c.url, err, c.valid)
+		}
+	}
+}
+
+func TestValidateSlackWebhookURL(t *testing.T) {
+	cases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{"http://hooks.slack.com/services/T000/B000/XXXX", false},
+		{"https://hooks.slack.com.example.com/services/T000", false},
+		{"https://user@hooks.slack.com/services/T000", false},
+		{"https://hooks.slack.com:8080/services/T000", false},
+		{"http://localhost:8080/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://gitserver-0:3178/exec", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if err := ValidateSlackWebhookURL(c.url); (err == nil) != c.valid {
+			t.Errorf("ValidateSlackWebhookURL(%q) returned %v, want valid=%v", c.url, err, c.valid)
+		}
+	}
+}
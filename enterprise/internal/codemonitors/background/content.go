@@ -0,0 +1,168 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+
+	"github.com/cockroachdb/errors"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// maxContentTriggerMatches is the count: we add to the queries of content
+// search triggers which don't specify one. Matches beyond it are not
+// fingerprinted, so new matches beyond it are reported once they fit within
+// the limit.
+const maxContentTriggerMatches = 10000
+
+// isContentTrigger returns true if the trigger query searches file contents
+// rather than commits. Content search triggers fire for matches which were not
+// found in the previous run instead of using after: filters.
+func isContentTrigger(queryString string) bool {
+	q, err := query.ParseLiteral(queryString)
+	if err != nil {
+		// Keep the behavior of commit triggers, which was the only kind of
+		// trigger before content search triggers.
+		return false
+	}
+	types, _ := q.StringValues(query.FieldType)
+	for _, t := range types {
+		if t == "commit" || t == "diff" {
+			return false
+		}
+	}
+	return true
+}
+
+// newContentQuery returns the query to run for a content search trigger.
+func newContentQuery(q *cm.MonitorQuery) string {
+	parsed, err := query.ParseLiteral(q.QueryString)
+	if err != nil {
+		return q.QueryString
+	}
+	if count, _ := parsed.StringValue(query.FieldCount); count != "" {
+		return q.QueryString
+	}
+	return fmt.Sprintf("%s count:%d", q.QueryString, maxContentTriggerMatches)
+}
+
+// newContentMatches returns the matches of v which were not found in the
+// previous run of the content search trigger and records the fingerprints of
+// all matches of v for the next run. The first run only records fingerprints,
+// so that a new trigger doesn't report all existing matches. If v doesn't
+// contain all matches, the fingerprints are added to those of the previous
+// run, so that matches which are missing from v aren't reported again once
+// they are found.
+func newContentMatches(ctx context.Context, s *cm.Store, queryID int64, v *gqlSearchResponse) ([]*cm.SearchResult, error) {
+	previous, recorded, err := s.ResultFingerprints(ctx, queryID)
+	if err != nil {
+		return nil, errors.Errorf("store.ResultFingerprints: %w", err)
+	}
+
+	var (
+		matches      []*cm.SearchResult
+		fingerprints []string
+		seen         = map[string]struct{}{}
+	)
+	for _, m := range extractContentMatches(v) {
+		f := m.Fingerprint()
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		fingerprints = append(fingerprints, f)
+		if _, ok := previous[f]; recorded && !ok {
+			matches = append(matches, m)
+		}
+	}
+
+	if isCompleteSearch(v) {
+		err = s.SetResultFingerprints(ctx, queryID, fingerprints)
+		if err != nil {
+			return nil, errors.Errorf("store.SetResultFingerprints: %w", err)
+		}
+	} else {
+		err = s.AddResultFingerprints(ctx, queryID, fingerprints)
+		if err != nil {
+			return nil, errors.Errorf("store.AddResultFingerprints: %w", err)
+		}
+	}
+	return matches, nil
+}
+
+// isCompleteSearch returns true if v contains all matches of the search.
+// Matches are missing if the search hit its result limit, timed out, skipped
+// repositories which are still cloning, or returned errors.
+func isCompleteSearch(v *gqlSearchResponse) bool {
+	if v == nil || len(v.Errors) > 0 {
+		return false
+	}
+	results := v.Data.Search.Results
+	return !results.LimitHit && len(results.Cloning) == 0 && len(results.Timedout) == 0
+}
+
+// extractContentMatches returns a result for each matched line of the file
+// matches of v, or for the file if only its path matched. Results we fail to
+// extract are skipped.
+func extractContentMatches(v *gqlSearchResponse) []*cm.SearchResult {
+	if v == nil {
+		return nil
+	}
+	var matches []*cm.SearchResult
+	for _, r := range v.Data.Search.Results.Results {
+		ms, err := extractContentMatch(r)
+		if err != nil {
+			// Error already logged by extractContentMatch.
+			continue
+		}
+		matches = append(matches, ms...)
+	}
+	return matches
+}
+
+func extractContentMatch(result interface{}) (matches []*cm.SearchResult, err error) {
+	// Use recover because we assume the data structure here a lot, for less
+	// error checking.
+	defer func() {
+		if r := recover(); r != nil {
+			// Same as net/http
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			log.Printf("failed to extract content matches from search result: %v\n%s", r, buf)
+			err = errors.Errorf("failed to extract content matches from search result")
+		}
+	}()
+
+	m := result.(map[string]interface{})
+	typeName := m["__typename"].(string)
+	if typeName != "FileMatch" {
+		return nil, errors.Errorf("unexpected result __typename %q", typeName)
+	}
+	repository := m["repository"].(map[string]interface{})["name"].(string)
+	file := m["file"].(map[string]interface{})
+	path := file["path"].(string)
+	url := file["url"].(string)
+
+	lineMatches, _ := m["lineMatches"].([]interface{})
+	if len(lineMatches) == 0 {
+		return []*cm.SearchResult{{Repository: repository, URL: url, Path: path}}, nil
+	}
+	matches = make([]*cm.SearchResult, 0, len(lineMatches))
+	for _, lm := range lineMatches {
+		lm := lm.(map[string]interface{})
+		// lineNumber is 0-based.
+		lineNumber := int(lm["lineNumber"].(float64)) + 1
+		matches = append(matches, &cm.SearchResult{
+			Repository: repository,
+			URL:        fmt.Sprintf("%s?L%d", url, lineNumber),
+			Path:       path,
+			LineNumber: lineNumber,
+			Preview:    lm["preview"].(string),
+		})
+	}
+	return matches, nil
+}
@@ -0,0 +1,115 @@
+package background
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+)
+
+func TestIsContentTrigger(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "TODO(security) repo:^github\\.com/sourcegraph/sourcegraph$@main", want: true},
+		{query: "TODO type:file", want: true},
+		{query: "TODO type:diff", want: false},
+		{query: "TODO type:commit", want: false},
+		{query: "(TODO type:diff) or (FIXME type:commit)", want: false},
+	}
+	for _, tt := range tests {
+		if got := isContentTrigger(tt.query); got != tt.want {
+			t.Errorf("isContentTrigger(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestNewContentQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "TODO", want: "TODO count:10000"},
+		{query: "TODO count:50", want: "TODO count:50"},
+	}
+	for _, tt := range tests {
+		if got := newContentQuery(&cm.MonitorQuery{QueryString: tt.query}); got != tt.want {
+			t.Errorf("newContentQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExtractContentMatches(t *testing.T) {
+	var v gqlSearchResponse
+	err := json.Unmarshal([]byte(`{"data":{"search":{"results":{"results":[
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "github.com/sourcegraph/sourcegraph"},
+			"file": {"path": "main.go", "url": "/github.com/sourcegraph/sourcegraph/-/blob/main.go"},
+			"lineMatches": [
+				{"preview": "// TODO(security): validate input", "lineNumber": 2, "offsetAndLengths": [[3, 14]]},
+				{"preview": "\t// TODO(security): escape", "lineNumber": 9, "offsetAndLengths": [[4, 14]]}
+			]
+		},
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "github.com/sourcegraph/sourcegraph"},
+			"file": {"path": "TODO.md", "url": "/github.com/sourcegraph/sourcegraph/-/blob/TODO.md"},
+			"lineMatches": []
+		},
+		{"__typename": "Repository"}
+	]}}}}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*cm.SearchResult{
+		{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/blob/main.go?L3",
+			Path:       "main.go",
+			LineNumber: 3,
+			Preview:    "// TODO(security): validate input",
+		},
+		{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/blob/main.go?L10",
+			Path:       "main.go",
+			LineNumber: 10,
+			Preview:    "\t// TODO(security): escape",
+		},
+		{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/blob/TODO.md",
+			Path:       "TODO.md",
+		},
+	}
+	if diff := cmp.Diff(want, extractContentMatches(&v)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestIsCompleteSearch(t *testing.T) {
+	tests := []struct {
+		response string
+		want     bool
+	}{
+		{response: `{"data":{"search":{"results":{"results":[]}}}}`, want: true},
+		{response: `{"data":{"search":{"results":{"limitHit":true,"results":[]}}}}`, want: false},
+		{response: `{"data":{"search":{"results":{"cloning":[{"name":"github.com/sourcegraph/sourcegraph"}],"results":[]}}}}`, want: false},
+		{response: `{"data":{"search":{"results":{"timedout":[{"name":"github.com/sourcegraph/sourcegraph"}],"results":[]}}}}`, want: false},
+		{response: `{"data":{"search":{"results":{"results":[]}}},"errors":[{"message":"boom"}]}`, want: false},
+	}
+	for _, tt := range tests {
+		var v gqlSearchResponse
+		if err := json.Unmarshal([]byte(tt.response), &v); err != nil {
+			t.Fatal(err)
+		}
+		if got := isCompleteSearch(&v); got != tt.want {
+			t.Errorf("isCompleteSearch(%s) = %v, want %v", tt.response, got, tt.want)
+		}
+	}
+}
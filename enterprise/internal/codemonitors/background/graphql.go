@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"time"
 
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 
@@ -33,6 +34,13 @@ const gqlSearchQuery = `query Search(
 			results {
 				__typename
 				... on FileMatch {
+					repository {
+						name
+					}
+					file {
+						path
+						url
+					}
 					limitHit
 					lineMatches {
 						preview
@@ -41,6 +49,7 @@ const gqlSearchQuery = `query Search(
 					}
 				}
 				... on CommitSearchResult {
+					url
 					refs {
 						name
 						displayName
@@ -111,6 +120,7 @@ type gqlSearchResponse struct {
 		Search struct {
 			Results struct {
 				ApproximateResultCount string
+				LimitHit               bool
 				Cloning                []*api.Repo
 				Timedout               []*api.Repo
 				Results                []interface{}
@@ -201,3 +211,73 @@ func extractTime(result interface{}) (t *time.Time, err error) {
 		return nil, errors.Errorf("unexpected result __typename %q", typeName)
 	}
 }
+
+// maxSearchResultMessageLength is the maximum length of the commit message of
+// a search result summary.
+const maxSearchResultMessageLength = 200
+
+// extractSearchResults summarizes the results of v for the notifications of
+// actions. Results we fail to summarize are skipped.
+func extractSearchResults(v *gqlSearchResponse) []*cm.SearchResult {
+	if v == nil {
+		return nil
+	}
+	results := make([]*cm.SearchResult, 0, len(v.Data.Search.Results.Results))
+	for _, r := range v.Data.Search.Results.Results {
+		sr, err := extractSearchResult(r)
+		if err != nil {
+			// Error already logged by extractSearchResult.
+			continue
+		}
+		results = append(results, sr)
+	}
+	return results
+}
+
+// extractSearchResult summarizes the given search result.
+func extractSearchResult(result interface{}) (sr *cm.SearchResult, err error) {
+	// Use recover because we assume the data structure here a lot, for less
+	// error checking.
+	defer func() {
+		if r := recover(); r != nil {
+			// Same as net/http
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			log.Printf("failed to summarize search result: %v\n%s", r, buf)
+			err = errors.Errorf("failed to summarize search result")
+		}
+	}()
+
+	m := result.(map[string]interface{})
+	typeName := m["__typename"].(string)
+	switch typeName {
+	case "CommitSearchResult":
+		commit := m["commit"].(map[string]interface{})
+		repository := commit["repository"].(map[string]interface{})
+		author := commit["author"].(map[string]interface{})
+		person := author["person"].(map[string]interface{})
+
+		message := commit["message"].(string)
+		if runes := []rune(message); len(runes) > maxSearchResultMessageLength {
+			message = string(runes[:maxSearchResultMessageLength]) + "..."
+		}
+		return &cm.SearchResult{
+			Repository: repository["name"].(string),
+			URL:        m["url"].(string),
+			Commit:     commit["oid"].(string),
+			Message:    message,
+			Author:     person["displayName"].(string),
+		}, nil
+	case "FileMatch":
+		repository := m["repository"].(map[string]interface{})
+		file := m["file"].(map[string]interface{})
+		return &cm.SearchResult{
+			Repository: repository["name"].(string),
+			URL:        file["url"].(string),
+			Path:       file["path"].(string),
+		}, nil
+	default:
+		return nil, errors.Errorf("unexpected result __typename %q", typeName)
+	}
+}
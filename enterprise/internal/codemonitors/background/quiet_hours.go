@@ -0,0 +1,40 @@
+package background
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+)
+
+// quietHoursEnd returns the time the quiet hours q end at if t is within them.
+// Actions of monitors are not executed during quiet hours, but delayed until
+// their end.
+func quietHoursEnd(t time.Time, q *cm.QuietHours) (end time.Time, quiet bool, err error) {
+	if q == nil || q.Start == q.End {
+		return time.Time{}, false, nil
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "quiet hours time zone %q", q.Timezone)
+	}
+
+	t = t.In(loc)
+	hour := t.Hour()
+	if q.Start < q.End {
+		quiet = hour >= q.Start && hour < q.End
+	} else {
+		// Quiet hours last over midnight.
+		quiet = hour >= q.Start || hour < q.End
+	}
+	if !quiet {
+		return time.Time{}, false, nil
+	}
+
+	day := t
+	if hour >= q.End {
+		day = t.AddDate(0, 0, 1)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), q.End, 0, 0, 0, loc), true, nil
+}
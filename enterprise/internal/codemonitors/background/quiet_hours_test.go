@@ -0,0 +1,53 @@
+package background
+
+import (
+	"testing"
+	"time"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2021, time.October, day, hour, minute, 0, 0, berlin)
+	}
+	overnight := &cm.QuietHours{Start: 22, End: 7, Timezone: "Europe/Berlin"}
+	office := &cm.QuietHours{Start: 9, End: 17, Timezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		quietHours *cm.QuietHours
+		wantQuiet  bool
+		wantEnd    time.Time
+	}{
+		{name: "no quiet hours", t: at(4, 23, 0)},
+		{name: "before overnight quiet hours", t: at(4, 21, 59), quietHours: overnight},
+		{name: "overnight quiet hours before midnight", t: at(4, 22, 0), quietHours: overnight, wantQuiet: true, wantEnd: at(5, 7, 0)},
+		{name: "overnight quiet hours after midnight", t: at(5, 6, 59), quietHours: overnight, wantQuiet: true, wantEnd: at(5, 7, 0)},
+		{name: "after overnight quiet hours", t: at(5, 7, 0), quietHours: overnight},
+		{name: "within daytime quiet hours", t: at(4, 12, 30), quietHours: office, wantQuiet: true, wantEnd: at(4, 17, 0)},
+		{name: "after daytime quiet hours", t: at(4, 17, 30), quietHours: office},
+		{name: "other time zone", t: at(4, 12, 30).UTC(), quietHours: office, wantQuiet: true, wantEnd: at(4, 17, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet, err := quietHoursEnd(tt.t, tt.quietHours)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quiet != tt.wantQuiet || !end.Equal(tt.wantEnd) {
+				t.Fatalf("got quiet=%v end=%v, want quiet=%v end=%v", quiet, end, tt.wantQuiet, tt.wantEnd)
+			}
+		})
+	}
+
+	_, _, err = quietHoursEnd(at(4, 12, 0), &cm.QuietHours{Start: 9, End: 17, Timezone: "Nowhere/Special"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown time zone")
+	}
+}
@@ -0,0 +1,185 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+)
+
+const (
+	utmSourceSlack   = "code-monitoring-slack"
+	utmSourceWebhook = "code-monitoring-webhook"
+
+	// maxSlackResults is the maximum number of results listed in a Slack
+	// message. The message links to the search for all of them.
+	maxSlackResults = 10
+)
+
+// webhookPayload is the JSON body webhook actions post. The Slack message of
+// Slack webhook actions is built from it, too.
+type webhookPayload struct {
+	MonitorDescription string             `json:"monitorDescription"`
+	MonitorURL         string             `json:"monitorURL"`
+	Query              string             `json:"query"`
+	QueryURL           string             `json:"queryURL"`
+	NumResults         int                `json:"numResults"`
+	Results            []*cm.SearchResult `json:"results"`
+}
+
+func newWebhookPayload(ctx context.Context, m *cm.ActionJobMetadata, utmSource string) (*webhookPayload, error) {
+	monitorURL, err := email.CodeMonitorURL(ctx, m.MonitorID, utmSource)
+	if err != nil {
+		return nil, err
+	}
+	queryURL, err := email.SearchURL(ctx, m.Query, utmSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// The URLs of the results are relative to the external URL.
+	results := make([]*cm.SearchResult, 0, len(m.Results))
+	for _, r := range m.Results {
+		u, err := email.SourcegraphURL(ctx, r.URL, "", utmSource)
+		if err != nil {
+			return nil, err
+		}
+		result := *r
+		result.URL = u
+		results = append(results, &result)
+	}
+
+	numResults := zeroOrVal(m.NumResults)
+	if numResults < len(results) {
+		numResults = len(results)
+	}
+	return &webhookPayload{
+		MonitorDescription: m.Description,
+		MonitorURL:         monitorURL,
+		Query:              m.Query,
+		QueryURL:           queryURL,
+		NumResults:         numResults,
+		Results:            results,
+	}, nil
+}
+
+// sendWebhook posts p as JSON to url with the client for external requests,
+// since the URL is provided by users. Failed deliveries are retried by the
+// worker of the action jobs.
+func sendWebhook(ctx context.Context, url string, p *webhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "webhook: marshal json")
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "webhook: create post request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	resp, err := httpcli.ExternalDoer.Do(req.WithContext(timeoutCtx))
+	if err != nil {
+		return errors.Wrap(err, "webhook: http request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Only report the beginning of the response. We don't include the URL
+		// because it often contains a secret.
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("webhook: request failed with %d %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// newSlackPayload returns the Slack message for p. It lists the first
+// maxSlackResults results as attachments.
+func newSlackPayload(p *webhookPayload) *slack.Payload {
+	plural := "s"
+	if p.NumResults == 1 {
+		plural = ""
+	}
+	text := fmt.Sprintf("*%d* new search result%s for code monitor <%s|%s>. <%s|View search results>",
+		p.NumResults,
+		plural,
+		p.MonitorURL,
+		escapeSlack(p.MonitorDescription),
+		p.QueryURL,
+	)
+
+	results := p.Results
+	if len(results) > maxSlackResults {
+		results = results[:maxSlackResults]
+	}
+	attachments := make([]*slack.Attachment, 0, len(results))
+	for _, r := range results {
+		title := r.Repository
+		switch {
+		case r.Commit != "":
+			title += "@" + abbreviateOID(r.Commit)
+		case r.Path != "":
+			title += " › " + r.Path
+			if r.LineNumber > 0 {
+				title += fmt.Sprintf(":%d", r.LineNumber)
+			}
+		}
+		text := r.Message
+		if r.Preview != "" {
+			text = r.Preview
+		}
+		attachments = append(attachments, &slack.Attachment{
+			AuthorName: r.Author,
+			Fallback:   title,
+			MarkdownIn: []string{},
+			Text:       text,
+			Title:      title,
+			TitleLink:  r.URL,
+		})
+	}
+
+	return &slack.Payload{
+		Username:    "Sourcegraph code monitor",
+		IconEmoji:   ":mag:",
+		UnfurlLinks: false,
+		UnfurlMedia: false,
+		Text:        text,
+		Attachments: attachments,
+	}
+}
+
+// sendSlackNotification posts the message for p to the Slack incoming webhook
+// url. Failed deliveries are retried by the worker of the action jobs.
+//
+// The URL is validated again because actions may have been created before
+// Slack webhook URLs were limited to Slack's host.
+func sendSlackNotification(ctx context.Context, url string, p *webhookPayload) error {
+	if err := cm.ValidateSlackWebhookURL(url); err != nil {
+		return err
+	}
+	return slack.New(url).Post(ctx, newSlackPayload(p))
+}
+
+// escapeSlack escapes the control characters of Slack's message formatting.
+func escapeSlack(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func abbreviateOID(oid string) string {
+	if len(oid) > 7 {
+		return oid[:7]
+	}
+	return oid
+}
@@ -0,0 +1,100 @@
+package background
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+)
+
+func TestNewSlackPayload(t *testing.T) {
+	p := &webhookPayload{
+		MonitorDescription: "new <TODO>s",
+		MonitorURL:         "https://sourcegraph.test/code-monitoring/1",
+		Query:              "TODO type:diff",
+		QueryURL:           "https://sourcegraph.test/search?q=TODO+type%3Adiff",
+		NumResults:         maxSlackResults + 2,
+	}
+	for i := 0; i < p.NumResults; i++ {
+		p.Results = append(p.Results, &cm.SearchResult{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "https://sourcegraph.test/github.com/sourcegraph/sourcegraph/-/commit/0123456789abcdef",
+			Commit:     "0123456789abcdef",
+			Message:    "add TODO",
+			Author:     "alice",
+		})
+	}
+
+	got := newSlackPayload(p)
+
+	wantText := "*12* new search results for code monitor <https://sourcegraph.test/code-monitoring/1|new &lt;TODO&gt;s>. <https://sourcegraph.test/search?q=TODO+type%3Adiff|View search results>"
+	if got.Text != wantText {
+		t.Errorf("got text %q, want %q", got.Text, wantText)
+	}
+	if len(got.Attachments) != maxSlackResults {
+		t.Fatalf("got %d attachments, want %d", len(got.Attachments), maxSlackResults)
+	}
+	a := got.Attachments[0]
+	if diff := cmp.Diff([]string{"github.com/sourcegraph/sourcegraph@0123456", p.Results[0].URL, "add TODO", "alice"}, []string{a.Title, a.TitleLink, a.Text, a.AuthorName}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestExtractSearchResults(t *testing.T) {
+	var v gqlSearchResponse
+	v.Data.Search.Results.Results = []interface{}{
+		map[string]interface{}{
+			"__typename": "CommitSearchResult",
+			"url":        "/github.com/foo/bar/-/commit/deadbeef",
+			"commit": map[string]interface{}{
+				"oid":        "deadbeef",
+				"message":    "fix bug",
+				"repository": map[string]interface{}{"name": "github.com/foo/bar"},
+				"author": map[string]interface{}{
+					"person": map[string]interface{}{"displayName": "alice"},
+				},
+			},
+		},
+		map[string]interface{}{
+			"__typename": "FileMatch",
+			"repository": map[string]interface{}{"name": "github.com/foo/bar"},
+			"file": map[string]interface{}{
+				"path": "main.go",
+				"url":  "/github.com/foo/bar/-/blob/main.go",
+			},
+		},
+		// Malformed results are skipped.
+		map[string]interface{}{"__typename": "CommitSearchResult"},
+	}
+
+	want := []*cm.SearchResult{{
+		Repository: "github.com/foo/bar",
+		URL:        "/github.com/foo/bar/-/commit/deadbeef",
+		Commit:     "deadbeef",
+		Message:    "fix bug",
+		Author:     "alice",
+	}, {
+		Repository: "github.com/foo/bar",
+		URL:        "/github.com/foo/bar/-/blob/main.go",
+		Path:       "main.go",
+	}}
+	if diff := cmp.Diff(want, extractSearchResults(&v)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSendSlackNotificationRejectsInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer server.Close()
+
+	err := sendSlackNotification(context.Background(), server.URL, &webhookPayload{})
+	if err == nil {
+		t.Fatal("expected error for internal address")
+	}
+}
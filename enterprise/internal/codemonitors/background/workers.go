@@ -20,6 +20,10 @@ import (
 
 const (
 	eventRetentionInDays int = 7
+
+	// resultHistoryRetentionInDays is how long the results of code monitors are
+	// kept in their history.
+	resultHistoryRetentionInDays int = 90
 )
 
 func newTriggerQueryRunner(ctx context.Context, s *cm.Store, metrics codeMonitorsMetrics) *workerutil.Worker {
@@ -72,6 +76,10 @@ func newTriggerJobsLogDeleter(ctx context.Context, store *cm.Store) goroutine.Ba
 			if err != nil {
 				return err
 			}
+			err = store.DeleteOldResultHistory(ctx, resultHistoryRetentionInDays)
+			if err != nil {
+				return err
+			}
 			return nil
 		})
 	return goroutine.NewPeriodicGoroutine(ctx, 60*time.Minute, deleteLogs)
@@ -152,7 +160,13 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 	if err != nil {
 		return err
 	}
-	newQuery := newQueryWithAfterFilter(q)
+	contentTrigger := isContentTrigger(q.QueryString)
+	var newQuery string
+	if contentTrigger {
+		newQuery = newContentQuery(q)
+	} else {
+		newQuery = newQueryWithAfterFilter(q)
+	}
 
 	// Search.
 	var results *gqlSearchResponse
@@ -160,18 +174,40 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 	if err != nil {
 		return err
 	}
-	var numResults int
-	if results != nil {
+	var (
+		numResults    int
+		searchResults []*cm.SearchResult
+	)
+	if contentTrigger {
+		searchResults, err = newContentMatches(ctx, s, q.Id, results)
+		if err != nil {
+			return err
+		}
+		numResults = len(searchResults)
+	} else if results != nil {
+		searchResults = extractSearchResults(results)
 		numResults = len(results.Data.Search.Results.Results)
 	}
 	if numResults > 0 {
-		err := s.EnqueueActionEmailsForQueryIDInt64(ctx, q.Id, record.RecordID())
+		err := s.LogSearchResults(ctx, searchResults, record.RecordID())
 		if err != nil {
-			return errors.Errorf("store.EnqueueActionEmailsForQueryIDInt64: %w", err)
+			return errors.Errorf("store.LogSearchResults: %w", err)
+		}
+		err = s.LogResultHistory(ctx, q.Monitor, record.RecordID(), searchResults)
+		if err != nil {
+			return errors.Errorf("store.LogResultHistory: %w", err)
+		}
+		err = s.EnqueueActionJobsForQueryIDInt64(ctx, q.Id, record.RecordID())
+		if err != nil {
+			return errors.Errorf("store.EnqueueActionJobsForQueryIDInt64: %w", err)
 		}
 	}
-	// Log next_run and latest_result to table cm_queries.
-	newLatestResult := latestResultTime(q.LatestResult, results, err)
+	// Log next_run and latest_result to table cm_queries. Content search
+	// triggers don't use latest_result.
+	newLatestResult := s.Clock()()
+	if !contentTrigger {
+		newLatestResult = latestResultTime(q.LatestResult, results, err)
+	}
 	err = s.SetTriggerQueryNextRun(ctx, q.Id, s.Clock()().Add(5*time.Minute), newLatestResult.UTC())
 	if err != nil {
 		return err
@@ -203,11 +239,8 @@ func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (er
 	defer func() { err = s.Done(err) }()
 
 	var (
-		j    *cm.ActionJob
-		m    *cm.ActionJobMetadata
-		e    *cm.MonitorEmail
-		recs []*cm.Recipient
-		data *email.TemplateDataNewSearchResults
+		j *cm.ActionJob
+		m *cm.ActionJobMetadata
 	)
 
 	var ok bool
@@ -221,12 +254,68 @@ func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (er
 		return errors.Errorf("store.GetActionJobMetadata: %w", err)
 	}
 
-	e, err = s.ActionEmailByIDInt64(ctx, j.Email)
+	// Delay the job until the end of the quiet hours of the monitor. The job
+	// then reports the results of the trigger events of the quiet hours, too.
+	var monitor *cm.Monitor
+	monitor, err = s.MonitorByIDInt64(ctx, m.MonitorID)
+	if err != nil {
+		return errors.Errorf("store.MonitorByIDInt64: %w", err)
+	}
+	end, quiet, err := quietHoursEnd(s.Now(), monitor.QuietHours)
+	if err != nil {
+		return err
+	}
+	if quiet {
+		return createDBWorkerStoreForActionJobs(s).Requeue(ctx, j.Id, end)
+	}
+
+	switch {
+	case j.Email != nil:
+		return sendEmails(ctx, s, *j.Email, m)
+	case j.SlackWebhook != nil:
+		var w *cm.MonitorSlackWebhook
+		w, err = s.ActionSlackWebhookByIDInt64(ctx, *j.SlackWebhook)
+		if err != nil {
+			return errors.Errorf("store.ActionSlackWebhookByIDInt64: %w", err)
+		}
+		var p *webhookPayload
+		p, err = newWebhookPayload(ctx, m, utmSourceSlack)
+		if err != nil {
+			return errors.Errorf("newWebhookPayload: %w", err)
+		}
+		return sendSlackNotification(ctx, w.URL, p)
+	case j.Webhook != nil:
+		var w *cm.MonitorWebhook
+		w, err = s.ActionWebhookByIDInt64(ctx, *j.Webhook)
+		if err != nil {
+			return errors.Errorf("store.ActionWebhookByIDInt64: %w", err)
+		}
+		var p *webhookPayload
+		p, err = newWebhookPayload(ctx, m, utmSourceWebhook)
+		if err != nil {
+			return errors.Errorf("newWebhookPayload: %w", err)
+		}
+		return sendWebhook(ctx, w.URL, p)
+	default:
+		return errors.Errorf("action job %d has no action", j.Id)
+	}
+}
+
+// sendEmails sends the email of the email action with the given ID to each of
+// its recipients.
+func sendEmails(ctx context.Context, s *cm.Store, emailID int64, m *cm.ActionJobMetadata) (err error) {
+	var (
+		e    *cm.MonitorEmail
+		recs []*cm.Recipient
+		data *email.TemplateDataNewSearchResults
+	)
+
+	e, err = s.ActionEmailByIDInt64(ctx, emailID)
 	if err != nil {
 		return errors.Errorf("store.ActionEmailByIDInt64: %w", err)
 	}
 
-	recs, err = s.AllRecipientsForEmailIDInt64(ctx, j.Email)
+	recs, err = s.AllRecipientsForEmailIDInt64(ctx, emailID)
 	if err != nil {
 		return errors.Errorf("store.AllRecipientsForEmailIDInt64: %w", err)
 	}
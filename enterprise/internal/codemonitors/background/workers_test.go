@@ -2,6 +2,9 @@ package background
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -9,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go/relay"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/storetest"
@@ -117,3 +121,104 @@ func TestActionRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestActionRunnerWebhook(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	email.MockExternalURL = func() *url.URL {
+		externalURL, _ := url.Parse("https://www.sourcegraph.com")
+		return externalURL
+	}
+
+	var got webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	db := dbtesting.GetDB(t)
+	// Empty database, preserve schema.
+	dbtesting.SetupGlobalTestDB(t)
+	now := time.Now()
+	s := codemonitors.NewStoreWithClock(db, func() time.Time { return now })
+	ctx, ts := storetest.NewTestStoreWithStore(t, s)
+	_, _, namespace, userCtx := storetest.NewTestUser(ctx, t)
+
+	_, err := ts.CreateCodeMonitor(userCtx, &graphqlbackend.CreateCodeMonitorArgs{
+		Monitor: &graphqlbackend.CreateMonitorArgs{
+			Namespace:   namespace,
+			Description: "test description",
+			Enabled:     true,
+		},
+		Trigger: &graphqlbackend.CreateTriggerArgs{Query: "test type:commit"},
+		Actions: []*graphqlbackend.CreateActionArgs{{
+			Webhook: &graphqlbackend.CreateActionWebhookArgs{Enabled: true, URL: server.URL},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		queryID      int64 = 1
+		triggerEvent       = 1
+		results            = []*codemonitors.SearchResult{{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/commit/deadbeef",
+			Commit:     "deadbeef",
+			Message:    "fix bug",
+			Author:     "alice",
+		}}
+	)
+	err = ts.EnqueueTriggerQueries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ts.LogSearch(ctx, "test type:commit", len(results), triggerEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ts.LogSearchResults(ctx, results, triggerEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ts.EnqueueActionJobsForQueryIDInt64(ctx, queryID, triggerEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := ts.ActionJobForIDInt(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Webhook == nil {
+		t.Fatalf("expected a webhook job, got %+v", record)
+	}
+
+	a := actionRunner{s}
+	err = a.Handle(ctx, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := webhookPayload{
+		MonitorDescription: "test description",
+		MonitorURL:         "https://www.sourcegraph.com/code-monitoring/" + string(relay.MarshalID("CodeMonitor", 1)) + "?utm_source=code-monitoring-webhook",
+		Query:              "test type:commit",
+		QueryURL:           "https://www.sourcegraph.com/search?q=test+type%3Acommit&utm_source=code-monitoring-webhook",
+		NumResults:         1,
+		Results: []*codemonitors.SearchResult{{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "https://www.sourcegraph.com/github.com/sourcegraph/sourcegraph/-/commit/deadbeef?utm_source=code-monitoring-webhook",
+			Commit:     "deadbeef",
+			Message:    "fix bug",
+			Author:     "alice",
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("diff: %s", diff)
+	}
+}
@@ -47,12 +47,12 @@ func NewTemplateDataForNewSearchResults(ctx context.Context, monitorDescription,
 		priority                  string
 		numberOfResultsWithDetail string
 	)
-	searchURL, err = getSearchURL(ctx, queryString, utmSourceEmail)
+	searchURL, err = SearchURL(ctx, queryString, utmSourceEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	codeMonitorURL, err = getCodeMonitorURL(ctx, email.Monitor, utmSourceEmail)
+	codeMonitorURL, err = CodeMonitorURL(ctx, email.Monitor, utmSourceEmail)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +68,12 @@ func NewTemplateDataForNewSearchResults(ctx context.Context, monitorDescription,
 	} else {
 		numberOfResultsWithDetail = fmt.Sprintf("There were %d new search results for your query", numResults)
 	}
+	switch email.Digest {
+	case codemonitors.DigestHourly:
+		numberOfResultsWithDetail += " in the past hour"
+	case codemonitors.DigestDaily:
+		numberOfResultsWithDetail += " in the past day"
+	}
 
 	return &TemplateDataNewSearchResults{
 		Priority:                  priority,
@@ -105,15 +111,19 @@ func sendEmail(ctx context.Context, userID int32, template txtypes.Templates, da
 	return nil
 }
 
-func getSearchURL(ctx context.Context, query, utmSource string) (string, error) {
-	return sourcegraphURL(ctx, "search", query, utmSource)
+// SearchURL returns the URL of the results page of query. It is shared with
+// the other actions of code monitors.
+func SearchURL(ctx context.Context, query, utmSource string) (string, error) {
+	return SourcegraphURL(ctx, "search", query, utmSource)
 }
 
-func getCodeMonitorURL(ctx context.Context, monitorID int64, utmSource string) (string, error) {
-	return sourcegraphURL(ctx, fmt.Sprintf("code-monitoring/%s", relay.MarshalID(MonitorKind, monitorID)), "", utmSource)
+// CodeMonitorURL returns the URL of the page of the code monitor.
+func CodeMonitorURL(ctx context.Context, monitorID int64, utmSource string) (string, error) {
+	return SourcegraphURL(ctx, fmt.Sprintf("code-monitoring/%s", relay.MarshalID(MonitorKind, monitorID)), "", utmSource)
 }
 
-func sourcegraphURL(ctx context.Context, path, query, utmSource string) (string, error) {
+// SourcegraphURL resolves path against the external URL of the instance.
+func SourcegraphURL(ctx context.Context, path, query, utmSource string) (string, error) {
 	if MockExternalURL != nil {
 		externalURL = MockExternalURL()
 	}
@@ -23,6 +23,15 @@ type Monitor struct {
 	Enabled         bool
 	NamespaceUserID *int32
 	NamespaceOrgID  *int32
+	QuietHours      *QuietHours
+}
+
+// QuietHours are the hours of the day during which the actions of a monitor
+// are delayed. If End is before Start, quiet hours last over midnight.
+type QuietHours struct {
+	Start    int
+	End      int
+	Timezone string
 }
 
 var monitorColumns = []*sqlf.Query{
@@ -35,6 +44,9 @@ var monitorColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_monitors.enabled"),
 	sqlf.Sprintf("cm_monitors.namespace_user_id"),
 	sqlf.Sprintf("cm_monitors.namespace_org_id"),
+	sqlf.Sprintf("cm_monitors.quiet_hours_start"),
+	sqlf.Sprintf("cm_monitors.quiet_hours_end"),
+	sqlf.Sprintf("cm_monitors.quiet_hours_timezone"),
 }
 
 func (s *Store) CreateMonitor(ctx context.Context, args *graphqlbackend.CreateMonitorArgs) (m *Monitor, err error) {
@@ -87,7 +99,7 @@ func (s *Store) Monitors(ctx context.Context, userID int32, args *graphqlbackend
 }
 
 const monitorByIDFmtStr = `
-SELECT id, created_by, created_at, changed_by, changed_at, description, enabled, namespace_user_id, namespace_org_id
+SELECT id, created_by, created_at, changed_by, changed_at, description, enabled, namespace_user_id, namespace_org_id, quiet_hours_start, quiet_hours_end, quiet_hours_timezone
 FROM cm_monitors
 WHERE id = %s
 `
@@ -108,7 +120,7 @@ func (s *Store) TotalCountMonitors(ctx context.Context, userID int32) (count int
 }
 
 const monitorsFmtStr = `
-SELECT id, created_by, created_at, changed_by, changed_at, description, enabled, namespace_user_id, namespace_org_id
+SELECT id, created_by, created_at, changed_by, changed_at, description, enabled, namespace_user_id, namespace_org_id, quiet_hours_start, quiet_hours_end, quiet_hours_timezone
 FROM cm_monitors
 WHERE namespace_user_id = %s
 AND id > %s
@@ -159,8 +171,8 @@ func (s *Store) toggleCodeMonitorQuery(ctx context.Context, args *graphqlbackend
 
 const insertCodeMonitorFmtStr = `
 INSERT INTO cm_monitors
-(created_at, created_by, changed_at, changed_by, description, enabled, namespace_user_id, namespace_org_id)
-VALUES (%s,%s,%s,%s,%s,%s,%s,%s)
+(created_at, created_by, changed_at, changed_by, description, enabled, namespace_user_id, namespace_org_id, quiet_hours_start, quiet_hours_end, quiet_hours_timezone)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s)
 RETURNING %s;
 `
 
@@ -171,8 +183,13 @@ func (s *Store) createCodeMonitorQuery(ctx context.Context, args *graphqlbackend
 	if err != nil {
 		return nil, err
 	}
+	err = validateQuietHours(args.QuietHours)
+	if err != nil {
+		return nil, err
+	}
 	now := s.Now()
 	a := actor.FromContext(ctx)
+	start, end, timezone := quietHoursColumns(args.QuietHours)
 	return sqlf.Sprintf(
 		insertCodeMonitorFmtStr,
 		now,
@@ -183,6 +200,9 @@ func (s *Store) createCodeMonitorQuery(ctx context.Context, args *graphqlbackend
 		args.Enabled,
 		nilOrInt32(userID),
 		nilOrInt32(orgID),
+		start,
+		end,
+		timezone,
 		sqlf.Join(monitorColumns, ", "),
 	), nil
 }
@@ -193,6 +213,9 @@ SET description = %s,
 	enabled	= %s,
 	namespace_user_id = %s,
 	namespace_org_id = %s,
+	quiet_hours_start = %s,
+	quiet_hours_end = %s,
+	quiet_hours_timezone = %s,
 	changed_by = %s,
 	changed_at = %s
 WHERE id = %s
@@ -206,6 +229,10 @@ func (s *Store) updateCodeMonitorQuery(ctx context.Context, args *graphqlbackend
 	if err != nil {
 		return nil, err
 	}
+	err = validateQuietHours(args.Monitor.Update.QuietHours)
+	if err != nil {
+		return nil, err
+	}
 	now := s.Now()
 	a := actor.FromContext(ctx)
 	var monitorID int64
@@ -213,12 +240,16 @@ func (s *Store) updateCodeMonitorQuery(ctx context.Context, args *graphqlbackend
 	if err != nil {
 		return nil, err
 	}
+	start, end, timezone := quietHoursColumns(args.Monitor.Update.QuietHours)
 	return sqlf.Sprintf(
 		updateCodeMonitorFmtStr,
 		args.Monitor.Update.Description,
 		args.Monitor.Update.Enabled,
 		nilOrInt32(userID),
 		nilOrInt32(orgID),
+		start,
+		end,
+		timezone,
 		a.UID,
 		now,
 		monitorID,
@@ -241,10 +272,44 @@ func (s *Store) deleteMonitorQuery(ctx context.Context, args *graphqlbackend.Del
 	return query, nil
 }
 
+// validateQuietHours returns an error if q are not valid quiet hours of a
+// monitor. q may be nil.
+func validateQuietHours(q *graphqlbackend.MonitorQuietHoursArgs) error {
+	if q == nil {
+		return nil
+	}
+	if q.Start < 0 || q.Start > 23 || q.End < 0 || q.End > 23 {
+		return errors.Errorf("invalid quiet hours %d-%d: hours must be between 0 and 23", q.Start, q.End)
+	}
+	if q.Start == q.End {
+		return errors.Errorf("invalid quiet hours %d-%d: start and end must differ", q.Start, q.End)
+	}
+	if q.Timezone == "" {
+		return errors.Errorf("invalid quiet hours: missing time zone")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return errors.Wrapf(err, "invalid quiet hours time zone %q", q.Timezone)
+	}
+	return nil
+}
+
+// quietHoursColumns returns the values of the quiet hours columns of a monitor
+// for q, which are NULL if q is nil.
+func quietHoursColumns(q *graphqlbackend.MonitorQuietHoursArgs) (start, end *int32, timezone *string) {
+	if q == nil {
+		return nil, nil, nil
+	}
+	return &q.Start, &q.End, &q.Timezone
+}
+
 func scanMonitors(rows *sql.Rows) ([]*Monitor, error) {
 	var ms []*Monitor
 	for rows.Next() {
 		m := &Monitor{}
+		var (
+			quietHoursStart, quietHoursEnd *int
+			quietHoursTimezone             *string
+		)
 		if err := rows.Scan(
 			&m.ID,
 			&m.CreatedBy,
@@ -255,9 +320,19 @@ func scanMonitors(rows *sql.Rows) ([]*Monitor, error) {
 			&m.Enabled,
 			&m.NamespaceUserID,
 			&m.NamespaceOrgID,
+			&quietHoursStart,
+			&quietHoursEnd,
+			&quietHoursTimezone,
 		); err != nil {
 			return nil, err
 		}
+		if quietHoursStart != nil && quietHoursEnd != nil && quietHoursTimezone != nil {
+			m.QuietHours = &QuietHours{
+				Start:    *quietHoursStart,
+				End:      *quietHoursEnd,
+				Timezone: *quietHoursTimezone,
+			}
+		}
 		ms = append(ms, m)
 	}
 	err := rows.Close()
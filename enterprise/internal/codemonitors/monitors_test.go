@@ -0,0 +1,28 @@
+package codemonitors
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestValidateQuietHours(t *testing.T) {
+	cases := []struct {
+		quietHours *graphqlbackend.MonitorQuietHoursArgs
+		valid      bool
+	}{
+		{nil, true},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 22, End: 7, Timezone: "Europe/Berlin"}, true},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 0, End: 8, Timezone: "UTC"}, true},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 9, End: 9, Timezone: "UTC"}, false},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 22, End: 24, Timezone: "UTC"}, false},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: -1, End: 7, Timezone: "UTC"}, false},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 22, End: 7}, false},
+		{&graphqlbackend.MonitorQuietHoursArgs{Start: 22, End: 7, Timezone: "Nowhere/Special"}, false},
+	}
+	for _, c := range cases {
+		if err := validateQuietHours(c.quietHours); (err == nil) != c.valid {
+			t.Errorf("validateQuietHours(%+v) returned %v, want valid=%v", c.quietHours, err, c.valid)
+		}
+	}
+}
@@ -114,7 +114,8 @@ UPDATE cm_queries
 SET query = %s,
 	changed_by = %s,
 	changed_at = %s,
-	latest_result = %s
+	latest_result = %s,
+	fingerprints_updated_at = NULL
 WHERE id = %s
 AND monitor = %s
 RETURNING %s;
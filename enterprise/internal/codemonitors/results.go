@@ -0,0 +1,202 @@
+package codemonitors
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+const resultFingerprintsRecordedFmtStr = `
+SELECT fingerprints_updated_at IS NOT NULL
+FROM cm_queries
+WHERE id = %s
+`
+
+const resultFingerprintsFmtStr = `
+SELECT fingerprint
+FROM cm_result_fingerprints
+WHERE query = %s
+`
+
+// ResultFingerprints returns the fingerprints of the matches the content
+// search trigger found in its previous run. recorded is false if the trigger
+// has not run since it was created or its query was changed.
+func (s *Store) ResultFingerprints(ctx context.Context, queryID int64) (fingerprints map[string]struct{}, recorded bool, err error) {
+	recorded, _, err = basestore.ScanFirstBool(s.Query(ctx, sqlf.Sprintf(resultFingerprintsRecordedFmtStr, queryID)))
+	if err != nil || !recorded {
+		return nil, recorded, err
+	}
+	fs, err := basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(resultFingerprintsFmtStr, queryID)))
+	if err != nil {
+		return nil, false, err
+	}
+	fingerprints = make(map[string]struct{}, len(fs))
+	for _, f := range fs {
+		fingerprints[f] = struct{}{}
+	}
+	return fingerprints, true, nil
+}
+
+const deleteResultFingerprintsFmtStr = `
+DELETE FROM cm_result_fingerprints WHERE query = %s
+`
+
+const insertResultFingerprintsFmtStr = `
+INSERT INTO cm_result_fingerprints (query, fingerprint)
+SELECT %s, unnest(%s::text[])
+ON CONFLICT DO NOTHING
+`
+
+const setFingerprintsUpdatedAtFmtStr = `
+UPDATE cm_queries
+SET fingerprints_updated_at = %s
+WHERE id = %s
+`
+
+// SetResultFingerprints replaces the fingerprints of the matches of the
+// content search trigger with those of its latest run. It should be called
+// within a transaction.
+func (s *Store) SetResultFingerprints(ctx context.Context, queryID int64, fingerprints []string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(deleteResultFingerprintsFmtStr, queryID))
+	if err != nil {
+		return err
+	}
+	return s.AddResultFingerprints(ctx, queryID, fingerprints)
+}
+
+// AddResultFingerprints adds the fingerprints of the matches of the latest run
+// of the content search trigger to those of previous runs. It is used for runs
+// which didn't return all matches.
+func (s *Store) AddResultFingerprints(ctx context.Context, queryID int64, fingerprints []string) error {
+	if len(fingerprints) > 0 {
+		err := s.Exec(ctx, sqlf.Sprintf(insertResultFingerprintsFmtStr, queryID, pq.Array(fingerprints)))
+		if err != nil {
+			return err
+		}
+	}
+	return s.Exec(ctx, sqlf.Sprintf(setFingerprintsUpdatedAtFmtStr, s.Now(), queryID))
+}
+
+// MonitorResult is a search result a code monitor ran its actions for.
+type MonitorResult struct {
+	Id      int64
+	Monitor int64
+	SearchResult
+	CreatedAt time.Time
+}
+
+const logResultHistoryFmtStr = `
+INSERT INTO cm_result_history
+(monitor, trigger_job, repository, url, commit, message, author, path, line_number, preview, created_at)
+VALUES %s
+`
+
+// LogResultHistory adds the new search results of a trigger job to the
+// history of the monitor.
+func (s *Store) LogResultHistory(ctx context.Context, monitorID int64, recordID int, results []*SearchResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	now := s.Now()
+	values := make([]*sqlf.Query, 0, len(results))
+	for _, r := range results {
+		values = append(values, sqlf.Sprintf(
+			"(%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s)",
+			monitorID,
+			recordID,
+			r.Repository,
+			r.URL,
+			dbutil.NewNullString(r.Commit),
+			dbutil.NewNullString(r.Message),
+			dbutil.NewNullString(r.Author),
+			dbutil.NewNullString(r.Path),
+			dbutil.NewNullInt(r.LineNumber),
+			dbutil.NewNullString(r.Preview),
+			now,
+		))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(logResultHistoryFmtStr, sqlf.Join(values, ", ")))
+}
+
+const resultHistoryForMonitorIDInt64FmtStr = `
+SELECT id, monitor, repository, url, commit, message, author, path, line_number, preview, created_at
+FROM cm_result_history
+WHERE monitor = %s
+AND (%s = 0 OR id < %s)
+ORDER BY id DESC
+LIMIT %s
+`
+
+// ResultHistoryForMonitorIDInt64 returns the history of the monitor, newest
+// first.
+func (s *Store) ResultHistoryForMonitorIDInt64(ctx context.Context, monitorID int64, args *graphqlbackend.ListMonitorResultsArgs) ([]*MonitorResult, error) {
+	after, err := unmarshalAfter(args.After)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Query(ctx, sqlf.Sprintf(resultHistoryForMonitorIDInt64FmtStr, monitorID, after, after, args.First))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMonitorResults(rows)
+}
+
+const totalCountResultHistoryFmtStr = `
+SELECT COUNT(*)
+FROM cm_result_history
+WHERE monitor = %s
+`
+
+func (s *Store) TotalCountResultHistory(ctx context.Context, monitorID int64) (int32, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(totalCountResultHistoryFmtStr, monitorID)))
+	return int32(count), err
+}
+
+const deleteOldResultHistoryFmtStr = `
+DELETE FROM cm_result_history
+WHERE created_at < (NOW() - (%s * '1 day'::interval));
+`
+
+// DeleteOldResultHistory deletes the results older than 'retention' days from
+// the history of all monitors.
+func (s *Store) DeleteOldResultHistory(ctx context.Context, retentionInDays int) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteOldResultHistoryFmtStr, retentionInDays))
+}
+
+func scanMonitorResults(rows *sql.Rows) (rs []*MonitorResult, err error) {
+	for rows.Next() {
+		r := &MonitorResult{}
+		if err = rows.Scan(
+			&r.Id,
+			&r.Monitor,
+			&r.Repository,
+			&r.URL,
+			&dbutil.NullString{S: &r.Commit},
+			&dbutil.NullString{S: &r.Message},
+			&dbutil.NullString{S: &r.Author},
+			&dbutil.NullString{S: &r.Path},
+			&dbutil.NullInt{N: &r.LineNumber},
+			&dbutil.NullString{S: &r.Preview},
+			&r.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
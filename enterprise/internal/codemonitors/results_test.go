@@ -0,0 +1,122 @@
+package codemonitors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestResultFingerprints(t *testing.T) {
+	ctx, s := newTestStore(t)
+	_, _, _, userCTX := newTestUser(ctx, t)
+	_, err := s.insertTestMonitor(userCTX, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryID := int64(1)
+
+	_, recorded, err := s.ResultFingerprints(ctx, queryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded {
+		t.Fatal("fingerprints should not be recorded before the first run")
+	}
+
+	// A run without matches records fingerprints, too.
+	err = s.SetResultFingerprints(ctx, queryID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, recorded, err := s.ResultFingerprints(ctx, queryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recorded || len(got) != 0 {
+		t.Fatalf("got %v, recorded=%v, want no fingerprints, recorded=true", got, recorded)
+	}
+
+	// Later runs replace the fingerprints.
+	for _, fingerprints := range [][]string{{"a", "b"}, {"b", "c"}} {
+		err = s.SetResultFingerprints(ctx, queryID, fingerprints)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, _, err = s.ResultFingerprints(ctx, queryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]struct{}{"b": {}, "c": {}}, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Incomplete runs keep the fingerprints of previous runs.
+	err = s.AddResultFingerprints(ctx, queryID, []string{"c", "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err = s.ResultFingerprints(ctx, queryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]struct{}{"b": {}, "c": {}, "d": {}}, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestResultHistory(t *testing.T) {
+	ctx, s := newTestStore(t)
+	_, _, _, userCTX := newTestUser(ctx, t)
+	m, err := s.insertTestMonitor(userCTX, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.EnqueueTriggerQueries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*SearchResult{
+		{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/commit/0123456789abcdef",
+			Commit:     "0123456789abcdef",
+			Message:    "add TODO",
+			Author:     "alice",
+		},
+		{
+			Repository: "github.com/sourcegraph/sourcegraph",
+			URL:        "/github.com/sourcegraph/sourcegraph/-/blob/main.go?L3",
+			Path:       "main.go",
+			LineNumber: 3,
+			Preview:    "// TODO(security): validate input",
+		},
+	}
+	err = s.LogResultHistory(ctx, m.ID, 1, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ResultHistoryForMonitorIDInt64(ctx, m.ID, &graphqlbackend.ListMonitorResultsArgs{First: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*MonitorResult{
+		{Id: 2, Monitor: m.ID, SearchResult: *results[1], CreatedAt: s.Now()},
+		{Id: 1, Monitor: m.ID, SearchResult: *results[0], CreatedAt: s.Now()},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	totalCount, err := s.TotalCountResultHistory(ctx, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCount != 2 {
+		t.Fatalf("got total count %d, want 2", totalCount)
+	}
+}
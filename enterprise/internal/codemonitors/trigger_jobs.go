@@ -2,7 +2,10 @@ package codemonitors
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"time"
 
 	"github.com/graph-gophers/graphql-go"
@@ -50,6 +53,56 @@ func (s *Store) LogSearch(ctx context.Context, queryString string, numResults in
 	return s.Store.Exec(ctx, sqlf.Sprintf(logSearchFmtStr, queryString, numResults > 0, numResults, recordID))
 }
 
+// SearchResult is a summary of a new search result of a trigger query, which
+// actions include in their notifications.
+type SearchResult struct {
+	Repository string `json:"repository"`
+
+	// URL is the URL of the result relative to the external URL.
+	URL string `json:"url"`
+
+	// Commit and Message are set for commit and diff results.
+	Commit  string `json:"commit,omitempty"`
+	Message string `json:"message,omitempty"`
+	Author  string `json:"author,omitempty"`
+
+	// Path is set for file results.
+	Path string `json:"path,omitempty"`
+
+	// LineNumber (1-based) and Preview are set for the matches of content
+	// search triggers. Each matched line is a result.
+	LineNumber int    `json:"lineNumber,omitempty"`
+	Preview    string `json:"preview,omitempty"`
+}
+
+// Fingerprint identifies a match of a content search trigger across runs. It
+// ignores the line number, so that a match is not new if only the lines above
+// it changed.
+func (r *SearchResult) Fingerprint() string {
+	h := sha256.New()
+	for _, s := range []string{r.Repository, r.Path, r.Preview} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+const logSearchResultsFmtStr = `
+UPDATE cm_trigger_jobs
+SET search_results = %s
+WHERE id = %s
+`
+
+// LogSearchResults stores a summary of the new search results of a trigger
+// job, so that its actions can include them in their notifications.
+func (s *Store) LogSearchResults(ctx context.Context, results []*SearchResult, recordID int) error {
+	b, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return s.Store.Exec(ctx, sqlf.Sprintf(logSearchResultsFmtStr, b, recordID))
+}
+
 const deleteObsoleteJobLogsFmtStr = `
 DELETE FROM cm_trigger_jobs
 WHERE results IS NOT TRUE
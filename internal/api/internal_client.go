@@ -51,6 +51,9 @@ type ConfigSavedQuery struct {
 	UserID          *int32  `json:"userID"`
 	OrgID           *int32  `json:"orgID"`
 	SlackWebhookURL *string `json:"slackWebhookURL"`
+	SlackChannel    *string `json:"slackChannel"`
+	NotifyTeams     bool    `json:"notifyTeams,omitempty"`
+	TeamsWebhookURL *string `json:"teamsWebhookURL"`
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -123,6 +126,43 @@ func (c *internalClient) SavedQueriesDeleteInfo(ctx context.Context, query strin
 	return c.postInternal(ctx, "saved-queries/delete-info", query, nil)
 }
 
+// SavedSearchCursor is the position of the query-runner in the results of a
+// saved search. Subscribers of the saved search have been notified of all
+// results up to and including LatestResult.
+type SavedSearchCursor struct {
+	// Key is the key of the saved search.
+	Key string
+
+	// LatestResult is the timestamp of the latest result subscribers were
+	// notified of.
+	LatestResult time.Time
+
+	// SeenResults are the keys of the results at LatestResult subscribers
+	// were notified of. Searching `after:<LatestResult>` includes results at
+	// LatestResult, so these are used to skip them.
+	SeenResults []string
+
+	// SlackThreadTS is the timestamp of the Slack message notifications of
+	// the saved search are threaded under, if any.
+	SlackThreadTS string
+}
+
+// SavedQueriesGetCursor gets the cursor of the saved search with the given
+// key. nil is returned if the saved search has no cursor yet.
+func (c *internalClient) SavedQueriesGetCursor(ctx context.Context, key string) (*SavedSearchCursor, error) {
+	var result *SavedSearchCursor
+	err := c.postInternal(ctx, "saved-queries/get-cursor", key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SavedQueriesSetCursor sets the cursor of the saved search cursor.Key.
+func (c *internalClient) SavedQueriesSetCursor(ctx context.Context, cursor *SavedSearchCursor) error {
+	return c.postInternal(ctx, "saved-queries/set-cursor", cursor, nil)
+}
+
 func (c *internalClient) SettingsGetForSubject(
 	ctx context.Context,
 	subject SettingsSubject,
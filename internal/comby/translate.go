@@ -189,3 +189,26 @@ func StructuralPatToRegexpQuery(pattern string, shortcircuit bool) string {
 	}
 	return "(" + strings.Join(pieces, ")(.|\\s)*?(") + ")"
 }
+
+// LongestLiteral returns the longest run of non-whitespace characters in the
+// literal parts of a comby pattern. Every match of the pattern contains it,
+// so it can be used to cheaply filter out content before running comby. It
+// returns the empty string if the pattern contains no literals.
+//
+// Example:
+// "defer :[x].Close()" -> ".Close()"
+func LongestLiteral(pattern string) string {
+	var longest string
+	for _, term := range parseTemplate([]byte(pattern)) {
+		literal, ok := term.(Literal)
+		if !ok {
+			continue
+		}
+		for _, field := range strings.Fields(literal.String()) {
+			if len(field) > len(longest) {
+				longest = field
+			}
+		}
+	}
+	return longest
+}
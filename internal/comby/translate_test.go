@@ -119,3 +119,22 @@ func TestStructuralPatToRegexpQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestLongestLiteral(t *testing.T) {
+	cases := []struct {
+		Pattern string
+		Want    string
+	}{
+		{Pattern: ":[1]", Want: ""},
+		{Pattern: "defer :[x].Close()", Want: ".Close()"},
+		{Pattern: "if err != nil { :[body] }", Want: "err"},
+		{Pattern: `:[x~[yo]] strconv.Itoa(:[v])`, Want: "strconv.Itoa("},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Pattern, func(t *testing.T) {
+			if got := LongestLiteral(tt.Pattern); got != tt.Want {
+				t.Errorf("got %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package compute
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// Aggregate is the number of matches of an aggregating compute query which
+// share the same value.
+type Aggregate struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Aggregator computes the aggregates of a CountBy or GroupBy query over the file
+// matches added to it. It is safe for concurrent use, so that it can be fed
+// while streaming over search results.
+type Aggregator struct {
+	pattern *regexp.Regexp
+	// key returns the value a match is aggregated by, given the submatch
+	// indices of the match within line and the metadata of its file. ok is
+	// false if the match is not counted.
+	key func(line string, submatches []int, metadata map[string]string) (value string, ok bool)
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func NewAggregator(q Query) (*Aggregator, error) {
+	a := &Aggregator{counts: make(map[string]int)}
+	switch n := q.(type) {
+	case *CountBy:
+		p, ok := n.MatchPattern.(*Regexp)
+		if !ok {
+			return nil, errors.Errorf("unsupported aggregation for %T", n.MatchPattern)
+		}
+		a.pattern = p.Value
+		template := ParseTemplate(n.Template)
+		a.key = func(line string, submatches []int, metadata map[string]string) (string, bool) {
+			return template.Render(regexpEnvironment(line, p.Value, submatches, metadata)), true
+		}
+	case *GroupBy:
+		p, ok := n.MatchPattern.(*Regexp)
+		if !ok {
+			return nil, errors.Errorf("unsupported aggregation for %T", n.MatchPattern)
+		}
+		i, err := captureIndex(p.Value, n.Capture)
+		if err != nil {
+			return nil, err
+		}
+		a.pattern = p.Value
+		a.key = func(line string, submatches []int, _ map[string]string) (string, bool) {
+			start, end := submatches[2*i], submatches[2*i+1]
+			if start == -1 || end == -1 {
+				return "", false
+			}
+			return line[start:end], true
+		}
+	default:
+		return nil, errors.Errorf("%T is not an aggregation", q)
+	}
+	return a, nil
+}
+
+// Add counts the matches of the aggregation pattern in the lines of fm.
+func (a *Aggregator) Add(fm *result.FileMatch) {
+	metadata := map[string]string{
+		"repo":   string(fm.Repo.Name),
+		"path":   fm.Path,
+		"commit": string(fm.CommitID),
+	}
+	var values []string
+	for _, l := range fm.LineMatches {
+		for _, submatches := range a.pattern.FindAllStringSubmatchIndex(l.Preview, -1) {
+			if value, ok := a.key(l.Preview, submatches, metadata); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, value := range values {
+		a.counts[value]++
+	}
+}
+
+// Aggregates returns the aggregates of the matches added so far, ordered by
+// descending count and then by value.
+func (a *Aggregator) Aggregates() []Aggregate {
+	a.mu.Lock()
+	aggregates := make([]Aggregate, 0, len(a.counts))
+	for value, count := range a.counts {
+		aggregates = append(aggregates, Aggregate{Value: value, Count: count})
+	}
+	a.mu.Unlock()
+
+	sort.Slice(aggregates, func(i, j int) bool {
+		if aggregates[i].Count != aggregates[j].Count {
+			return aggregates[i].Count > aggregates[j].Count
+		}
+		return aggregates[i].Value < aggregates[j].Value
+	})
+	return aggregates
+}
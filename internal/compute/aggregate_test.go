@@ -0,0 +1,61 @@
+package compute
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/hexops/autogold"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestAggregator(t *testing.T) {
+	fileMatches := []*result.FileMatch{
+		{
+			LineMatches: []*result.LineMatch{
+				{Preview: `import "github.com/sourcegraph/lib/v2"`},
+				{Preview: `import "github.com/sourcegraph/lib/v3" // import "github.com/sourcegraph/lib/v2"`},
+			},
+		},
+		{
+			LineMatches: []*result.LineMatch{
+				{Preview: `import "github.com/sourcegraph/lib"`},
+				{Preview: `import "github.com/sourcegraph/lib/v2"`},
+			},
+		},
+	}
+	test := func(q Query) string {
+		a, err := NewAggregator(q)
+		if err != nil {
+			return err.Error()
+		}
+		for _, fm := range fileMatches {
+			a.Add(fm)
+		}
+		v, _ := json.Marshal(a.Aggregates())
+		return string(v)
+	}
+
+	pattern := &Regexp{Value: regexp.MustCompile(`github\.com/sourcegraph/(lib)(?:/(?P<version>v\d+))?`)}
+
+	autogold.Want(
+		"count by template",
+		`[{"value":"lib@v2","count":3},{"value":"lib@","count":1},{"value":"lib@v3","count":1}]`).
+		Equal(t, test(&CountBy{MatchPattern: pattern, Template: "$1@$version"}))
+
+	autogold.Want(
+		"group by named capture skips matches without the group",
+		`[{"value":"v2","count":3},{"value":"v3","count":1}]`).
+		Equal(t, test(&GroupBy{MatchPattern: pattern, Capture: "version"}))
+
+	autogold.Want(
+		"group by numbered capture",
+		`[{"value":"lib","count":5}]`).
+		Equal(t, test(&GroupBy{MatchPattern: pattern, Capture: "$1"}))
+
+	autogold.Want(
+		"not an aggregation",
+		"*compute.MatchOnly is not an aggregation").
+		Equal(t, test(&MatchOnly{MatchPattern: pattern}))
+}
@@ -0,0 +1,173 @@
+package compute
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Text kinds of the output of an Output query.
+const (
+	outputKindFile   = "output"
+	outputKindCommit = "output.commit"
+	outputKindDiff   = "output.diff"
+)
+
+// OutputFromFileMatch renders the template of op for every match of its
+// pattern in fm. Regular expressions are matched against the matched lines of
+// fm, structural patterns against the content of the file.
+func OutputFromFileMatch(ctx context.Context, fm *result.FileMatch, op *Output) (*Text, error) {
+	metadata := map[string]string{
+		"repo":   string(fm.Repo.Name),
+		"path":   fm.Path,
+		"commit": string(fm.CommitID),
+	}
+
+	template := ParseTemplate(op.Template)
+	var outputs []string
+	switch p := op.MatchPattern.(type) {
+	case *Regexp:
+		for _, l := range fm.LineMatches {
+			outputs = append(outputs, outputRegexp(l.Preview, p.Value, template, metadata)...)
+		}
+	case *Comby:
+		content, err := git.ReadFile(ctx, fm.Repo.Name, fm.CommitID, fm.Path, 0)
+		if err != nil {
+			return nil, err
+		}
+		outputs, err = outputComby(ctx, content, p.Value, combyMatcher(fm.Path), template, metadata)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported output operation for %T", p)
+	}
+	return outputText(outputs, outputKindFile), nil
+}
+
+// OutputFromCommitMatch renders the template of op for every match of its
+// pattern in the message or diff of cm.
+func OutputFromCommitMatch(ctx context.Context, cm *result.CommitMatch, op *Output) (*Text, error) {
+	metadata := map[string]string{
+		"repo":   string(cm.Repo.Name),
+		"commit": string(cm.Commit.ID),
+		"author": cm.Commit.Author.Name,
+		"email":  cm.Commit.Author.Email,
+		"date":   cm.Commit.Author.Date.Format("2006-01-02"),
+	}
+
+	content, kind := string(cm.Commit.Message), outputKindCommit
+	if cm.DiffPreview != nil {
+		content, kind = cm.DiffPreview.Value, outputKindDiff
+	}
+
+	template := ParseTemplate(op.Template)
+	var outputs []string
+	switch p := op.MatchPattern.(type) {
+	case *Regexp:
+		outputs = outputRegexp(content, p.Value, template, metadata)
+	case *Comby:
+		var err error
+		outputs, err = outputComby(ctx, []byte(content), p.Value, ".generic", template, metadata)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported output operation for %T", p)
+	}
+	return outputText(outputs, kind), nil
+}
+
+// outputText returns the text of the outputs of a search result, or nil if
+// there are none.
+func outputText(outputs []string, kind string) *Text {
+	if len(outputs) == 0 {
+		return nil
+	}
+	return &Text{Value: strings.Join(outputs, "\n"), Kind: kind}
+}
+
+// outputRegexp renders template for every match of rp in content. The
+// environment of a match consists of metadata, the capture groups of rp and
+// $content.
+func outputRegexp(content string, rp *regexp.Regexp, template Template, metadata map[string]string) []string {
+	var outputs []string
+	for _, m := range rp.FindAllStringSubmatchIndex(content, -1) {
+		outputs = append(outputs, template.Render(regexpEnvironment(content, rp, m, metadata)))
+	}
+	return outputs
+}
+
+// regexpEnvironment returns the template environment of the match of rp in
+// content with the submatch indices m.
+func regexpEnvironment(content string, rp *regexp.Regexp, m []int, metadata map[string]string) map[string]string {
+	names := rp.SubexpNames()
+	env := newOutputEnvironment(metadata)
+	env["content"] = content[m[0]:m[1]]
+	for i := 0; 2*i < len(m); i++ {
+		start, end := m[2*i], m[2*i+1]
+		if start == -1 || end == -1 {
+			continue
+		}
+		env[strconv.Itoa(i)] = content[start:end]
+		if names[i] != "" {
+			env[names[i]] = content[start:end]
+		}
+	}
+	return env
+}
+
+// outputComby renders template for every match of the structural pattern in
+// content. The environment of a match consists of metadata, the holes of the
+// pattern and $content.
+func outputComby(ctx context.Context, content []byte, pattern, matcher string, template Template, metadata map[string]string) ([]string, error) {
+	fileMatches, err := comby.Matches(ctx, comby.Args{
+		Input:         comby.FileContent(content),
+		MatchTemplate: pattern,
+		Matcher:       matcher,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var outputs []string
+	for _, fm := range fileMatches {
+		for _, m := range fm.Matches {
+			outputs = append(outputs, template.Render(combyEnvironment(m, metadata)))
+		}
+	}
+	return outputs, nil
+}
+
+func combyEnvironment(m comby.Match, metadata map[string]string) map[string]string {
+	env := newOutputEnvironment(metadata)
+	env["content"] = m.Matched
+	for _, e := range m.Environment {
+		env[e.Variable] = e.Value
+	}
+	return env
+}
+
+func newOutputEnvironment(metadata map[string]string) map[string]string {
+	env := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		env[k] = v
+	}
+	return env
+}
+
+// combyMatcher returns the comby matcher for the file at path, which is
+// selected by its extension.
+func combyMatcher(path string) string {
+	if ext := filepath.Ext(path); ext != "" {
+		return ext
+	}
+	return ".generic"
+}
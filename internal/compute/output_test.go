@@ -0,0 +1,56 @@
+package compute
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold"
+
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
+)
+
+func TestOutputFromCommitMatch(t *testing.T) {
+	commit := &result.CommitMatch{
+		Repo: types.RepoName{Name: "github.com/sourcegraph/sourcegraph"},
+		Commit: gitapi.Commit{
+			ID:      "abc",
+			Author:  gitapi.Signature{Name: "Alice", Email: "alice@example.com", Date: time.Date(2021, 10, 4, 12, 0, 0, 0, time.UTC)},
+			Message: "Fix search (#1)\n\nAlso fix code monitors (#2)",
+		},
+	}
+	test := func(pattern, template string) string {
+		text, err := OutputFromCommitMatch(context.Background(), commit, &Output{
+			MatchPattern: &Regexp{Value: regexp.MustCompile(pattern)},
+			Template:     template,
+		})
+		if err != nil {
+			return err.Error()
+		}
+		if text == nil {
+			return "<nil>"
+		}
+		return text.Kind + ": " + text.Value
+	}
+
+	autogold.Want(
+		"changelog",
+		"output.commit: 2021-10-04 Alice: search (abc)\n2021-10-04 Alice: code monitors (abc)").
+		Equal(t, test(`(?i)fix (?P<what>[^(]+) \(#\d+\)`, "$date $author: $what ($commit)"))
+
+	autogold.Want("no matches", "<nil>").Equal(t, test(`revert`, "$content"))
+}
+
+func TestCombyEnvironment(t *testing.T) {
+	m := comby.Match{
+		Matched:     "fmt.Println(a, b)",
+		Environment: []comby.Environment{{Variable: "args", Value: "a, b"}},
+	}
+	env := combyEnvironment(m, map[string]string{"path": "main.go"})
+	autogold.Want("holes and metadata", "main.go: fmt.Println(a, b) prints a, b").
+		Equal(t, ParseTemplate("$path: $content prints $args").Render(env))
+}
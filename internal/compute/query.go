@@ -3,6 +3,8 @@ package compute
 import (
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 
@@ -17,6 +19,9 @@ type Query interface {
 func (MatchOnly) node()            {}
 func (ReplaceInPlace) node()       {}
 func (ReplaceWithSeparator) node() {}
+func (CountBy) node()              {}
+func (GroupBy) node()              {}
+func (Output) node()               {}
 
 type MatchOnly struct {
 	MatchPattern MatchPattern
@@ -36,6 +41,34 @@ type ReplaceWithSeparator struct {
 	Parameters     []query.Parameter
 }
 
+// CountBy counts the matches of MatchPattern by the value of Template, which
+// may refer to capture groups of MatchPattern as $1 or ${name}, and to the
+// repository and path of matches. See Template.
+type CountBy struct {
+	MatchPattern MatchPattern
+	Template     string
+	Parameters   []query.Parameter
+}
+
+// GroupBy counts the matches of MatchPattern by the value of the capture group
+// Capture, which is the name or number of the group. Matches in which the group
+// does not participate are not counted.
+type GroupBy struct {
+	MatchPattern MatchPattern
+	Capture      string
+	Parameters   []query.Parameter
+}
+
+// Output renders Template for every match of MatchPattern, which may be a
+// regular expression or a structural pattern. Matches are found in the
+// matched lines of files, or in the messages or diffs of commits. See
+// Template for the variables the template may refer to.
+type Output struct {
+	MatchPattern MatchPattern
+	Template     string
+	Parameters   []query.Parameter
+}
+
 func (n MatchOnly) String() string {
 	return fmt.Sprintf("Match only: %s", n.MatchPattern.String())
 }
@@ -48,6 +81,18 @@ func (n ReplaceWithSeparator) String() string {
 	return fmt.Sprintf("Replace with separator: %s -> %s separator: %s", n.MatchPattern.String(), n.ReplacePattern, n.Separator)
 }
 
+func (n CountBy) String() string {
+	return fmt.Sprintf("Count by: %s -> %s", n.MatchPattern.String(), n.Template)
+}
+
+func (n GroupBy) String() string {
+	return fmt.Sprintf("Group by: %s -> %s", n.MatchPattern.String(), n.Capture)
+}
+
+func (n Output) String() string {
+	return fmt.Sprintf("Output: %s -> %s", n.MatchPattern.String(), n.Template)
+}
+
 type MatchPattern interface {
 	pattern()
 	String() string
@@ -108,7 +153,55 @@ func toRegexpPattern(value string) (*regexp.Regexp, error) {
 	return rp, nil
 }
 
-func toComputeQuery(plan query.Plan) (Query, error) {
+// commandPattern matches the command clause a compute query may start with,
+// like `count by $1`, `group by "version"` or `output "$author: $1"`.
+var commandPattern = regexp.MustCompile(`(?i)^\s*(count\s+by|group\s+by|output)\s+("(?:[^"\\]|\\.)*"|\S+)(?:\s+|$)`)
+
+// command is the command clause of a compute query.
+type command struct {
+	kind  string // "count", "group" or "output"
+	value string
+}
+
+// parseCommand splits the command clause off the start of a compute query. It
+// returns a nil command if q does not start with one. The rest is the search
+// query.
+func parseCommand(q string) (_ *command, rest string, err error) {
+	m := commandPattern.FindStringSubmatchIndex(q)
+	if m == nil {
+		return nil, q, nil
+	}
+	kind := strings.ToLower(strings.Fields(q[m[2]:m[3]])[0])
+	value := q[m[4]:m[5]]
+	if strings.HasPrefix(value, `"`) {
+		value, err = strconv.Unquote(value)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "invalid %s value %s", kind, q[m[4]:m[5]])
+		}
+	}
+	return &command{kind: kind, value: value}, q[m[1]:], nil
+}
+
+// captureIndex returns the index of the capture group called name in rp. name
+// is the name or number of the group, optionally written as $name or ${name}.
+func captureIndex(rp *regexp.Regexp, name string) (int, error) {
+	name = strings.TrimPrefix(name, "$")
+	if strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") {
+		name = name[1 : len(name)-1]
+	}
+	if i, err := strconv.Atoi(name); err == nil {
+		if i < 0 || i > rp.NumSubexp() {
+			return 0, errors.Errorf("group by %s: the pattern has no capture group %d", name, i)
+		}
+		return i, nil
+	}
+	if i := rp.SubexpIndex(name); i > 0 {
+		return i, nil
+	}
+	return 0, errors.Errorf("group by %s: the pattern has no capture group named %q", name, name)
+}
+
+func toComputeQuery(plan query.Plan, cmd *command) (Query, error) {
 	if len(plan) != 1 {
 		return nil, errors.New("compute endpoint only supports one search pattern currently ('and' or 'or' operators are not supported yet)")
 	}
@@ -116,17 +209,91 @@ func toComputeQuery(plan query.Plan) (Query, error) {
 	if err != nil {
 		return nil, err
 	}
+	parameters := plan[0].Parameters
+
+	if plan[0].IsStructural() {
+		if cmd == nil || cmd.kind != "output" {
+			return nil, errors.New("compute endpoint only supports structural patterns in output commands")
+		}
+		return &Output{MatchPattern: &Comby{Value: pattern}, Template: cmd.value, Parameters: parameters}, nil
+	}
+
 	rp, err := toRegexpPattern(pattern)
 	if err != nil {
 		return nil, err
 	}
-	return &MatchOnly{MatchPattern: &Regexp{Value: rp}, Parameters: plan[0].Parameters}, nil
+	if cmd == nil {
+		return &MatchOnly{MatchPattern: &Regexp{Value: rp}, Parameters: parameters}, nil
+	}
+	switch cmd.kind {
+	case "count":
+		return &CountBy{MatchPattern: &Regexp{Value: rp}, Template: cmd.value, Parameters: parameters}, nil
+	case "group":
+		if _, err := captureIndex(rp, cmd.value); err != nil {
+			return nil, err
+		}
+		return &GroupBy{MatchPattern: &Regexp{Value: rp}, Capture: cmd.value, Parameters: parameters}, nil
+	default:
+		return &Output{MatchPattern: &Regexp{Value: rp}, Template: cmd.value, Parameters: parameters}, nil
+	}
 }
 
+// Parse parses a compute query. A compute query is a search query, optionally
+// preceded by a command clause: "count by <template>", "group by <capture
+// group>" or "output <template>". Output commands support structural patterns
+// with patterntype:structural.
 func Parse(q string) (Query, error) {
-	plan, err := query.Pipeline(query.Init(q, query.SearchTypeRegex))
+	cmd, rest, err := parseCommand(q)
+	if err != nil {
+		return nil, err
+	}
+	searchType := query.SearchTypeRegex
+	if isStructural(rest) {
+		searchType = query.SearchTypeStructural
+	}
+	plan, err := query.Pipeline(query.Init(rest, searchType))
 	if err != nil {
 		return nil, err
 	}
-	return toComputeQuery(plan)
+	return toComputeQuery(plan, cmd)
+}
+
+// isStructural returns whether the search query q selects structural search
+// with patterntype:structural.
+func isStructural(q string) bool {
+	nodes, err := query.Parse(q, query.SearchTypeLiteral)
+	if err != nil {
+		return false
+	}
+	var structural bool
+	query.VisitField(nodes, query.FieldPatternType, func(value string, _ bool, _ query.Annotation) {
+		structural = strings.EqualFold(value, "structural")
+	})
+	return structural
+}
+
+// SearchQuery returns the search query of the compute query q, which is q
+// without its command clause.
+func SearchQuery(q string) string {
+	_, rest, err := parseCommand(q)
+	if err != nil {
+		return q
+	}
+	return rest
+}
+
+// AggregationSearchQuery returns the search query of the aggregating compute
+// query q. Unless the search query has a "count:" filter, it is extended to
+// search for all results, so that the aggregates aren't computed over the
+// default number of results only.
+func AggregationSearchQuery(q string) string {
+	rest := SearchQuery(q)
+	parsed, err := query.ParseRegexp(rest)
+	if err != nil {
+		return rest
+	}
+	if count, _ := parsed.StringValue(query.FieldCount); count != "" {
+		return rest
+	}
+	return rest + " count:all"
 }
@@ -19,4 +19,23 @@ func TestParse(t *testing.T) {
 	autogold.Want("`content` normalized", "Match only: foo").Equal(t, test("content:'foo'"))
 	autogold.Want("no pattern", "compute endpoint expects nonempty pattern").Equal(t, test("repo:cool"))
 	autogold.Want("unsupported operators", "compute endpoint only supports one search pattern currently ('and' or 'or' operators are not supported yet)").Equal(t, test("a or b"))
+	autogold.Want("count by", "Count by: lib/(v\\d+) -> $1").Equal(t, test(`count by $1 repo:cool lib/(v\d+)`))
+	autogold.Want("count by quoted template", "Count by: lib/(\\w+)/(v\\d+) -> $1 $2").Equal(t, test(`count by "$1 $2" lib/(\w+)/(v\d+)`))
+	autogold.Want("group by", "Group by: lib/(?P<version>v\\d+) -> version").Equal(t, test(`group by version lib/(?P<version>v\d+)`))
+	autogold.Want("group by unknown capture", `group by version: the pattern has no capture group named "version"`).Equal(t, test(`group by version lib/(v\d+)`))
+	autogold.Want("group by without pattern", "compute endpoint expects nonempty pattern").Equal(t, test("group by 1 repo:cool"))
+	autogold.Want("output", "Output: fix (\\w+) -> $date $1").Equal(t, test(`output "$date $1" type:commit fix (\w+)`))
+	autogold.Want("output structural", "Output: fmt.Println(:[args]) -> $args").Equal(t, test(`output $args patterntype:structural fmt.Println(:[args])`))
+	autogold.Want("structural without output", "compute endpoint only supports structural patterns in output commands").Equal(t, test(`patterntype:structural fmt.Println(:[args])`))
+}
+
+func TestSearchQuery(t *testing.T) {
+	autogold.Want("without aggregation", "repo:cool lib/(v\\d+)").Equal(t, SearchQuery(`repo:cool lib/(v\d+)`))
+	autogold.Want("with aggregation", "repo:cool lib/(v\\d+)").Equal(t, SearchQuery(`count by "$1 x" repo:cool lib/(v\d+)`))
+	autogold.Want("with output", "type:commit fix").Equal(t, SearchQuery(`OUTPUT "$author: $content" type:commit fix`))
+}
+
+func TestAggregationSearchQuery(t *testing.T) {
+	autogold.Want("without count", "repo:cool lib/(v\\d+) count:all").Equal(t, AggregationSearchQuery(`count by $1 repo:cool lib/(v\d+)`))
+	autogold.Want("with count", "repo:cool lib/(v\\d+) count:50").Equal(t, AggregationSearchQuery(`count by $1 repo:cool lib/(v\d+) count:50`))
 }
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+// NewRequest returns an http.Request against the streaming compute API for
+// query.
+func NewRequest(baseURL string, query string) (*http.Request, error) {
+	u := baseURL + "/compute/stream?q=" + url.QueryEscape(query)
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	return req, nil
+}
+
+// ComputeStreamDecoder decodes streaming compute events from the frontend
+// service.
+type ComputeStreamDecoder struct {
+	OnProgress   func(*api.Progress)
+	OnResults    func([]EventResult)
+	OnAggregates func(*EventAggregates)
+	OnError      func(*EventError)
+	OnUnknown    func(event, data []byte)
+}
+
+func (rr ComputeStreamDecoder) ReadAll(r io.Reader) error {
+	dec := streamhttp.NewDecoder(r)
+
+	for dec.Scan() {
+		event := dec.Event()
+		data := dec.Data()
+
+		if bytes.Equal(event, []byte("progress")) {
+			if rr.OnProgress == nil {
+				continue
+			}
+			var d api.Progress
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode progress payload: %w", err)
+			}
+			rr.OnProgress(&d)
+		} else if bytes.Equal(event, []byte("results")) {
+			if rr.OnResults == nil {
+				continue
+			}
+			var d []eventResultUnmarshaller
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode results payload: %w", err)
+			}
+			results := make([]EventResult, 0, len(d))
+			for _, e := range d {
+				results = append(results, e.EventResult)
+			}
+			rr.OnResults(results)
+		} else if bytes.Equal(event, []byte("aggregates")) {
+			if rr.OnAggregates == nil {
+				continue
+			}
+			var d EventAggregates
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode aggregates payload: %w", err)
+			}
+			rr.OnAggregates(&d)
+		} else if bytes.Equal(event, []byte("error")) {
+			if rr.OnError == nil {
+				continue
+			}
+			var d EventError
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode error payload: %w", err)
+			}
+			rr.OnError(&d)
+		} else if bytes.Equal(event, []byte("done")) {
+			// Always the last event
+			break
+		} else {
+			if rr.OnUnknown == nil {
+				continue
+			}
+			rr.OnUnknown(event, data)
+		}
+	}
+	return dec.Err()
+}
+
+type eventResultUnmarshaller struct {
+	EventResult
+}
+
+func (r *eventResultUnmarshaller) UnmarshalJSON(b []byte) error {
+	var typeU struct {
+		Type ResultType `json:"type"`
+	}
+
+	if err := json.Unmarshal(b, &typeU); err != nil {
+		return err
+	}
+
+	switch typeU.Type {
+	case MatchContextResultType:
+		r.EventResult = &EventMatchContext{}
+	case TextResultType:
+		r.EventResult = &EventText{}
+	default:
+		return errors.Errorf("unknown ResultType %v", typeU.Type)
+	}
+	return json.Unmarshal(b, r.EventResult)
+}
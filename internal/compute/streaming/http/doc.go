@@ -0,0 +1,4 @@
+// package http contains the streaming HTTP protocol of the compute endpoint.
+// Like the streaming search protocol in internal/search/streaming/http, it is
+// based on Server Sent Events (SSE).
+package http
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/compute"
+)
+
+// EventResult is a result of a compute query, sent in "results" events.
+type EventResult interface {
+	eventResult()
+}
+
+func (EventMatchContext) eventResult() {}
+func (EventText) eventResult()         {}
+
+// EventMatchContext is the result of a compute query which only matches: the
+// matches of its pattern in a file.
+type EventMatchContext struct {
+	// Type is always MatchContextResultType. Included here for marshalling.
+	Type ResultType `json:"type"`
+
+	Repository   string          `json:"repository"`
+	RepositoryID int32           `json:"repositoryID"`
+	Commit       string          `json:"commit,omitempty"`
+	Path         string          `json:"path"`
+	Matches      []compute.Match `json:"matches"`
+}
+
+// EventText is the text an output compute query renders for a file or commit.
+type EventText struct {
+	// Type is always TextResultType. Included here for marshalling.
+	Type ResultType `json:"type"`
+
+	Repository   string `json:"repository"`
+	RepositoryID int32  `json:"repositoryID"`
+	Commit       string `json:"commit,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Kind         string `json:"kind"`
+	Value        string `json:"value"`
+}
+
+// EventAggregates is sent in "aggregates" events. It carries all aggregates
+// computed so far, so every event supersedes the previous one.
+type EventAggregates struct {
+	Aggregates []EventAggregate `json:"aggregates"`
+
+	// LimitHit is true if the search didn't return all results, so the
+	// aggregates may be incomplete.
+	LimitHit bool `json:"limitHit"`
+}
+
+// EventAggregate is the number of matches of an aggregating compute query
+// which share the same value.
+type EventAggregate struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// EventError emulates a JavaScript error with a message property as is
+// returned when a compute query encounters an error.
+type EventError struct {
+	Message string `json:"message"`
+}
+
+type ResultType string
+
+const (
+	MatchContextResultType ResultType = "matchContext"
+	TextResultType         ResultType = "text"
+)
@@ -0,0 +1,94 @@
+package compute
+
+import (
+	"strings"
+)
+
+// Template is a parsed output template. Templates consist of text and
+// variables written as $name or ${name}, which are replaced by their value in
+// an environment when the template is rendered. $$ stands for a literal $.
+// Variables are named after:
+//
+// - the capture groups of a regular expression, like $1 or $version,
+// - the holes of a structural pattern, like $args for :[args],
+// - $content, the value of the whole match, and
+// - metadata of the search result, like $repo, $path, $commit, $author,
+// $email and $date.
+type Template []templateAtom
+
+type templateAtom struct {
+	// value is literal text, or the name of a variable if variable is true.
+	value    string
+	variable bool
+}
+
+// ParseTemplate parses an output template.
+func ParseTemplate(t string) Template {
+	var (
+		atoms   Template
+		literal strings.Builder
+	)
+	flush := func() {
+		if literal.Len() > 0 {
+			atoms = append(atoms, templateAtom{value: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(t); i++ {
+		if t[i] != '$' || i+1 == len(t) {
+			literal.WriteByte(t[i])
+			continue
+		}
+		if t[i+1] == '$' {
+			literal.WriteByte('$')
+			i++
+			continue
+		}
+
+		var name string
+		end := i + 1
+		if t[i+1] == '{' {
+			closing := strings.IndexByte(t[i+2:], '}')
+			if closing == -1 {
+				literal.WriteByte(t[i])
+				continue
+			}
+			name = t[i+2 : i+2+closing]
+			end = i + 2 + closing + 1
+		} else {
+			for end < len(t) && isNameByte(t[end]) {
+				end++
+			}
+			name = t[i+1 : end]
+		}
+		if name == "" {
+			literal.WriteByte(t[i])
+			continue
+		}
+
+		flush()
+		atoms = append(atoms, templateAtom{value: name, variable: true})
+		i = end - 1
+	}
+	flush()
+	return atoms
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// Render renders the template with the variables in env. Variables which are
+// not in env render as the empty string.
+func (t Template) Render(env map[string]string) string {
+	var b strings.Builder
+	for _, atom := range t {
+		if atom.variable {
+			b.WriteString(env[atom.value])
+		} else {
+			b.WriteString(atom.value)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold"
+)
+
+func TestTemplate(t *testing.T) {
+	env := map[string]string{
+		"1":      "v2",
+		"author": "Alice",
+		"repo":   "github.com/sourcegraph/sourcegraph",
+	}
+	test := func(template string) string {
+		return ParseTemplate(template).Render(env)
+	}
+
+	autogold.Want("text only", "no variables").Equal(t, test("no variables"))
+	autogold.Want("variables", "Alice bumped github.com/sourcegraph/sourcegraph to v2").Equal(t, test("$author bumped $repo to $1"))
+	autogold.Want("braced variable", "v2.0").Equal(t, test("${1}.0"))
+	autogold.Want("unknown variable", "[]").Equal(t, test("[$unknown]"))
+	autogold.Want("escaped dollar", "$1 costs $v2").Equal(t, test("$$1 costs $$$1"))
+	autogold.Want("lone dollars", "$ ${ $").Equal(t, test("$ ${ $"))
+}
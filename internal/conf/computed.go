@@ -411,6 +411,17 @@ func GitMaxConcurrentClones() int {
 	return v
 }
 
+// GitReplicationFactor returns the number of gitservers each repository is
+// stored on. If not set, it returns 1, which means repositories are not
+// replicated.
+func GitReplicationFactor() int {
+	v := Get().GitReplicationFactor
+	if v < 1 {
+		return 1
+	}
+	return v
+}
+
 func UserReposMaxPerUser() int {
 	v := Get().UserReposMaxPerUser
 	if v == 0 {
@@ -0,0 +1,91 @@
+package reposource
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type GoModule struct {
+	// Path is the module path, like "golang.org/x/net".
+	Path string
+}
+
+// ParseGoModuleFromRepoURL returns a parsed Go module from the provided URL
+// path, without a leading `/`, like "go/golang.org/x/net".
+func ParseGoModuleFromRepoURL(urlPath string) (GoModule, error) {
+	modulePath := strings.TrimPrefix(urlPath, "go/")
+	if err := module.CheckPath(modulePath); err != nil {
+		return GoModule{}, fmt.Errorf("failed to parse a Go module from the path %s: %s", urlPath, err)
+	}
+	return GoModule{Path: modulePath}, nil
+}
+
+func (m GoModule) PackageSyntax() string {
+	return m.Path
+}
+
+func (m GoModule) RepoName() api.RepoName {
+	return api.RepoName("go/" + m.Path)
+}
+
+func (m GoModule) CloneURL() string {
+	cloneURL := url.URL{Path: string(m.RepoName())}
+	return cloneURL.String()
+}
+
+// Contains returns true if the package with the given import path is part of
+// the module, which is the case if the module path is a path prefix of the
+// import path. Packages in nested modules are contained in their parent
+// modules, too.
+func (m GoModule) Contains(importPath string) bool {
+	return importPath == m.Path || strings.HasPrefix(importPath, m.Path+"/")
+}
+
+type GoDependency struct {
+	GoModule
+	Version string
+}
+
+// ParseGoDependency parses a dependency string in the format of the go
+// command (module path and version separated by @, like
+// "golang.org/x/net@v0.0.0-20211019232329-c6ed85c7a12d") into a GoDependency.
+func ParseGoDependency(dependency string) (GoDependency, error) {
+	i := strings.LastIndex(dependency, "@")
+	if i < 0 {
+		return GoDependency{}, fmt.Errorf("dependency %q must be of the form module@version", dependency)
+	}
+	modulePath, version := dependency[:i], dependency[i+1:]
+	if err := module.Check(modulePath, version); err != nil {
+		return GoDependency{}, err
+	}
+	return GoDependency{GoModule: GoModule{Path: modulePath}, Version: version}, nil
+}
+
+func (d GoDependency) PackageManagerSyntax() string {
+	return d.Path + "@" + d.Version
+}
+
+// GitTagFromVersion returns the version, which is already a valid git tag
+// with the conventional "v" prefix.
+func (d GoDependency) GitTagFromVersion() string {
+	return d.Version
+}
+
+// SortGoDependencies sorts the dependencies by the semantic version in
+// descending order. The latest version of a dependency becomes the first
+// element of the slice.
+func SortGoDependencies(dependencies []GoDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].GoModule == dependencies[j].GoModule {
+			return semver.Compare(dependencies[i].Version, dependencies[j].Version) > 0
+		}
+		return dependencies[i].Path > dependencies[j].Path
+	})
+}
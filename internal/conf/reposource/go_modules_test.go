@@ -0,0 +1,62 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseGoDependency(t *testing.T) {
+	dependency, err := ParseGoDependency("github.com/gorilla/mux@v1.8.0")
+	assert.Nil(t, err)
+	assert.Equal(t, GoDependency{GoModule{Path: "github.com/gorilla/mux"}, "v1.8.0"}, dependency)
+	assert.Equal(t, "github.com/gorilla/mux@v1.8.0", dependency.PackageManagerSyntax())
+	assert.Equal(t, "v1.8.0", dependency.GitTagFromVersion())
+	assert.Equal(t, api.RepoName("go/github.com/gorilla/mux"), dependency.RepoName())
+
+	for _, invalid := range []string{
+		"github.com/gorilla/mux",
+		"github.com/gorilla/mux@1.8.0",
+		"github.com/gorilla/mux@v2.0.0",
+		"-invalid@v1.0.0",
+	} {
+		_, err := ParseGoDependency(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestParseGoModuleFromRepoURL(t *testing.T) {
+	obtained, err := ParseGoModuleFromRepoURL("go/rsc.io/quote/v3")
+	assert.Nil(t, err)
+	assert.Equal(t, GoModule{Path: "rsc.io/quote/v3"}, obtained)
+	assert.True(t, obtained.Contains("rsc.io/quote/v3/internal"))
+	assert.False(t, obtained.Contains("rsc.io/quote/v33"))
+}
+
+func TestSortGoDependencies(t *testing.T) {
+	parse := func(dependency string) GoDependency {
+		d, err := ParseGoDependency(dependency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	dependencies := []GoDependency{
+		parse("a.com/x@v1.2.0"),
+		parse("b.com/x@v1.2.0"),
+		parse("b.com/x@v1.11.0"),
+		parse("b.com/x@v1.2.0-rc.1"),
+		parse("b.com/x@v0.0.0-20211019232329-c6ed85c7a12d"),
+	}
+	expected := []GoDependency{
+		parse("b.com/x@v1.11.0"),
+		parse("b.com/x@v1.2.0"),
+		parse("b.com/x@v1.2.0-rc.1"),
+		parse("b.com/x@v0.0.0-20211019232329-c6ed85c7a12d"),
+		parse("a.com/x@v1.2.0"),
+	}
+	SortGoDependencies(dependencies)
+	assert.Equal(t, expected, dependencies)
+}
@@ -76,6 +76,14 @@ func (d MavenDependency) CoursierSyntax() string {
 	return fmt.Sprintf("%s:%s:%s", d.MavenModule.GroupID, d.MavenModule.ArtifactID, d.Version)
 }
 
+func (d MavenDependency) PackageSyntax() string {
+	return d.MavenModule.CoursierSyntax()
+}
+
+func (d MavenDependency) PackageManagerSyntax() string {
+	return d.CoursierSyntax()
+}
+
 func (d MavenDependency) GitTagFromVersion() string {
 	return "v" + d.Version
 }
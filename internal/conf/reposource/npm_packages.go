@@ -0,0 +1,110 @@
+package reposource
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// npmPackageNamePattern matches npm package names with an optional scope, like
+// "lodash" or "@types/node". See
+// https://docs.npmjs.com/cli/v8/configuring-npm/package-json#name
+var npmPackageNamePattern = lazyregexp.New(`^(?:@([a-z0-9~-][a-z0-9._~-]*)/)?([a-z0-9~-][a-z0-9._~-]*)$`)
+
+type NpmPackage struct {
+	// Scope is the scope of the package without the leading @, or empty for
+	// unscoped packages.
+	Scope string
+	Name  string
+}
+
+// ParseNpmPackage parses a package name like "lodash" or "@types/node" into
+// an NpmPackage.
+func ParseNpmPackage(name string) (NpmPackage, error) {
+	match := npmPackageNamePattern.FindStringSubmatch(name)
+	if match == nil {
+		return NpmPackage{}, fmt.Errorf("invalid npm package name %q", name)
+	}
+	return NpmPackage{Scope: match[1], Name: match[2]}, nil
+}
+
+// ParseNpmPackageFromRepoURL returns a parsed npm package from the provided
+// URL path, without a leading `/`, like "npm/lodash" or "npm/types/node".
+func ParseNpmPackageFromRepoURL(urlPath string) (NpmPackage, error) {
+	parts := strings.Split(strings.TrimPrefix(urlPath, "npm/"), "/")
+	switch len(parts) {
+	case 1:
+		return ParseNpmPackage(parts[0])
+	case 2:
+		return ParseNpmPackage("@" + parts[0] + "/" + parts[1])
+	default:
+		return NpmPackage{}, fmt.Errorf("failed to parse an npm package from the path %s", urlPath)
+	}
+}
+
+// PackageSyntax returns the name of the package as it is used in a
+// package.json file, like "@types/node".
+func (p NpmPackage) PackageSyntax() string {
+	if p.Scope == "" {
+		return p.Name
+	}
+	return fmt.Sprintf("@%s/%s", p.Scope, p.Name)
+}
+
+func (p NpmPackage) RepoName() api.RepoName {
+	if p.Scope == "" {
+		return api.RepoName("npm/" + p.Name)
+	}
+	return api.RepoName(fmt.Sprintf("npm/%s/%s", p.Scope, p.Name))
+}
+
+func (p NpmPackage) CloneURL() string {
+	cloneURL := url.URL{Path: string(p.RepoName())}
+	return cloneURL.String()
+}
+
+type NpmDependency struct {
+	NpmPackage
+	Version string
+}
+
+// ParseNpmDependency parses a dependency string in the npm format (package
+// name and version separated by @, like "@types/node@16.11.7") into an
+// NpmDependency.
+func ParseNpmDependency(dependency string) (NpmDependency, error) {
+	// The scope of scoped packages starts with @, so the version is separated
+	// by the last @.
+	i := strings.LastIndex(dependency, "@")
+	if i <= 0 || i == len(dependency)-1 {
+		return NpmDependency{}, fmt.Errorf("dependency %q must be of the form (@scope/)?packageName@version", dependency)
+	}
+	pkg, err := ParseNpmPackage(dependency[:i])
+	if err != nil {
+		return NpmDependency{}, err
+	}
+	return NpmDependency{NpmPackage: pkg, Version: dependency[i+1:]}, nil
+}
+
+func (d NpmDependency) PackageManagerSyntax() string {
+	return fmt.Sprintf("%s@%s", d.PackageSyntax(), d.Version)
+}
+
+func (d NpmDependency) GitTagFromVersion() string {
+	return "v" + d.Version
+}
+
+// SortNpmDependencies sorts the dependencies by the semantic version in
+// descending order. The latest version of a dependency becomes the first
+// element of the slice.
+func SortNpmDependencies(dependencies []NpmDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].NpmPackage == dependencies[j].NpmPackage {
+			return versionGreaterThan(dependencies[i].Version, dependencies[j].Version)
+		}
+		return dependencies[i].PackageSyntax() > dependencies[j].PackageSyntax()
+	})
+}
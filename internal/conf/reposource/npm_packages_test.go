@@ -0,0 +1,77 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseNpmDependency(t *testing.T) {
+	tests := []struct {
+		dependency string
+		want       NpmDependency
+		wantErr    bool
+	}{
+		{dependency: "lodash@4.17.21", want: NpmDependency{NpmPackage{Name: "lodash"}, "4.17.21"}},
+		{dependency: "@types/node@16.11.7", want: NpmDependency{NpmPackage{Scope: "types", Name: "node"}, "16.11.7"}},
+		{dependency: "lodash", wantErr: true},
+		{dependency: "@types/node", wantErr: true},
+		{dependency: "lodash@", wantErr: true},
+		{dependency: "Lodash@1.0.0", wantErr: true},
+		{dependency: "@types/node/extra@1.0.0", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseNpmDependency(test.dependency)
+		if test.wantErr {
+			assert.NotNil(t, err, test.dependency)
+			continue
+		}
+		assert.Nil(t, err, test.dependency)
+		assert.Equal(t, test.want, got)
+		assert.Equal(t, test.dependency, got.PackageManagerSyntax())
+	}
+}
+
+func TestParseNpmPackageFromRepoURL(t *testing.T) {
+	obtained, err := ParseNpmPackageFromRepoURL("npm/types/node")
+	assert.Nil(t, err)
+	assert.Equal(t, NpmPackage{Scope: "types", Name: "node"}, obtained)
+	assert.Equal(t, "@types/node", obtained.PackageSyntax())
+	assert.Equal(t, api.RepoName("npm/types/node"), obtained.RepoName())
+
+	obtained, err = ParseNpmPackageFromRepoURL("npm/lodash")
+	assert.Nil(t, err)
+	assert.Equal(t, NpmPackage{Name: "lodash"}, obtained)
+	assert.Equal(t, api.RepoName("npm/lodash"), obtained.RepoName())
+
+	_, err = ParseNpmPackageFromRepoURL("npm/a/b/c")
+	assert.NotNil(t, err)
+}
+
+func TestSortNpmDependencies(t *testing.T) {
+	parse := func(dependency string) NpmDependency {
+		d, err := ParseNpmDependency(dependency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	dependencies := []NpmDependency{
+		parse("a@1.2.0"),
+		parse("b@1.11.0"),
+		parse("b@1.2.0"),
+		parse("@a/b@1.0.0"),
+		parse("b@1.2.0-rc.1"),
+	}
+	expected := []NpmDependency{
+		parse("b@1.11.0"),
+		parse("b@1.2.0"),
+		parse("b@1.2.0-rc.1"),
+		parse("a@1.2.0"),
+		parse("@a/b@1.0.0"),
+	}
+	SortNpmDependencies(dependencies)
+	assert.Equal(t, expected, dependencies)
+}
@@ -0,0 +1,23 @@
+package reposource
+
+// PackageDependency is a version of a package from a package manager. Package
+// repositories are synthesized git repositories with one tag per version of
+// the package.
+type PackageDependency interface {
+	// PackageSyntax returns the name of the package, without the version.
+	PackageSyntax() string
+
+	// PackageManagerSyntax returns the name and version of the package in the
+	// syntax of its package manager.
+	PackageManagerSyntax() string
+
+	// GitTagFromVersion returns the git tag of the version of the package.
+	GitTagFromVersion() string
+}
+
+var (
+	_ PackageDependency = MavenDependency{}
+	_ PackageDependency = NpmDependency{}
+	_ PackageDependency = GoDependency{}
+	_ PackageDependency = PythonDependency{}
+)
@@ -0,0 +1,103 @@
+package reposource
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// pythonPackageNamePattern matches valid Python package names. See
+// https://www.python.org/dev/peps/pep-0508/#names
+var pythonPackageNamePattern = lazyregexp.New(`^(?i:[a-z0-9]|[a-z0-9][a-z0-9._-]*[a-z0-9])$`)
+
+// pythonPackageNameSeparators matches the runs of separators that are
+// replaced when normalizing package names.
+var pythonPackageNameSeparators = lazyregexp.New(`[-_.]+`)
+
+type PythonPackage struct {
+	// Name is the normalized name of the package, like "python-dateutil".
+	Name string
+}
+
+// ParsePythonPackage parses a package name like "requests" or
+// "Python_Dateutil" into a PythonPackage with a normalized name.
+func ParsePythonPackage(name string) (PythonPackage, error) {
+	if !pythonPackageNamePattern.MatchString(name) {
+		return PythonPackage{}, fmt.Errorf("invalid Python package name %q", name)
+	}
+	return PythonPackage{Name: NormalizePythonPackageName(name)}, nil
+}
+
+// NormalizePythonPackageName returns the normalized form of a package name,
+// which is the same for all spellings of the name that package indexes
+// consider equal. See https://www.python.org/dev/peps/pep-0503/#normalized-names
+func NormalizePythonPackageName(name string) string {
+	return strings.ToLower(pythonPackageNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// ParsePythonPackageFromRepoURL returns a parsed Python package from the
+// provided URL path, without a leading `/`, like "python/requests".
+func ParsePythonPackageFromRepoURL(urlPath string) (PythonPackage, error) {
+	pkg, err := ParsePythonPackage(strings.TrimPrefix(urlPath, "python/"))
+	if err != nil {
+		return PythonPackage{}, fmt.Errorf("failed to parse a Python package from the path %s: %s", urlPath, err)
+	}
+	return pkg, nil
+}
+
+func (p PythonPackage) PackageSyntax() string {
+	return p.Name
+}
+
+func (p PythonPackage) RepoName() api.RepoName {
+	return api.RepoName("python/" + p.Name)
+}
+
+func (p PythonPackage) CloneURL() string {
+	cloneURL := url.URL{Path: string(p.RepoName())}
+	return cloneURL.String()
+}
+
+type PythonDependency struct {
+	PythonPackage
+	Version string
+}
+
+// ParsePythonDependency parses a dependency string in the format of pip
+// requirements pinned to a version (package name and version separated by
+// ==, like "requests==2.26.0") into a PythonDependency.
+func ParsePythonDependency(dependency string) (PythonDependency, error) {
+	parts := strings.Split(dependency, "==")
+	if len(parts) != 2 || parts[1] == "" {
+		return PythonDependency{}, fmt.Errorf("dependency %q must be of the form packageName==version", dependency)
+	}
+	pkg, err := ParsePythonPackage(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return PythonDependency{}, err
+	}
+	return PythonDependency{PythonPackage: pkg, Version: strings.TrimSpace(parts[1])}, nil
+}
+
+func (d PythonDependency) PackageManagerSyntax() string {
+	return d.Name + "==" + d.Version
+}
+
+func (d PythonDependency) GitTagFromVersion() string {
+	return "v" + d.Version
+}
+
+// SortPythonDependencies sorts the dependencies by version in descending
+// order. The latest version of a dependency becomes the first element of the
+// slice.
+func SortPythonDependencies(dependencies []PythonDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].PythonPackage == dependencies[j].PythonPackage {
+			return versionGreaterThan(dependencies[i].Version, dependencies[j].Version)
+		}
+		return dependencies[i].Name > dependencies[j].Name
+	})
+}
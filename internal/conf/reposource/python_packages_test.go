@@ -0,0 +1,75 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParsePythonDependency(t *testing.T) {
+	tests := []struct {
+		dependency string
+		want       PythonDependency
+		wantErr    bool
+	}{
+		{dependency: "requests==2.26.0", want: PythonDependency{PythonPackage{Name: "requests"}, "2.26.0"}},
+		{dependency: "Python_Dateutil==2.8.2", want: PythonDependency{PythonPackage{Name: "python-dateutil"}, "2.8.2"}},
+		{dependency: "zope.interface==5.4.0", want: PythonDependency{PythonPackage{Name: "zope-interface"}, "5.4.0"}},
+		{dependency: "requests", wantErr: true},
+		{dependency: "requests==", wantErr: true},
+		{dependency: "requests>=2.26.0", wantErr: true},
+		{dependency: "-requests==2.26.0", wantErr: true},
+		{dependency: "requests==2.26.0==2.27.0", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParsePythonDependency(test.dependency)
+		if test.wantErr {
+			assert.NotNil(t, err, test.dependency)
+			continue
+		}
+		assert.Nil(t, err, test.dependency)
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestNormalizePythonPackageName(t *testing.T) {
+	for _, name := range []string{"friendly-bard", "Friendly-Bard", "FRIENDLY-BARD", "friendly.bard", "friendly_bard", "friendly--bard", "FrIeNdLy-._.-bArD"} {
+		assert.Equal(t, "friendly-bard", NormalizePythonPackageName(name), name)
+	}
+}
+
+func TestParsePythonPackageFromRepoURL(t *testing.T) {
+	obtained, err := ParsePythonPackageFromRepoURL("python/python-dateutil")
+	assert.Nil(t, err)
+	assert.Equal(t, PythonPackage{Name: "python-dateutil"}, obtained)
+	assert.Equal(t, api.RepoName("python/python-dateutil"), obtained.RepoName())
+
+	_, err = ParsePythonPackageFromRepoURL("python/a/b")
+	assert.NotNil(t, err)
+}
+
+func TestSortPythonDependencies(t *testing.T) {
+	parse := func(dependency string) PythonDependency {
+		d, err := ParsePythonDependency(dependency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	dependencies := []PythonDependency{
+		parse("a==1.2.0"),
+		parse("b==1.11.0"),
+		parse("b==1.2.0"),
+		parse("b==1.2.0rc1"),
+	}
+	expected := []PythonDependency{
+		parse("b==1.11.0"),
+		parse("b==1.2.0"),
+		parse("b==1.2.0rc1"),
+		parse("a==1.2.0"),
+	}
+	SortPythonDependencies(dependencies)
+	assert.Equal(t, expected, dependencies)
+}
@@ -126,9 +126,13 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	extsvc.KindGoModules:       {CodeHost: true, JSONSchema: schema.GoModulesSchemaJSON},
 	extsvc.KindJVMPackages:     {CodeHost: true, JSONSchema: schema.JVMPackagesSchemaJSON},
+	extsvc.KindMercurial:       {CodeHost: true, JSONSchema: schema.MercurialSchemaJSON},
+	extsvc.KindNpmPackages:     {CodeHost: true, JSONSchema: schema.NpmPackagesSchemaJSON},
 	extsvc.KindPerforce:        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 	extsvc.KindPhabricator:     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
+	extsvc.KindPythonPackages:  {CodeHost: true, JSONSchema: schema.PythonPackagesSchemaJSON},
 	extsvc.KindOther:           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
 
@@ -335,6 +339,13 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 			return nil, err
 		}
 		err = validateOtherExternalServiceConnection(&c)
+
+	case extsvc.KindMercurial:
+		var c schema.MercurialConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = validateMercurialConnection(&c)
 	}
 
 	return normalized, multierror.Append(errs, err).ErrorOrNil()
@@ -369,6 +380,32 @@ func validateOtherExternalServiceConnection(c *schema.OtherExternalServiceConnec
 	return nil
 }
 
+// validateMercurialConnection validates that the repos of a Mercurial
+// connection resolve to clone URLs that Mercurial can pull from.
+func validateMercurialConnection(c *schema.MercurialConnection) error {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		// The invalid URL is already reported by the JSON Schema validation.
+		return nil
+	}
+
+	for i, repo := range c.Repos {
+		cloneURL, err := baseURL.Parse(repo)
+		if err != nil {
+			return errors.Errorf(`repos.%d: %s`, i, err)
+		}
+
+		switch cloneURL.Scheme {
+		case "http", "https", "ssh":
+			continue
+		default:
+			return errors.Errorf("repos.%d: scheme %q not one of http, https or ssh", i, cloneURL.Scheme)
+		}
+	}
+
+	return nil
+}
+
 func (e *ExternalServiceStore) validateGitHubConnection(ctx context.Context, id int64, c *schema.GitHubConnection) error {
 	err := new(multierror.Error)
 	for _, validate := range e.GitHubValidators {
@@ -135,6 +135,18 @@ func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 			config:  `{"url": "https://bitbucket.org", "username": "ceo", "appPassword": "abc"}`,
 			wantErr: "<nil>",
 		},
+		{
+			name:    "0 errors - Mercurial",
+			kind:    extsvc.KindMercurial,
+			config:  `{"url": "https://hg.example.org", "repos": ["repo1", "ssh://hg@hg.example.org/repo2"]}`,
+			wantErr: "<nil>",
+		},
+		{
+			name:    "Mercurial repo with unsupported scheme",
+			kind:    extsvc.KindMercurial,
+			config:  `{"url": "https://hg.example.org", "repos": ["git://hg.example.org/repo1"]}`,
+			wantErr: "1 error occurred:\n\t* repos.0: scheme \"git\" not one of http, https or ssh\n\n",
+		},
 		{
 			name:    "1 error",
 			kind:    extsvc.KindGitHub,
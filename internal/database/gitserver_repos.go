@@ -294,6 +294,75 @@ SET (last_fetched, last_changed, shard_id, updated_at) =
 	return errors.Wrap(err, "setting last fetched")
 }
 
+// UpsertReplicas adds or updates rows representing the clone status of repos
+// on the gitservers they are replicated to.
+func (s *GitserverRepoStore) UpsertReplicas(ctx context.Context, replicas ...*types.GitserverRepoReplica) error {
+	values := make([]*sqlf.Query, 0, len(replicas))
+	for _, r := range replicas {
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, now())", r.RepoID, r.ShardID, r.CloneStatus))
+	}
+
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.UpsertReplicas
+INSERT INTO
+    gitserver_repo_replicas(repo_id, shard_id, clone_status, updated_at)
+    VALUES %s
+    ON CONFLICT (repo_id, shard_id) DO UPDATE
+    SET (clone_status, updated_at) = (EXCLUDED.clone_status, now())
+    WHERE gitserver_repo_replicas.clone_status IS DISTINCT FROM EXCLUDED.clone_status
+`, sqlf.Join(values, ",")))
+
+	return errors.Wrap(err, "upserting GitserverRepoReplicas")
+}
+
+// SetReplicaCloneStatus will attempt to update the clone status of a repo on
+// the given gitserver replica. If a matching row does not yet exist a new one
+// will be created. If the status value hasn't changed, the row will not be
+// updated.
+func (s *GitserverRepoStore) SetReplicaCloneStatus(ctx context.Context, name api.RepoName, status types.CloneStatus, shardID string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.SetReplicaCloneStatus
+INSERT INTO gitserver_repo_replicas(repo_id, shard_id, clone_status, updated_at)
+SELECT id, %s, %s, now()
+FROM repo
+WHERE name = %s
+ON CONFLICT (repo_id, shard_id) DO UPDATE
+SET (clone_status, updated_at) = (EXCLUDED.clone_status, now())
+    WHERE gitserver_repo_replicas.clone_status IS DISTINCT FROM EXCLUDED.clone_status
+`, shardID, status, name))
+
+	return errors.Wrap(err, "setting replica clone status")
+}
+
+// ListReplicas returns the clone status of a repo on each gitserver it is
+// replicated to, ordered by the gitserver hostname.
+func (s *GitserverRepoStore) ListReplicas(ctx context.Context, id api.RepoID) ([]*types.GitserverRepoReplica, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.ListReplicas
+SELECT repo_id, shard_id, clone_status, updated_at
+FROM gitserver_repo_replicas
+WHERE repo_id = %s
+ORDER BY shard_id
+`, id))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing GitserverRepoReplicas")
+	}
+	defer rows.Close()
+
+	var replicas []*types.GitserverRepoReplica
+	for rows.Next() {
+		var r types.GitserverRepoReplica
+		var cloneStatus string
+		if err := rows.Scan(&r.RepoID, &r.ShardID, &cloneStatus, &r.UpdatedAt); err != nil {
+			return nil, errors.Wrap(err, "scanning GitserverRepoReplica")
+		}
+		r.CloneStatus = types.ParseCloneStatus(cloneStatus)
+		replicas = append(replicas, &r)
+	}
+
+	return replicas, errors.Wrap(rows.Err(), "iterating rows")
+}
+
 // sanitizeToUTF8 will remove any null character terminated string. The null character can be
 // represented in one of the following ways in Go:
 //
@@ -468,6 +468,60 @@ func TestGitserverRepoUpsert(t *testing.T) {
 	}
 }
 
+func TestGitserverRepoReplicas(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	repo1 := &types.Repo{
+		Name:         "github.com/sourcegraph/repo1",
+		URI:          "github.com/sourcegraph/repo1",
+		ExternalRepo: api.ExternalRepoSpec{},
+	}
+
+	// Create one test repo
+	err := Repos(db).Create(ctx, repo1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting the clone status creates the row of the replica
+	err = GitserverRepos(db).SetReplicaCloneStatus(ctx, repo1.Name, types.CloneStatusCloning, "gitserver-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Upserting adds new replicas and updates existing ones
+	err = GitserverRepos(db).UpsertReplicas(ctx,
+		&types.GitserverRepoReplica{RepoID: repo1.ID, ShardID: "gitserver-2", CloneStatus: types.CloneStatusNotCloned},
+		&types.GitserverRepoReplica{RepoID: repo1.ID, ShardID: "gitserver-1", CloneStatus: types.CloneStatusCloned},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromDB, err := GitserverRepos(db).ListReplicas(ctx, repo1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*types.GitserverRepoReplica{
+		{RepoID: repo1.ID, ShardID: "gitserver-1", CloneStatus: types.CloneStatusCloned},
+		{RepoID: repo1.ID, ShardID: "gitserver-2", CloneStatus: types.CloneStatusNotCloned},
+	}
+	if diff := cmp.Diff(want, fromDB, cmpopts.IgnoreFields(types.GitserverRepoReplica{}, "UpdatedAt")); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// The replicas don't affect the status of the repo in gitserver_repos
+	if _, err := GitserverRepos(db).GetByID(ctx, repo1.ID); err == nil {
+		t.Fatal("expected no GitserverRepo")
+	}
+}
+
 func TestSanitizeToUTF8(t *testing.T) {
 	testSet := map[string]string{
 		"test\x00":     "test",
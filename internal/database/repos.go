@@ -35,9 +35,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/jvmpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/phabricator"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -473,10 +476,16 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(perforce.Depot)
 	case extsvc.TypePhabricator:
 		r.Metadata = new(phabricator.Repo)
-	case extsvc.TypeOther:
+	case extsvc.TypeOther, extsvc.TypeMercurial:
 		r.Metadata = new(extsvc.OtherRepoMetadata)
 	case extsvc.TypeJVMPackages:
 		r.Metadata = new(jvmpackages.Metadata)
+	case extsvc.TypeNpmPackages:
+		r.Metadata = new(npmpackages.Metadata)
+	case extsvc.TypeGoModules:
+		r.Metadata = new(gomodules.Metadata)
+	case extsvc.TypePythonPackages:
+		r.Metadata = new(pythonpackages.Metadata)
 	default:
 		log15.Warn("scanRepo - unknown service type", "typ", typ)
 		return nil
@@ -580,6 +589,16 @@ type ReposListOptions struct {
 	// OnlyPrivate excludes non-private repositories from the list.
 	OnlyPrivate bool
 
+	// DescriptionPatterns is a list of regular expressions, all of which must
+	// match the description of all repositories returned in the list. The
+	// patterns are matched case insensitively.
+	DescriptionPatterns []string
+
+	// Topics is a list of code host topics, all of which must be set on all
+	// repositories returned in the list. Only GitHub and GitLab repositories
+	// have topics.
+	Topics []string
+
 	// Index when set will only include repositories which should be indexed
 	// if true. If false it will exclude repositories which should be
 	// indexed. An example use case of this is for indexed search only
@@ -928,6 +947,12 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 	if opt.OnlyPrivate {
 		where = append(where, sqlf.Sprintf("private"))
 	}
+	for _, pattern := range opt.DescriptionPatterns {
+		where = append(where, sqlf.Sprintf("repo.description ~* %s", pattern))
+	}
+	for _, topic := range opt.Topics {
+		where = append(where, repoTopicCond(topic))
+	}
 
 	if len(opt.Names) > 0 {
 		lowerNames := make([]string, len(opt.Names))
@@ -1554,6 +1579,24 @@ LIMIT 1
 	return api.RepoName(name), nil
 }
 
+// repoTopicCond returns a condition matching repositories tagged with topic
+// on their code host. Topics are read from the code host metadata of the
+// repository, whose shape depends on the code host. GitHub topics are always
+// lowercase, GitLab topics are compared case insensitively.
+func repoTopicCond(topic string) *sqlf.Query {
+	topic = strings.ToLower(topic)
+	return sqlf.Sprintf(`(
+	(repo.external_service_type = %s AND repo.metadata->'RepositoryTopics'->'Nodes' @> jsonb_build_array(jsonb_build_object('Topic', jsonb_build_object('Name', %s::text))))
+	OR (repo.external_service_type = %s AND EXISTS (
+		SELECT 1 FROM jsonb_array_elements_text(COALESCE(repo.metadata->'topics', repo.metadata->'tag_list')) AS t(name)
+		WHERE lower(t.name) = %s
+	))
+)`,
+		extsvc.TypeGitHub, topic,
+		extsvc.TypeGitLab, topic,
+	)
+}
+
 func parsePattern(p string) ([]*sqlf.Query, error) {
 	exact, like, pattern, err := parseIncludePattern(p)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -108,3 +109,60 @@ func (s *QueryRunnerStateStore) Delete(ctx context.Context, query string) error
 	)
 	return err
 }
+
+// SavedSearchCursor is the position of the query-runner in the results of a
+// saved search.
+type SavedSearchCursor struct {
+	SavedSearchID int32
+	LatestResult  time.Time
+	SeenResults   []string
+	SlackThreadTS string
+}
+
+// GetCursor gets the cursor of the given saved search. nil is returned if
+// there is no cursor for the saved search yet.
+func (s *QueryRunnerStateStore) GetCursor(ctx context.Context, savedSearchID int32) (*SavedSearchCursor, error) {
+	cursor := &SavedSearchCursor{
+		SavedSearchID: savedSearchID,
+	}
+	var slackThreadTS sql.NullString
+	err := s.Handle().DB().QueryRowContext(
+		ctx,
+		"SELECT latest_result, seen_results, slack_thread_ts FROM saved_search_cursors WHERE saved_search_id=$1",
+		savedSearchID,
+	).Scan(&cursor.LatestResult, pq.Array(&cursor.SeenResults), &slackThreadTS)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "QueryRow")
+	}
+	cursor.SlackThreadTS = slackThreadTS.String
+	return cursor, nil
+}
+
+// SetCursor sets the cursor of the saved search cursor.SavedSearchID.
+func (s *QueryRunnerStateStore) SetCursor(ctx context.Context, cursor *SavedSearchCursor) error {
+	seenResults := cursor.SeenResults
+	if seenResults == nil {
+		seenResults = []string{}
+	}
+	_, err := s.Handle().DB().ExecContext(
+		ctx,
+		`INSERT INTO saved_search_cursors(saved_search_id, latest_result, seen_results, slack_thread_ts)
+		VALUES($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT (saved_search_id) DO UPDATE SET
+			latest_result=EXCLUDED.latest_result,
+			seen_results=EXCLUDED.seen_results,
+			slack_thread_ts=EXCLUDED.slack_thread_ts,
+			updated_at=now()`,
+		cursor.SavedSearchID,
+		cursor.LatestResult,
+		pq.Array(seenResults),
+		cursor.SlackThreadTS,
+	)
+	if err != nil {
+		return errors.Wrap(err, "INSERT")
+	}
+	return nil
+}
@@ -78,7 +78,10 @@ func (s *SavedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url FROM saved_searches
+		slack_webhook_url,
+		slack_channel,
+		notify_teams,
+		teams_webhook_url FROM saved_searches
 	`)
 	rows, err := s.Query(ctx, q)
 	if err != nil {
@@ -95,7 +98,10 @@ func (s *SavedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 			&sq.Config.NotifySlack,
 			&sq.Config.UserID,
 			&sq.Config.OrgID,
-			&sq.Config.SlackWebhookURL); err != nil {
+			&sq.Config.SlackWebhookURL,
+			&sq.Config.SlackChannel,
+			&sq.Config.NotifyTeams,
+			&sq.Config.TeamsWebhookURL); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		sq.Spec.Key = sq.Config.Key
@@ -129,7 +135,10 @@ func (s *SavedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		slack_channel,
+		notify_teams,
+		teams_webhook_url
 		FROM saved_searches WHERE id=$1`, id).Scan(
 		&sq.Config.Key,
 		&sq.Config.Description,
@@ -138,7 +147,10 @@ func (s *SavedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		&sq.Config.NotifySlack,
 		&sq.Config.UserID,
 		&sq.Config.OrgID,
-		&sq.Config.SlackWebhookURL)
+		&sq.Config.SlackWebhookURL,
+		&sq.Config.SlackChannel,
+		&sq.Config.NotifyTeams,
+		&sq.Config.TeamsWebhookURL)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +202,10 @@ func (s *SavedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		slack_channel,
+		notify_teams,
+		teams_webhook_url
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -199,7 +214,7 @@ func (s *SavedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.SlackChannel, &ss.NotifyTeams, &ss.TeamsWebhookURL); err != nil {
 			return nil, errors.Wrap(err, "Scan(2)")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -225,7 +240,10 @@ func (s *SavedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		slack_channel,
+		notify_teams,
+		teams_webhook_url
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -234,7 +252,7 @@ func (s *SavedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.SlackChannel, &ss.NotifyTeams, &ss.TeamsWebhookURL); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 
@@ -265,12 +283,15 @@ func (s *SavedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 	}()
 
 	savedQuery = &types.SavedSearch{
-		Description: newSavedSearch.Description,
-		Query:       newSavedSearch.Query,
-		Notify:      newSavedSearch.Notify,
-		NotifySlack: newSavedSearch.NotifySlack,
-		UserID:      newSavedSearch.UserID,
-		OrgID:       newSavedSearch.OrgID,
+		Description:     newSavedSearch.Description,
+		Query:           newSavedSearch.Query,
+		Notify:          newSavedSearch.Notify,
+		NotifySlack:     newSavedSearch.NotifySlack,
+		UserID:          newSavedSearch.UserID,
+		OrgID:           newSavedSearch.OrgID,
+		SlackChannel:    newSavedSearch.SlackChannel,
+		NotifyTeams:     newSavedSearch.NotifyTeams,
+		TeamsWebhookURL: newSavedSearch.TeamsWebhookURL,
 	}
 
 	err = s.Handle().DB().QueryRowContext(ctx, `INSERT INTO saved_searches(
@@ -279,14 +300,20 @@ func (s *SavedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 			notify_owner,
 			notify_slack,
 			user_id,
-			org_id
-		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+			org_id,
+			slack_channel,
+			notify_teams,
+			teams_webhook_url
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
 		newSavedSearch.Description,
 		savedQuery.Query,
 		newSavedSearch.Notify,
 		newSavedSearch.NotifySlack,
 		newSavedSearch.UserID,
 		newSavedSearch.OrgID,
+		newSavedSearch.SlackChannel,
+		newSavedSearch.NotifyTeams,
+		newSavedSearch.TeamsWebhookURL,
 	).Scan(&savedQuery.ID)
 	if err != nil {
 		return nil, err
@@ -318,6 +345,9 @@ func (s *SavedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		UserID:          savedSearch.UserID,
 		OrgID:           savedSearch.OrgID,
 		SlackWebhookURL: savedSearch.SlackWebhookURL,
+		SlackChannel:    savedSearch.SlackChannel,
+		NotifyTeams:     savedSearch.NotifyTeams,
+		TeamsWebhookURL: savedSearch.TeamsWebhookURL,
 	}
 
 	fieldUpdates := []*sqlf.Query{
@@ -329,6 +359,9 @@ func (s *SavedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		sqlf.Sprintf("user_id=%v", savedSearch.UserID),
 		sqlf.Sprintf("org_id=%v", savedSearch.OrgID),
 		sqlf.Sprintf("slack_webhook_url=%v", savedSearch.SlackWebhookURL),
+		sqlf.Sprintf("slack_channel=%v", savedSearch.SlackChannel),
+		sqlf.Sprintf("notify_teams=%v", savedSearch.NotifyTeams),
+		sqlf.Sprintf("teams_webhook_url=%v", savedSearch.TeamsWebhookURL),
 	}
 
 	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
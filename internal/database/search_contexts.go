@@ -65,7 +65,7 @@ func searchContextsPermissionsCondition(ctx context.Context, db dbutil.DB) (*sql
 }
 
 const listSearchContextsFmtStr = `
-SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, u.username, o.name
+SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, u.username, o.name, sc.query, sc.managed
 FROM search_contexts sc
 LEFT JOIN users u on sc.namespace_user_id = u.id
 LEFT JOIN orgs o on sc.namespace_org_id = o.id
@@ -114,6 +114,8 @@ type ListSearchContextsOptions struct {
 	NamespaceOrgIDs []int32
 	// NoNamespace matches search contexts without a namespace ("instance-level contexts").
 	NoNamespace bool
+	// Managed matches only search contexts which are managed by the search contexts file.
+	Managed bool
 	// OrderBy specifies the ordering option for search contexts. Search contexts are ordered using SearchContextsOrderByID by default.
 	// SearchContextsOrderBySpec option sorts contexts by coallesced namespace names first
 	// (user name and org name) and then by context name. SearchContextsOrderByUpdatedAt option sorts
@@ -187,6 +189,10 @@ func getSearchContextsQueryConditions(opts ListSearchContextsOptions) ([]*sqlf.Q
 		conds = append(conds, sqlf.Sprintf("COALESCE(u.username, o.name, '') ILIKE %s", "%"+opts.NamespaceName+"%"))
 	}
 
+	if opts.Managed {
+		conds = append(conds, sqlf.Sprintf("sc.managed"))
+	}
+
 	if len(conds) == 0 {
 		// If no conditions are present, append a catch-all condition to avoid a SQL syntax error
 		conds = append(conds, sqlf.Sprintf("1 = 1"))
@@ -303,8 +309,8 @@ func (s *SearchContextsStore) DeleteSearchContext(ctx context.Context, searchCon
 
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
-(name, description, public, namespace_user_id, namespace_org_id)
-VALUES (%s, %s, %s, %s, %s)
+(name, description, public, namespace_user_id, namespace_org_id, query, managed)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to create the search context.
@@ -333,6 +339,7 @@ SET
 	name = %s,
 	description = %s,
 	public = %s,
+	query = %s,
 	updated_at = now()
 WHERE id = %d AND deleted_at IS NULL
 `
@@ -357,11 +364,9 @@ func (s *SearchContextsStore) UpdateSearchContextWithRepositoryRevisions(ctx con
 	return updatedSearchContext, nil
 }
 
+// SetSearchContextRepositoryRevisions replaces the repository revisions of
+// the search context. An empty list removes all of its repository revisions.
 func (s *SearchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64, repositoryRevisions []*types.SearchContextRepositoryRevisions) (err error) {
-	if len(repositoryRevisions) == 0 {
-		return nil
-	}
-
 	tx, err := s.Transact(ctx)
 	if err != nil {
 		return err
@@ -382,6 +387,9 @@ func (s *SearchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 			))
 		}
 	}
+	if len(values) == 0 {
+		return nil
+	}
 
 	return tx.Exec(ctx, sqlf.Sprintf(
 		"INSERT INTO search_context_repos (search_context_id, repo_id, revision) VALUES %s",
@@ -397,6 +405,8 @@ func (s *SearchContextsStore) createSearchContext(ctx context.Context, searchCon
 		searchContext.Public,
 		nullInt32Column(searchContext.NamespaceUserID),
 		nullInt32Column(searchContext.NamespaceOrgID),
+		nullStringColumn(searchContext.Query),
+		searchContext.Managed,
 	))
 	if err != nil {
 		return nil, err
@@ -414,6 +424,7 @@ func (s *SearchContextsStore) updateSearchContext(ctx context.Context, searchCon
 		searchContext.Name,
 		searchContext.Description,
 		searchContext.Public,
+		nullStringColumn(searchContext.Query),
 		searchContext.ID,
 	))
 	if err != nil {
@@ -451,6 +462,8 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&sc.UpdatedAt,
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
+			&dbutil.NullString{S: &sc.Query},
+			&sc.Managed,
 		)
 		if err != nil {
 			return nil, err
@@ -1660,6 +1660,32 @@ type Repository struct {
 	// Metadata retained for ranking
 	StargazerCount int `json:",omitempty"`
 	ForkCount      int `json:",omitempty"`
+
+	// RepositoryTopics is a list of topics the repository is tagged with.
+	RepositoryTopics RepositoryTopics
+}
+
+// RepositoryTopics is a list of topics of a repository, in the shape returned
+// by the GraphQL API.
+type RepositoryTopics struct {
+	Nodes []RepositoryTopic
+}
+
+type RepositoryTopic struct {
+	Topic Topic
+}
+
+type Topic struct {
+	Name string
+}
+
+// Topics returns the names of the topics the repository is tagged with.
+func (r *Repository) Topics() []string {
+	topics := make([]string, 0, len(r.RepositoryTopics.Nodes))
+	for _, node := range r.RepositoryTopics.Nodes {
+		topics = append(topics, node.Topic.Name)
+	}
+	return topics
 }
 
 func ownerNameCacheKey(owner, name string) string       { return "0:" + owner + "/" + name }
@@ -1731,6 +1757,7 @@ type restRepository struct {
 	Permissions restRepositoryPermissions `json:"permissions"`
 	Stars       int                       `json:"stargazers_count"`
 	Forks       int                       `json:"forks_count"`
+	Topics      []string                  `json:"topics"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -1766,7 +1793,21 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
 		StargazerCount:   restRepo.Stars,
 		ForkCount:        restRepo.Forks,
+		RepositoryTopics: convertRestRepoTopics(restRepo.Topics),
+	}
+}
+
+// convertRestRepoTopics converts the topics returned by the rest API to the
+// shape returned by the GraphQL API.
+func convertRestRepoTopics(topics []string) RepositoryTopics {
+	if len(topics) == 0 {
+		return RepositoryTopics{}
+	}
+	nodes := make([]RepositoryTopic, 0, len(topics))
+	for _, name := range topics {
+		nodes = append(nodes, RepositoryTopic{Topic: Topic{Name: name}})
 	}
+	return RepositoryTopics{Nodes: nodes}
 }
 
 // convertRestRepoPermissions converts repo information returned by the rest API
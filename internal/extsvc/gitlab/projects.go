@@ -30,6 +30,8 @@ type Project struct {
 	Archived          bool           `json:"archived"`
 	StarCount         int            `json:"star_count"`
 	ForksCount        int            `json:"forks_count"`
+	Topics            []string       `json:"topics,omitempty"`   // Topics of the project, available since GitLab 14.0
+	TagList           []string       `json:"tag_list,omitempty"` // Deprecated in favour of Topics since GitLab 14.0
 }
 
 type ProjectCommon struct {
@@ -41,6 +43,15 @@ type ProjectCommon struct {
 	SSHURLToRepo      string `json:"ssh_url_to_repo"`     // SSH clone URL ("git@example.com:foo/bar.git")
 }
 
+// ProjectTopics returns the topics of the project. Older GitLab versions
+// only return them as tag_list.
+func (p Project) ProjectTopics() []string {
+	if len(p.Topics) > 0 {
+		return p.Topics
+	}
+	return p.TagList
+}
+
 // RequiresAuthentication reports whether this project requires authentication to view (i.e., its visibility is
 // "private" or "internal").
 func (p Project) RequiresAuthentication() bool {
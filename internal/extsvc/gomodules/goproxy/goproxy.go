@@ -0,0 +1,141 @@
+// Package goproxy implements a client for Go module proxies, which serve Go
+// modules with the GOPROXY protocol. See https://golang.org/ref/mod#goproxy-protocol
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/mod/module"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultProxyURL is the URL of the public Go module mirror, which is used if
+// no proxies are configured.
+const DefaultProxyURL = "https://proxy.golang.org"
+
+// Client fetches module versions and zips from a list of Go module proxies.
+type Client struct {
+	urls    []string
+	doer    httpcli.Doer
+	limiter *rate.Limiter
+}
+
+// NewClient returns a client for the proxies of the given connection.
+func NewClient(connection *schema.GoModulesConnection, doer httpcli.Doer) *Client {
+	var urls []string
+	for _, u := range connection.Urls {
+		urls = append(urls, strings.TrimSuffix(u, "/"))
+	}
+	if len(urls) == 0 {
+		urls = []string{DefaultProxyURL}
+	}
+	return &Client{
+		urls:    urls,
+		doer:    doer,
+		limiter: ratelimit.DefaultRegistry.Get(urls[0]),
+	}
+}
+
+// VersionInfo is the metadata of a version of a module.
+type VersionInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// GetVersion returns the metadata of the given version of a module.
+func (c *Client) GetVersion(ctx context.Context, dependency reposource.GoDependency) (*VersionInfo, error) {
+	resp, err := c.get(ctx, dependency, ".info")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var info VersionInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, errors.Wrapf(err, "decoding version info of Go module %s", dependency.PackageManagerSyntax())
+	}
+	return &info, nil
+}
+
+// Exists returns true if a proxy serves the given version of a module.
+func (c *Client) Exists(ctx context.Context, dependency reposource.GoDependency) bool {
+	_, err := c.GetVersion(ctx, dependency)
+	return err == nil
+}
+
+// GetZip returns the zip file of the given version of a module. The caller
+// must close the returned reader.
+func (c *Client) GetZip(ctx context.Context, dependency reposource.GoDependency) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, dependency, ".zip")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// get requests the file with the given suffix of the given version of a
+// module from the proxies in order. Like the go command, it falls back to the
+// next proxy only if a proxy responds with 404 or 410.
+func (c *Client) get(ctx context.Context, dependency reposource.GoDependency, suffix string) (*http.Response, error) {
+	escapedPath, err := module.EscapePath(dependency.Path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := module.EscapeVersion(dependency.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, proxyURL := range c.urls {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		u := fmt.Sprintf("%s/%s/@v/%s%s", proxyURL, escapedPath, escapedVersion, suffix)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.doer.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if e := (&Error{StatusCode: resp.StatusCode, URL: u, Body: string(body)}); !e.NotFound() {
+			return nil, e
+		}
+	}
+	return nil, &Error{StatusCode: http.StatusNotFound, URL: dependency.PackageManagerSyntax(), Body: "not found in any Go module proxy"}
+}
+
+// Error is returned for unsuccessful responses of a proxy.
+type Error struct {
+	StatusCode int
+	URL        string
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Go module proxy HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+}
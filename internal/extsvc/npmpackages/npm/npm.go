@@ -0,0 +1,150 @@
+// Package npm implements a client for npm registries, which host the packages
+// of the JavaScript ecosystem.
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultRegistryURL is the URL of the public npm registry, which is used if
+// no registry is configured.
+const DefaultRegistryURL = "https://registry.npmjs.org"
+
+// Client fetches package metadata and tarballs from an npm registry.
+type Client struct {
+	registryURL string
+	credentials string
+	doer        httpcli.Doer
+	limiter     *rate.Limiter
+}
+
+// NewClient returns a client for the registry of the given connection.
+func NewClient(connection *schema.NpmPackagesConnection, doer httpcli.Doer) *Client {
+	registryURL := strings.TrimSuffix(connection.Registry, "/")
+	if registryURL == "" {
+		registryURL = DefaultRegistryURL
+	}
+	return &Client{
+		registryURL: registryURL,
+		credentials: connection.Credentials,
+		doer:        doer,
+		limiter:     ratelimit.DefaultRegistry.Get(registryURL),
+	}
+}
+
+// PackageVersion is the metadata of one version of a package.
+type PackageVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dist    struct {
+		Tarball string `json:"tarball"`
+	} `json:"dist"`
+}
+
+// GetPackageVersion returns the metadata of the given version of a package.
+func (c *Client) GetPackageVersion(ctx context.Context, dependency reposource.NpmDependency) (*PackageVersion, error) {
+	u := fmt.Sprintf("%s/%s/%s", c.registryURL, dependency.PackageSyntax(), url.PathEscape(dependency.Version))
+	resp, err := c.do(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var version PackageVersion
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return nil, errors.Wrapf(err, "decoding metadata of npm package %s", dependency.PackageManagerSyntax())
+	}
+	if version.Dist.Tarball == "" {
+		return nil, errors.Errorf("npm package %s has no tarball", dependency.PackageManagerSyntax())
+	}
+	return &version, nil
+}
+
+// Exists returns true if the registry hosts the given version of a package.
+func (c *Client) Exists(ctx context.Context, dependency reposource.NpmDependency) bool {
+	_, err := c.GetPackageVersion(ctx, dependency)
+	return err == nil
+}
+
+// FetchTarball returns the gzipped tarball of the given version of a package.
+// The caller must close the returned reader.
+func (c *Client) FetchTarball(ctx context.Context, dependency reposource.NpmDependency) (io.ReadCloser, error) {
+	version, err := c.GetPackageVersion(ctx, dependency)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, version.Dist.Tarball)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a GET request to the given URL and returns the response if it is
+// successful. The credentials are only sent to the registry, and not to other
+// hosts which tarballs may be served from.
+func (c *Client) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.credentials != "" && sameHost(rawURL, c.registryURL) {
+		req.Header.Set("Authorization", "Bearer "+c.credentials)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &Error{StatusCode: resp.StatusCode, URL: rawURL, Body: string(body)}
+	}
+	return resp, nil
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme && ua.Host == ub.Host
+}
+
+// Error is returned for unsuccessful responses of the registry.
+type Error struct {
+	StatusCode int
+	URL        string
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("npm registry HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
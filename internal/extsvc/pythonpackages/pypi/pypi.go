@@ -0,0 +1,265 @@
+// Package pypi implements a client for Python package indexes, which serve
+// Python packages with the simple repository API. See
+// https://www.python.org/dev/peps/pep-0503/
+package pypi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultIndexURL is the URL of the simple repository API of the Python
+// Package Index, which is used if no indexes are configured.
+const DefaultIndexURL = "https://pypi.org/simple"
+
+// Client fetches the distribution files of packages from a list of Python
+// package indexes.
+type Client struct {
+	urls    []string
+	doer    httpcli.Doer
+	limiter *rate.Limiter
+}
+
+// NewClient returns a client for the indexes of the given connection.
+func NewClient(connection *schema.PythonPackagesConnection, doer httpcli.Doer) *Client {
+	var urls []string
+	for _, u := range connection.Urls {
+		urls = append(urls, strings.TrimSuffix(u, "/"))
+	}
+	if len(urls) == 0 {
+		urls = []string{DefaultIndexURL}
+	}
+	return &Client{
+		urls:    urls,
+		doer:    doer,
+		limiter: ratelimit.DefaultRegistry.Get(urls[0]),
+	}
+}
+
+// File is a distribution file of a package, which is either a source
+// distribution (sdist) or a wheel.
+type File struct {
+	// Name is the file name, like "requests-2.26.0.tar.gz".
+	Name string
+	// URL is the absolute URL of the file.
+	URL string
+	// SHA256 is the hex-encoded SHA-256 hash of the file, or empty if the
+	// index doesn't provide it.
+	SHA256 string
+}
+
+// IsWheel returns true if the file is a wheel, and false if it is a source
+// distribution.
+func (f File) IsWheel() bool {
+	return strings.HasSuffix(f.Name, ".whl")
+}
+
+// sdistExtensions are the extensions of the supported source distribution
+// formats.
+var sdistExtensions = []string{".tar.gz", ".zip"}
+
+// parseFileName returns the normalized package name and the version of a
+// distribution file name. See https://packaging.python.org/specifications/binary-distribution-format/
+// and https://www.python.org/dev/peps/pep-0625/
+func parseFileName(name string) (pkg, version string, ok bool) {
+	if strings.HasSuffix(name, ".whl") {
+		// {distribution}-{version}(-{build tag})?-{python tag}-{abi tag}-{platform tag}.whl
+		parts := strings.Split(strings.TrimSuffix(name, ".whl"), "-")
+		if len(parts) != 5 && len(parts) != 6 {
+			return "", "", false
+		}
+		return reposource.NormalizePythonPackageName(parts[0]), parts[1], true
+	}
+	for _, ext := range sdistExtensions {
+		if !strings.HasSuffix(name, ext) {
+			continue
+		}
+		// {distribution}-{version}.tar.gz, where legacy distribution names
+		// may contain dashes, too.
+		base := strings.TrimSuffix(name, ext)
+		i := strings.LastIndex(base, "-")
+		if i <= 0 {
+			return "", "", false
+		}
+		return reposource.NormalizePythonPackageName(base[:i]), base[i+1:], true
+	}
+	return "", "", false
+}
+
+// Project returns the distribution files of all versions of a package.
+func (c *Client) Project(ctx context.Context, pkg reposource.PythonPackage) ([]File, error) {
+	for _, indexURL := range c.urls {
+		files, err := c.project(ctx, indexURL, pkg)
+		if err == nil {
+			return files, nil
+		}
+		var e *Error
+		if !errors.As(err, &e) || !e.NotFound() {
+			return nil, err
+		}
+	}
+	return nil, &Error{StatusCode: http.StatusNotFound, URL: pkg.Name, Body: "not found in any Python package index"}
+}
+
+// project returns the distribution files listed on the project page of the
+// package in the given index.
+func (c *Client) project(ctx context.Context, indexURL string, pkg reposource.PythonPackage) ([]File, error) {
+	u := fmt.Sprintf("%s/%s/", indexURL, pkg.Name)
+	resp, err := c.do(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Links are relative to the URL of the page after redirects.
+	pageURL, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		pageURL = resp.Request.URL
+	}
+	files, err := parseProjectPage(pageURL, resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing project page of Python package %s", pkg.Name)
+	}
+	return files, nil
+}
+
+// parseProjectPage returns the files linked from a project page. The links
+// are resolved relative to the URL of the page.
+func parseProjectPage(pageURL *url.URL, r io.Reader) ([]File, error) {
+	var files []File
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return files, nil
+			}
+			return nil, z.Err()
+		case html.StartTagToken:
+			t := z.Token()
+			if t.Data != "a" {
+				continue
+			}
+			for _, attr := range t.Attr {
+				if attr.Key != "href" {
+					continue
+				}
+				u, err := pageURL.Parse(attr.Val)
+				if err != nil {
+					break
+				}
+				f := File{Name: path.Base(u.Path)}
+				if hash := strings.TrimPrefix(u.Fragment, "sha256="); hash != u.Fragment {
+					f.SHA256 = hash
+				}
+				u.Fragment = ""
+				f.URL = u.String()
+				files = append(files, f)
+			}
+		}
+	}
+}
+
+// Version returns the distribution file of the given version of a package.
+// Source distributions are preferred over wheels because they contain all
+// sources of a package, and pure-Python wheels are preferred over wheels for
+// specific platforms.
+func (c *Client) Version(ctx context.Context, dependency reposource.PythonDependency) (File, error) {
+	files, err := c.Project(ctx, dependency.PythonPackage)
+	if err != nil {
+		return File{}, err
+	}
+
+	var wheels []File
+	for _, f := range files {
+		pkg, version, ok := parseFileName(f.Name)
+		if !ok || pkg != dependency.Name || !strings.EqualFold(version, dependency.Version) {
+			continue
+		}
+		if !f.IsWheel() {
+			return f, nil
+		}
+		wheels = append(wheels, f)
+	}
+	for _, f := range wheels {
+		if strings.HasSuffix(f.Name, "-none-any.whl") {
+			return f, nil
+		}
+	}
+	if len(wheels) > 0 {
+		return wheels[0], nil
+	}
+	return File{}, &Error{StatusCode: http.StatusNotFound, URL: dependency.PackageManagerSyntax(), Body: "no supported distribution file"}
+}
+
+// Exists returns true if an index serves the given version of a package.
+func (c *Client) Exists(ctx context.Context, dependency reposource.PythonDependency) bool {
+	_, err := c.Version(ctx, dependency)
+	return err == nil
+}
+
+// Download returns the contents of the given distribution file. The caller
+// must close the returned reader.
+func (c *Client) Download(ctx context.Context, f File) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, f.URL)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a GET request to the given URL and returns the response if it is
+// successful.
+func (c *Client) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &Error{StatusCode: resp.StatusCode, URL: rawURL, Body: string(body)}
+	}
+	return resp, nil
+}
+
+// Error is returned for unsuccessful responses of an index.
+type Error struct {
+	StatusCode int
+	URL        string
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Python package index HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,99 @@
+package pypi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     string
+		version string
+		ok      bool
+	}{
+		{name: "requests-2.26.0.tar.gz", pkg: "requests", version: "2.26.0", ok: true},
+		{name: "python-dateutil-2.8.2.tar.gz", pkg: "python-dateutil", version: "2.8.2", ok: true},
+		{name: "Django-3.2.9.zip", pkg: "django", version: "3.2.9", ok: true},
+		{name: "python_dateutil-2.8.2-py2.py3-none-any.whl", pkg: "python-dateutil", version: "2.8.2", ok: true},
+		{name: "numpy-1.21.4-1-cp39-cp39-manylinux_2_17_x86_64.whl", pkg: "numpy", version: "1.21.4", ok: true},
+		{name: "numpy-1.21.4.whl", ok: false},
+		{name: "requests-2.26.0.exe", ok: false},
+		{name: "requests.tar.gz", ok: false},
+	}
+	for _, test := range tests {
+		pkg, version, ok := parseFileName(test.name)
+		if ok != test.ok || pkg != test.pkg || version != test.version {
+			t.Errorf("parseFileName(%q) = (%q, %q, %v), want (%q, %q, %v)", test.name, pkg, version, ok, test.pkg, test.version, test.ok)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	// The first index never has any packages, so all requests fall back to
+	// the second one.
+	empty := httptest.NewServer(http.NotFoundHandler())
+	defer empty.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/simple/python-dateutil/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html><body>
+<a href="../../files/python_dateutil-2.8.1-py2.py3-none-any.whl#sha256=75bb">python_dateutil-2.8.1-py2.py3-none-any.whl</a>
+<a href="../../files/python_dateutil-2.8.2-cp39-cp39-win32.whl">python_dateutil-2.8.2-cp39-cp39-win32.whl</a>
+<a href="../../files/python_dateutil-2.8.2-py2.py3-none-any.whl#sha256=961d">python_dateutil-2.8.2-py2.py3-none-any.whl</a>
+<a href="/files/python-dateutil-2.8.2.tar.gz#sha256=0123" data-requires-python="&gt;=2.7">python-dateutil-2.8.2.tar.gz</a>
+</body></html>`))
+	})
+	index := httptest.NewServer(mux)
+	defer index.Close()
+
+	client := NewClient(&schema.PythonPackagesConnection{Urls: []string{empty.URL, index.URL + "/simple/"}}, http.DefaultClient)
+	ctx := context.Background()
+
+	parse := func(dependency string) reposource.PythonDependency {
+		d, err := reposource.ParsePythonDependency(dependency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		dependency string
+		want       File
+	}{
+		{
+			// source distributions are preferred
+			dependency: "Python_Dateutil==2.8.2",
+			want:       File{Name: "python-dateutil-2.8.2.tar.gz", URL: index.URL + "/files/python-dateutil-2.8.2.tar.gz", SHA256: "0123"},
+		},
+		{
+			dependency: "python-dateutil==2.8.1",
+			want:       File{Name: "python_dateutil-2.8.1-py2.py3-none-any.whl", URL: index.URL + "/files/python_dateutil-2.8.1-py2.py3-none-any.whl", SHA256: "75bb"},
+		},
+	}
+	for _, test := range tests {
+		got, err := client.Version(ctx, parse(test.dependency))
+		if err != nil {
+			t.Fatalf("Version(%q) returned unexpected error: %v", test.dependency, err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Version(%q) mismatch (-want, +got):\n%s", test.dependency, diff)
+		}
+	}
+
+	if client.Exists(ctx, parse("python-dateutil==2.8.0")) {
+		t.Error("expected python-dateutil==2.8.0 to not exist")
+	}
+	if client.Exists(ctx, parse("requests==2.26.0")) {
+		t.Error("expected requests==2.26.0 to not exist")
+	}
+}
@@ -0,0 +1,7 @@
+package pythonpackages
+
+import "github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+
+type Metadata struct {
+	Package reposource.PythonPackage
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/neelance/parallel"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
@@ -61,6 +62,7 @@ func NewClient(cli httpcli.Doer) *Client {
 		Addrs: func() []string {
 			return conf.Get().ServiceConnections.GitServers
 		},
+		Replicas:    conf.GitReplicationFactor,
 		HTTPClient:  cli,
 		HTTPLimiter: parallel.NewRun(500),
 		// Use the binary name for UserAgent. This should effectively identify
@@ -83,6 +85,14 @@ type Client struct {
 	// concurrent use. It may return different results at different times.
 	Addrs func() []string
 
+	// Replicas is a function which should return the number of gitservers each
+	// repository is stored on. If it is nil, repositories are not replicated.
+	Replicas func() int
+
+	// health tracks the gitservers which recently failed requests, so that
+	// requests prefer the healthy replicas of a repository.
+	health replicaHealth
+
 	// UserAgent is a string identifying who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
@@ -97,6 +107,32 @@ func (c *Client) AddrForRepo(repo api.RepoName) string {
 	return AddrForRepo(repo, addrs)
 }
 
+// AddrsForRepo returns the addresses of the gitservers which store the given
+// repo, starting with the primary returned by AddrForRepo.
+func (c *Client) AddrsForRepo(repo api.RepoName) []string {
+	addrs := c.Addrs()
+	if len(addrs) == 0 {
+		panic("unexpected state: no gitserver addresses")
+	}
+	return AddrsForRepo(repo, addrs, c.replicas())
+}
+
+// ReadAddrsForRepo returns the addresses of the gitservers which store the
+// given repo in the order read requests try them: the primary first, unless
+// it recently couldn't be reached, in which case it is tried after the
+// healthy replicas.
+func (c *Client) ReadAddrsForRepo(repo api.RepoName) []string {
+	return c.health.order(c.AddrsForRepo(repo))
+}
+
+// replicas returns the number of gitservers each repository is stored on.
+func (c *Client) replicas() int {
+	if c.Replicas == nil {
+		return 1
+	}
+	return c.Replicas()
+}
+
 // addrForKey returns the gitserver address to use for the given string key,
 // which is hashed for sharding purposes.
 func (c *Client) addrForKey(key string) string {
@@ -121,6 +157,36 @@ func AddrForRepo(repo api.RepoName, addrs []string) string {
 	return addrForKey(string(repo), addrs)
 }
 
+// AddrsForRepo returns the addresses of the gitservers which store the given
+// repo when it is replicated to the given number of gitservers. The first
+// address is the primary returned by AddrForRepo, the replicas are stored on
+// the gitservers following it in addrs. This means that repos with the same
+// primary also share their replicas. It should never be called with an empty
+// slice.
+func AddrsForRepo(repo api.RepoName, addrs []string, replicas int) []string {
+	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
+	return addrsForKey(string(repo), addrs, replicas)
+}
+
+// addrsForKey returns the gitserver addresses to use for the given string
+// key when it is replicated to the given number of gitservers.
+func addrsForKey(key string, addrs []string, replicas int) []string {
+	if replicas < 1 {
+		replicas = 1
+	}
+	if replicas > len(addrs) {
+		replicas = len(addrs)
+	}
+	sum := md5.Sum([]byte(key))
+	serverIndex := binary.BigEndian.Uint64(sum[:]) % uint64(len(addrs))
+
+	replicaAddrs := make([]string, 0, replicas)
+	for i := 0; i < replicas; i++ {
+		replicaAddrs = append(replicaAddrs, addrs[(serverIndex+uint64(i))%uint64(len(addrs))])
+	}
+	return replicaAddrs
+}
+
 // addrForKey returns the gitserver address to use for the given string key,
 // which is hashed for sharding purposes.
 func addrForKey(key string, addrs []string) string {
@@ -162,8 +228,20 @@ func (a *archiveReader) Close() error {
 }
 
 // ArchiveURL returns a URL from which an archive of the given Git repository can
-// be downloaded from.
+// be downloaded from. The URL points to the first gitserver of
+// ReadAddrsForRepo.
 func (c *Client) ArchiveURL(repo api.RepoName, opt ArchiveOptions) *url.URL {
+	return &url.URL{
+		Scheme:   "http",
+		Host:     c.ReadAddrsForRepo(repo)[0],
+		Path:     "/archive",
+		RawQuery: archiveQuery(repo, opt).Encode(),
+	}
+}
+
+// archiveQuery returns the query of the archive endpoint for the given repo
+// and options.
+func archiveQuery(repo api.RepoName, opt ArchiveOptions) url.Values {
 	q := url.Values{
 		"repo":    {string(repo)},
 		"treeish": {opt.Treeish},
@@ -174,12 +252,7 @@ func (c *Client) ArchiveURL(repo api.RepoName, opt ArchiveOptions) *url.URL {
 		q.Add("path", path)
 	}
 
-	return &url.URL{
-		Scheme:   "http",
-		Host:     c.AddrForRepo(repo),
-		Path:     "/archive",
-		RawQuery: q.Encode(),
-	}
+	return q
 }
 
 // Archive produces an archive from a Git repository.
@@ -201,8 +274,9 @@ func (c *Client) Archive(ctx context.Context, repo api.RepoName, opt ArchiveOpti
 		return nil, err
 	}
 
-	u := c.ArchiveURL(repo, opt)
-	resp, err := c.do(ctx, repo, "GET", u.String(), nil)
+	// The archive is requested by path instead of ArchiveURL, so that we fail
+	// over to the replicas of repo.
+	resp, err := c.do(ctx, repo, "GET", "archive?"+archiveQuery(repo, opt).Encode(), nil, readOnlyOps["archive"])
 	if err != nil {
 		return nil, err
 	}
@@ -316,7 +390,7 @@ func (c *Client) Search(ctx context.Context, args *protocol.SearchRequest, onMat
 		return false, err
 	}
 
-	resp, err := c.do(ctx, repoName, "POST", "search", buf.Bytes())
+	resp, err := c.do(ctx, repoName, "POST", "search", buf.Bytes(), readOnlyOps["search"])
 	if err != nil {
 		return false, err
 	}
@@ -526,15 +600,17 @@ func (c *Client) ListGitolite(ctx context.Context, gitoliteHost string) (list []
 	return list, err
 }
 
-// ListCloned lists all cloned repositories
+// ListCloned lists all cloned repositories. A repository is listed once, even
+// if it is cloned on several of its replicas.
 func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 	var (
 		wg    sync.WaitGroup
 		mu    sync.Mutex
 		err   error
-		repos []string
+		repos = make(map[string]struct{})
 	)
 	addrs := c.Addrs()
+	replicas := c.replicas()
 	for _, addr := range addrs {
 		wg.Add(1)
 		go func(addr string) {
@@ -545,8 +621,11 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 			if len(r) > 0 {
 				filtered := r[:0]
 				for _, repo := range r {
-					if addrForKey(repo, addrs) == addr {
-						filtered = append(filtered, repo)
+					for _, a := range addrsForKey(repo, addrs, replicas) {
+						if a == addr {
+							filtered = append(filtered, repo)
+							break
+						}
 					}
 				}
 				r = filtered
@@ -555,12 +634,19 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 			if e != nil {
 				err = e
 			}
-			repos = append(repos, r...)
+			for _, repo := range r {
+				repos[repo] = struct{}{}
+			}
 			mu.Unlock()
 		}(addr)
 	}
 	wg.Wait()
-	return repos, err
+
+	cloned := make([]string, 0, len(repos))
+	for repo := range repos {
+		cloned = append(cloned, repo)
+	}
+	return cloned, err
 }
 
 // GetGitolitePhabricatorMetadata returns Phabricator metadata for a Gitolite repository fetched via
@@ -609,12 +695,51 @@ func (c *Client) doListOne(ctx context.Context, urlSuffix, addr string) ([]strin
 // Repo updates are not guaranteed to occur. If a repo has been updated
 // recently (within the Since duration specified in the request), the
 // update won't happen.
+//
+// The update is requested from every replica of the repo, so that they can
+// serve it when the primary is unavailable. The response of the primary is
+// returned, or of the first replica which succeeded if the primary failed.
 func (c *Client) RequestRepoUpdate(ctx context.Context, repo api.RepoName, since time.Duration) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
 		Repo:  repo,
 		Since: since,
 	}
-	resp, err := c.httpPost(ctx, repo, "repo-update", req)
+
+	addrs := c.AddrsForRepo(repo)
+	infos := make([]*protocol.RepoUpdateResponse, len(addrs))
+	errs := make([]error, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			infos[i], errs[i] = c.requestRepoUpdate(ctx, addr, req)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			continue
+		}
+		for j, err := range errs {
+			if err != nil {
+				log15.Warn("failed to update repo on replica", "repo", repo, "addr", addrs[j], "error", err)
+			}
+		}
+		return infos[i], nil
+	}
+	return nil, errs[0]
+}
+
+// requestRepoUpdate requests an update of a repo from the gitserver with the
+// given address.
+func (c *Client) requestRepoUpdate(ctx context.Context, addr string, req *protocol.RepoUpdateRequest) (*protocol.RepoUpdateResponse, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, req.Repo, "POST", "http://"+addr+"/repo-update", b, false)
 	if err != nil {
 		return nil, err
 	}
@@ -709,6 +834,8 @@ func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (
 	numPossibleShards := len(c.Addrs())
 	shards := make(map[string]*protocol.RepoCloneProgressRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
+	// Repos with the same primary also share their replicas, so the request
+	// of a shard fails over to the replicas of its first repo.
 	for _, r := range repos {
 		addr := c.AddrForRepo(r)
 		shard := shards[addr]
@@ -786,6 +913,8 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	numPossibleShards := len(c.Addrs())
 	shards := make(map[string]*protocol.RepoInfoRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
+	// Repos with the same primary also share their replicas, so the request
+	// of a shard fails over to the replicas of its first repo.
 	for _, r := range repos {
 		addr := c.AddrForRepo(r)
 		shard := shards[addr]
@@ -894,12 +1023,30 @@ func (c *Client) doReposStats(ctx context.Context, addr string) (*protocol.Repos
 	return &stats, nil
 }
 
-// Remove removes the repository clone from gitserver.
+// Remove removes the repository clone from every gitserver replica of the
+// repository.
 func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
 	req := &protocol.RepoDeleteRequest{
 		Repo: repo,
 	}
-	resp, err := c.httpPost(ctx, repo, "delete", req)
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, addr := range c.AddrsForRepo(repo) {
+		if err := c.remove(ctx, addr, repo, b); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// remove removes the repository clone from the gitserver with the given
+// address.
+func (c *Client) remove(ctx context.Context, addr string, repo api.RepoName, payload []byte) error {
+	resp, err := c.do(ctx, repo, "POST", "http://"+addr+"/delete", payload, false)
 	if err != nil {
 		return err
 	}
@@ -917,12 +1064,73 @@ func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, pay
 	if err != nil {
 		return nil, err
 	}
-	return c.do(ctx, repo, "POST", op, b)
+	return c.do(ctx, repo, "POST", op, b, canFailover(op, payload))
+}
+
+// readOnlyOps are the gitserver endpoints which don't change repositories.
+// Requests to them can be retried on another replica. Other requests, such as
+// create-commit-from-patch, may have been applied by a gitserver which failed
+// to respond, and replicas are synced independently, so they are never
+// retried.
+var readOnlyOps = map[string]bool{
+	"archive":             true,
+	"search":              true,
+	"is-repo-cloneable":   true,
+	"is-repo-cloned":      true,
+	"repo-clone-progress": true,
+	"repos":               true,
+	"commands/get-object": true,
+}
+
+// readOnlyGitCommands are the git commands which don't change repositories,
+// so that exec requests running them can be retried on another replica. See
+// IsReadOnlyGitCommand.
+var readOnlyGitCommands = map[string]bool{
+	"blame":        true,
+	"cat-file":     true,
+	"diff":         true,
+	"diff-tree":    true,
+	"for-each-ref": true,
+	"log":          true,
+	"ls-files":     true,
+	"ls-tree":      true,
+	"merge-base":   true,
+	"rev-list":     true,
+	"rev-parse":    true,
+	"shortlog":     true,
+	"show":         true,
+	"show-ref":     true,
+}
+
+// IsReadOnlyGitCommand reports whether the git command with the given
+// arguments, excluding "git", is known not to change the repository. Exec
+// requests running such commands can be retried on another replica.
+func IsReadOnlyGitCommand(args []string) bool {
+	return len(args) > 0 && readOnlyGitCommands[args[0]]
+}
+
+// canFailover reports whether a request to op with payload can be retried on
+// another replica of its repo. Exec requests can only be retried if they run a
+// read-only git command.
+func canFailover(op string, payload interface{}) bool {
+	if req, ok := payload.(*protocol.ExecRequest); ok {
+		return IsReadOnlyGitCommand(req.Args)
+	}
+	return readOnlyOps[op]
 }
 
+var replicaFailoverCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_replica_failover",
+	Help: "Number of times a request was retried on another replica because a gitserver was unavailable",
+})
+
 // do performs a request to a gitserver, sharding based on the given
-// repo name (the repo name is otherwise not used).
-func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload []byte) (resp *http.Response, err error) {
+// repo name (the repo name is otherwise not used). If failover is true and a
+// gitserver can't be reached or responds with a server error, the request is
+// retried on the next replica of the repo, so it must only be true for
+// requests which don't change the repo.
+// See canFailover. Requests to an absolute URL are only sent to that URL.
+func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload []byte, failover bool) (resp *http.Response, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Client.do")
 	defer func() {
 		span.LogKV("repo", string(repo), "method", method, "op", op)
@@ -933,11 +1141,51 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 		span.Finish()
 	}()
 
-	uri := op
-	if !strings.HasPrefix(op, "http") {
-		uri = "http://" + c.AddrForRepo(repo) + "/" + op
+	if strings.HasPrefix(op, "http") {
+		return c.doRequest(ctx, span, method, op, payload)
 	}
 
+	var addrs []string
+	if failover {
+		addrs = c.ReadAddrsForRepo(repo)
+	} else {
+		// Requests which may change the repo always go to its primary.
+		addrs = c.AddrsForRepo(repo)[:1]
+	}
+	for i, addr := range addrs {
+		last := i == len(addrs)-1
+		resp, err = c.doRequest(ctx, span, method, "http://"+addr+"/"+op, payload)
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError && !last {
+			// The gitserver is reachable but failed, another replica may
+			// still be able to serve the request. The last response is
+			// returned as is, so that callers can report the status.
+			resp.Body.Close()
+			replicaFailoverCounter.Inc()
+			span.LogKV("event", "failover", "addr", addr, "status", resp.StatusCode)
+			log15.Warn("gitserver request failed, failing over to the next replica", "repo", repo, "addr", addr, "status", resp.StatusCode)
+			continue
+		}
+		if err == nil {
+			c.health.markHealthy(addr)
+			return resp, nil
+		}
+		// The gitserver isn't to blame if our own context is done.
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		c.health.markUnhealthy(addr)
+		if !last {
+			replicaFailoverCounter.Inc()
+			span.LogKV("event", "failover", "addr", addr)
+			log15.Warn("gitserver unavailable, failing over to the next replica", "repo", repo, "addr", addr, "error", err)
+		}
+	}
+	return nil, err
+}
+
+// doRequest performs a single request to the given gitserver URL.
+func (c *Client) doRequest(ctx context.Context, span opentracing.Span, method, uri string, payload []byte) (*http.Response, error) {
 	req, err := http.NewRequest(method, uri, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
@@ -962,6 +1210,54 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 	return c.HTTPClient.Do(req)
 }
 
+// replicaUnhealthyDuration is how long a gitserver which couldn't be reached
+// is only tried after the other replicas of a repo.
+const replicaUnhealthyDuration = 30 * time.Second
+
+// replicaHealth tracks the gitservers which recently couldn't be reached. The
+// zero value is ready to use.
+type replicaHealth struct {
+	mu             sync.Mutex
+	unhealthyUntil map[string]time.Time
+}
+
+func (h *replicaHealth) markUnhealthy(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.unhealthyUntil == nil {
+		h.unhealthyUntil = make(map[string]time.Time)
+	}
+	h.unhealthyUntil[addr] = time.Now().Add(replicaUnhealthyDuration)
+}
+
+func (h *replicaHealth) markHealthy(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.unhealthyUntil, addr)
+}
+
+// order returns addrs with the unhealthy gitservers moved to the end. The
+// order is otherwise kept, so that the primary is preferred.
+func (h *replicaHealth) order(addrs []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.unhealthyUntil) == 0 {
+		return addrs
+	}
+
+	now := time.Now()
+	healthy := make([]string, 0, len(addrs))
+	var unhealthy []string
+	for _, addr := range addrs {
+		if until, ok := h.unhealthyUntil[addr]; ok && now.Before(until) {
+			unhealthy = append(unhealthy, addr)
+		} else {
+			healthy = append(healthy, addr)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
 func userFromContext(ctx context.Context) string {
 	a := actor.FromContext(ctx)
 	if a == nil {
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/cockroachdb/errors"
@@ -23,6 +26,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
@@ -287,6 +291,379 @@ func TestAddrForRepo(t *testing.T) {
 	}
 }
 
+func TestAddrsForRepo(t *testing.T) {
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+
+	testCases := []struct {
+		name     string
+		repo     api.RepoName
+		replicas int
+		want     []string
+	}{
+		{
+			name:     "no replication",
+			repo:     api.RepoName("repo1"),
+			replicas: 1,
+			want:     []string{"gitserver-3"},
+		},
+		{
+			name:     "replicas follow the primary",
+			repo:     api.RepoName("repo1"),
+			replicas: 2,
+			want:     []string{"gitserver-3", "gitserver-1"},
+		},
+		{
+			name:     "check we normalise",
+			repo:     api.RepoName("repo1.git"),
+			replicas: 2,
+			want:     []string{"gitserver-3", "gitserver-1"},
+		},
+		{
+			name:     "another repo",
+			repo:     api.RepoName("github.com/sourcegraph/sourcegraph.git"),
+			replicas: 2,
+			want:     []string{"gitserver-2", "gitserver-3"},
+		},
+		{
+			name:     "capped at the number of gitservers",
+			repo:     api.RepoName("repo1"),
+			replicas: 5,
+			want:     []string{"gitserver-3", "gitserver-1", "gitserver-2"},
+		},
+		{
+			name:     "invalid replication factor",
+			repo:     api.RepoName("repo1"),
+			replicas: 0,
+			want:     []string{"gitserver-3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := gitserver.AddrsForRepo(tc.repo, addrs, tc.replicas)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// replicatedClient returns a client for three gitservers which replicates
+// repos to two of them. The requested URLs are recorded in requests, and
+// requests to the gitservers in down fail.
+func replicatedClient(requests *[]string, down ...string) *gitserver.Client {
+	var mu sync.Mutex
+	return &gitserver.Client{
+		Addrs:    func() []string { return []string{"gitserver-1", "gitserver-2", "gitserver-3"} },
+		Replicas: func() int { return 2 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*requests = append(*requests, r.URL.String())
+			mu.Unlock()
+
+			for _, addr := range down {
+				if r.URL.Host == addr {
+					return nil, errors.Errorf("dial tcp %s: connection refused", addr)
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"cloned": true}`)),
+			}, nil
+		}),
+	}
+}
+
+func TestClient_Failover(t *testing.T) {
+	ctx := context.Background()
+
+	// The primary of repo1 is gitserver-3, its replica is gitserver-1.
+	var requests []string
+	cli := replicatedClient(&requests, "gitserver-3")
+
+	cloned, err := cli.IsRepoCloned(ctx, "repo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cloned {
+		t.Fatal("want repo1 to be cloned on the replica")
+	}
+	want := []string{"http://gitserver-3/is-repo-cloned", "http://gitserver-1/is-repo-cloned"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The unavailable primary is only tried after the healthy replica.
+	requests = nil
+	if _, err := cli.IsRepoCloned(ctx, "repo1"); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"http://gitserver-1/is-repo-cloned"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Requests fail if no replica is available.
+	requests = nil
+	cli = replicatedClient(&requests, "gitserver-3", "gitserver-1")
+	if _, err := cli.IsRepoCloned(ctx, "repo1"); err == nil {
+		t.Fatal("want error if all replicas are unavailable")
+	}
+	want = []string{"http://gitserver-3/is-repo-cloned", "http://gitserver-1/is-repo-cloned"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_NoFailoverForWrites(t *testing.T) {
+	ctx := context.Background()
+
+	// The primary of repo1 is gitserver-3, its replica is gitserver-1.
+	tests := []struct {
+		name string
+		run  func(cli *gitserver.Client) error
+		want []string
+	}{
+		{
+			name: "read-only git command",
+			run: func(cli *gitserver.Client) error {
+				cmd := cli.Command("git", "log", "-n1")
+				cmd.Repo = "repo1"
+				_, err := cmd.Output(ctx)
+				return err
+			},
+			want: []string{"http://gitserver-3/exec", "http://gitserver-1/exec"},
+		},
+		{
+			name: "git command which changes the repo",
+			run: func(cli *gitserver.Client) error {
+				cmd := cli.Command("git", "update-ref", "refs/heads/main", "HEAD")
+				cmd.Repo = "repo1"
+				_, err := cmd.Output(ctx)
+				return err
+			},
+			want: []string{"http://gitserver-3/exec"},
+		},
+		{
+			name: "create commit from patch",
+			run: func(cli *gitserver.Client) error {
+				_, err := cli.CreateCommitFromPatch(ctx, protocol.CreateCommitFromPatchRequest{Repo: "repo1"})
+				return err
+			},
+			want: []string{"http://gitserver-3/create-commit-from-patch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			cli := replicatedClient(&requests, "gitserver-3")
+
+			err := tt.run(cli)
+			if len(tt.want) == 1 && err == nil {
+				t.Fatal("want error if the primary is unavailable")
+			}
+			if diff := cmp.Diff(tt.want, requests); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_RepoInfo_Replicated(t *testing.T) {
+	ctx := context.Background()
+
+	// repo1 is stored on gitserver-3 and gitserver-1, repo2 on gitserver-2
+	// and gitserver-3. gitserver-3 fails every request.
+	for name, fail := range map[string]func() (*http.Response, error){
+		"unavailable": func() (*http.Response, error) {
+			return nil, errors.New("dial tcp gitserver-3: connection refused")
+		},
+		"server error": func() (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []string
+			cli := &gitserver.Client{
+				Addrs:    func() []string { return []string{"gitserver-1", "gitserver-2", "gitserver-3"} },
+				Replicas: func() int { return 2 },
+				HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+					mu.Lock()
+					requests = append(requests, r.URL.String())
+					mu.Unlock()
+
+					if r.URL.Host == "gitserver-3" {
+						return fail()
+					}
+					var req struct{ Repos []api.RepoName }
+					if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+						return nil, err
+					}
+					results := map[api.RepoName]interface{}{}
+					for _, repo := range req.Repos {
+						results[repo] = map[string]bool{"Cloned": true}
+					}
+					body, _ := json.Marshal(map[string]interface{}{"Results": results})
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}, nil
+				}),
+			}
+
+			info, err := cli.RepoInfo(ctx, "repo1", "repo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, repo := range []api.RepoName{"repo1", "repo2"} {
+				if info.Results[repo] == nil || !info.Results[repo].Cloned {
+					t.Errorf("want info of %s from a replica, got %+v", repo, info.Results[repo])
+				}
+			}
+
+			progress, err := cli.RepoCloneProgress(ctx, "repo1", "repo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, repo := range []api.RepoName{"repo1", "repo2"} {
+				if progress.Results[repo] == nil || !progress.Results[repo].Cloned {
+					t.Errorf("want clone progress of %s from a replica, got %+v", repo, progress.Results[repo])
+				}
+			}
+
+			sort.Strings(requests)
+			want := []string{
+				"http://gitserver-1/repo-clone-progress",
+				"http://gitserver-1/repos",
+				"http://gitserver-2/repo-clone-progress",
+				"http://gitserver-2/repos",
+			}
+			if name == "server error" {
+				// Server errors don't make the gitserver unhealthy, so it is
+				// tried first again.
+				want = append(want, "http://gitserver-3/repo-clone-progress", "http://gitserver-3/repos")
+			} else {
+				want = append(want, "http://gitserver-3/repos")
+			}
+			if diff := cmp.Diff(want, requests); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReverseProxy_ServeHTTPFailover(t *testing.T) {
+	replica := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.URL.Path, body)
+	}))
+	defer replica.Close()
+	replicaAddr := strings.TrimPrefix(replica.URL, "http://")
+
+	// Nothing listens on the address of the unavailable primary anymore.
+	primary := httptest.NewServer(http.NotFoundHandler())
+	primaryAddr := strings.TrimPrefix(primary.URL, "http://")
+	primary.Close()
+
+	cli := &gitserver.Client{
+		Addrs:    func() []string { return []string{primaryAddr, replicaAddr} },
+		Replicas: func() int { return 2 },
+	}
+	repo := api.RepoName("repo0")
+	for i := 1; cli.AddrForRepo(repo) != primaryAddr; i++ {
+		repo = api.RepoName(fmt.Sprintf("repo%d", i))
+	}
+
+	proxy := gitserver.NewReverseProxy(http.DefaultTransport, nil)
+	director := func(req *http.Request, addr string) {
+		req.URL.Scheme = "http"
+		req.URL.Host = addr
+		req.URL.Path = "/exec"
+		req.Body = io.NopCloser(strings.NewReader("payload"))
+		req.ContentLength = int64(len("payload"))
+	}
+	serve := func(failover bool) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTPFailover(cli, repo, "POST", "exec", failover, director, rec, httptest.NewRequest("POST", "/git/exec", nil))
+		return rec
+	}
+
+	if rec := serve(true); rec.Code != http.StatusOK || rec.Body.String() != "/exec payload" {
+		t.Fatalf("want request served by the replica, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Requests which may change the repo are only sent to the primary.
+	if rec := serve(false); rec.Code != http.StatusBadGateway {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestClient_RequestRepoUpdate_Replicated(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	cli := replicatedClient(&requests, "gitserver-3")
+
+	// The update succeeds as long as one replica was updated.
+	if _, err := cli.RequestRepoUpdate(ctx, "repo1", 0); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(requests)
+	want := []string{"http://gitserver-1/repo-update", "http://gitserver-3/repo-update"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Removing a repo also removes it from every replica, and fails if a
+	// replica is unavailable.
+	requests = nil
+	if err := cli.Remove(ctx, "repo1"); err == nil {
+		t.Fatal("want error if a replica is unavailable")
+	}
+	want = []string{"http://gitserver-3/delete", "http://gitserver-1/delete"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_ListCloned_Replicated(t *testing.T) {
+	// repo1 is stored on gitserver-3 and gitserver-1, repo2 on gitserver-2
+	// and gitserver-3.
+	cli := &gitserver.Client{
+		Addrs:    func() []string { return []string{"gitserver-1", "gitserver-2", "gitserver-3"} },
+		Replicas: func() int { return 2 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "http://gitserver-1/list?cloned":
+				return &http.Response{
+					Body: io.NopCloser(bytes.NewBufferString(`["repo1", "repo2"]`)),
+				}, nil
+			case "http://gitserver-2/list?cloned":
+				return &http.Response{
+					Body: io.NopCloser(bytes.NewBufferString(`["repo2"]`)),
+				}, nil
+			case "http://gitserver-3/list?cloned":
+				return &http.Response{
+					Body: io.NopCloser(bytes.NewBufferString(`["repo1", "repo2"]`)),
+				}, nil
+			default:
+				return nil, errors.Errorf("unexpected url: %s", r.URL.String())
+			}
+		}),
+	}
+
+	// The leftover clone of repo2 on gitserver-1 is ignored and every repo is
+	// only listed once.
+	want := []string{"repo1", "repo2"}
+	got, err := cli.ListCloned(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if !cmp.Equal(want, got, cmpopts.EquateEmpty()) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
 func TestClient_P4Exec(t *testing.T) {
 	root, err := os.MkdirTemp("", t.Name())
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 
+	"github.com/inconshreveable/log15"
 	"github.com/neelance/parallel"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -56,3 +57,62 @@ func (p *ReverseProxy) ServeHTTP(repo api.RepoName, method, op string, director
 
 	proxy.ServeHTTP(res, req)
 }
+
+// ServeHTTPFailover is like ServeHTTP, but resolves the gitserver address of
+// repo with c. If failover is true, the request is sent to the gitservers of
+// ReadAddrsForRepo in order until one can be reached, so it must only be true
+// for requests which don't change the repo. Otherwise it is only sent to the
+// primary of repo. The director is called for each attempt with the address of
+// the gitserver, so it must set a fresh request body every time.
+func (p *ReverseProxy) ServeHTTPFailover(c *Client, repo api.RepoName, method, op string, failover bool, director func(req *http.Request, addr string), res http.ResponseWriter, req *http.Request) {
+	span, _ := ot.StartSpanFromContext(req.Context(), "ReverseProxy.ServeHTTPFailover")
+	defer func() {
+		span.LogKV("repo", string(repo), "method", method, "op", op)
+		span.Finish()
+	}()
+
+	if p.HTTPLimiter != nil {
+		p.HTTPLimiter.Acquire()
+		defer p.HTTPLimiter.Release()
+		span.LogKV("event", "Acquired HTTP limiter")
+	}
+
+	addrs := c.AddrsForRepo(repo)[:1]
+	if failover {
+		addrs = c.ReadAddrsForRepo(repo)
+	}
+	for i, addr := range addrs {
+		addr := addr
+		last := i == len(addrs)-1
+		var unavailable error
+		proxy := &httputil.ReverseProxy{
+			Director:  func(req *http.Request) { director(req, addr) },
+			Transport: p.Transport,
+			// The error handler is only called before anything was written
+			// to res, so the request can still be sent to the next replica.
+			ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+				unavailable = err
+				if last || req.Context().Err() != nil {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			},
+		}
+
+		proxy.ServeHTTP(res, req)
+		if unavailable == nil {
+			c.health.markHealthy(addr)
+			return
+		}
+		// The gitserver isn't to blame if the request was canceled.
+		if req.Context().Err() != nil {
+			return
+		}
+
+		c.health.markUnhealthy(addr)
+		if !last {
+			replicaFailoverCounter.Inc()
+			span.LogKV("event", "failover", "addr", addr)
+			log15.Warn("gitserver unavailable, failing over to the next replica", "repo", repo, "addr", addr, "error", unavailable)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package repos
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
@@ -14,9 +15,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/jvmpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/phabricator"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -67,10 +71,26 @@ func CloneURL(kind, config string, repo *types.Repo) (string, error) {
 		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
 			return otherCloneURL(repo, r), nil
 		}
+	case *schema.MercurialConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return mercurialCloneURL(repo, r, t), nil
+		}
 	case *schema.JVMPackagesConnection:
 		if r, ok := repo.Metadata.(*jvmpackages.Metadata); ok {
 			return r.Module.CloneURL(), nil
 		}
+	case *schema.NpmPackagesConnection:
+		if r, ok := repo.Metadata.(*npmpackages.Metadata); ok {
+			return r.Package.CloneURL(), nil
+		}
+	case *schema.GoModulesConnection:
+		if r, ok := repo.Metadata.(*gomodules.Metadata); ok {
+			return r.Module.CloneURL(), nil
+		}
+	case *schema.PythonPackagesConnection:
+		if r, ok := repo.Metadata.(*pythonpackages.Metadata); ok {
+			return r.Package.CloneURL(), nil
+		}
 	default:
 		return "", errors.Errorf("unknown external service kind %q for repo %d", kind, repo.ID)
 	}
@@ -257,3 +277,18 @@ func phabricatorCloneURL(repo *phabricator.Repo, _ *schema.PhabricatorConnection
 func otherCloneURL(repo *types.Repo, m *extsvc.OtherRepoMetadata) string {
 	return repo.ExternalRepo.ServiceID + m.RelativePath
 }
+
+// mercurialCloneURL composes the clone URL of a Mercurial repository, adding
+// the credentials of the connection's clone base URL.
+func mercurialCloneURL(repo *types.Repo, m *extsvc.OtherRepoMetadata, cfg *schema.MercurialConnection) string {
+	cloneURL := otherCloneURL(repo, m)
+	u, err := url.Parse(cloneURL)
+	if err != nil {
+		log15.Warn("Error adding authentication to Mercurial repository remote URL.", "url", cloneURL, "error", err)
+		return cloneURL
+	}
+	if base, err := url.Parse(cfg.Url); err == nil && base.User != nil && strings.EqualFold(base.Host, u.Host) {
+		u.User = base.User
+	}
+	return u.String()
+}
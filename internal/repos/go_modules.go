@@ -0,0 +1,163 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GoModulesSource creates git repositories from the zips of Go modules
+// served by Go module proxies.
+type GoModulesSource struct {
+	svc    *types.ExternalService
+	config *schema.GoModulesConnection
+	client *goproxy.Client
+}
+
+// NewGoModulesSource returns a new GoModulesSource from the given external
+// service.
+func NewGoModulesSource(svc *types.ExternalService, cf *httpcli.Factory) (*GoModulesSource, error) {
+	var c schema.GoModulesConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoModulesSource{
+		svc:    svc,
+		config: &c,
+		client: goproxy.NewClient(&c, cli),
+	}, nil
+}
+
+// ListRepos returns all Go modules configured in the external service.
+func (s *GoModulesSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	modules, err := GoModules(*s.config)
+	if err != nil {
+		results <- SourceResult{Err: err}
+		return
+	}
+	for _, module := range modules {
+		results <- SourceResult{
+			Source: s,
+			Repo:   s.makeRepo(module),
+		}
+	}
+}
+
+func (s *GoModulesSource) GetRepo(ctx context.Context, modulePath string) (*types.Repo, error) {
+	module, err := reposource.ParseGoModuleFromRepoURL(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := GoDependencies(*s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	nonExistentDependencies := make([]reposource.GoDependency, 0)
+	hasAtLeastOneValidDependency := false
+	for _, dep := range dependencies {
+		if dep.GoModule != module {
+			continue
+		}
+		if _, err := s.client.GetVersion(ctx, dep); err != nil {
+			nonExistentDependencies = append(nonExistentDependencies, dep)
+			continue
+		}
+		hasAtLeastOneValidDependency = true
+	}
+
+	if !hasAtLeastOneValidDependency {
+		return nil, &goDependencyNotFound{
+			dependencies: nonExistentDependencies,
+		}
+	}
+
+	for _, nonExistentDependency := range nonExistentDependencies {
+		// Don't reject all versions if a single version fails to resolve.
+		log15.Warn("Skipping non-existing Go module", "nonExistentDependency", nonExistentDependency.PackageManagerSyntax())
+	}
+
+	return s.makeRepo(module), nil
+}
+
+type goDependencyNotFound struct {
+	dependencies []reposource.GoDependency
+}
+
+func (e *goDependencyNotFound) Error() string {
+	return fmt.Sprintf("not found: go dependency '%v'", e.dependencies)
+}
+
+func (s *GoModulesSource) makeRepo(module reposource.GoModule) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: module.RepoName(),
+		URI:  string(module.RepoName()),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(module.RepoName()),
+			ServiceID:   extsvc.TypeGoModules,
+			ServiceType: extsvc.TypeGoModules,
+		},
+		Private: false,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: module.CloneURL(),
+			},
+		},
+		Metadata: &gomodules.Metadata{
+			Module: module,
+		},
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GoModulesSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func GoDependencies(connection schema.GoModulesConnection) (dependencies []reposource.GoDependency, err error) {
+	for _, dep := range connection.Dependencies {
+		dependency, err := reposource.ParseGoDependency(dep)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing Go dependency")
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func GoModules(connection schema.GoModulesConnection) ([]reposource.GoModule, error) {
+	isAdded := make(map[reposource.GoModule]bool)
+	modules := []reposource.GoModule{}
+	dependencies, err := GoDependencies(connection)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range dependencies {
+		if !isAdded[dep.GoModule] {
+			modules = append(modules, dep.GoModule)
+		}
+		isAdded[dep.GoModule] = true
+	}
+	return modules, nil
+}
@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A MercurialSource yields repositories from a single Mercurial connection
+// configured in Sourcegraph via the external services configuration. The
+// repositories are converted to Git repositories by gitserver.
+type MercurialSource struct {
+	svc  *types.ExternalService
+	conn *schema.MercurialConnection
+}
+
+// NewMercurialSource returns a new MercurialSource from the given external
+// service.
+func NewMercurialSource(svc *types.ExternalService) (*MercurialSource, error) {
+	var c schema.MercurialConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &MercurialSource{svc: svc, conn: &c}, nil
+}
+
+// ListRepos returns all Mercurial repositories of the connection.
+func (s MercurialSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := url.Parse(s.conn.Url)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+	// Repository paths are relative to the path of the base URL, even if it
+	// doesn't end with a slash.
+	base = extsvc.NormalizeBaseURL(base)
+
+	urn := s.svc.URN()
+	for _, repo := range s.conn.Repos {
+		cloneURL, err := base.Parse(repo)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		r, err := s.mercurialRepoFromCloneURL(urn, cloneURL)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s MercurialSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s MercurialSource) mercurialRepoFromCloneURL(urn string, u *url.URL) (*types.Repo, error) {
+	repoURL := u.String()
+	repoSource := reposource.Mercurial{MercurialConnection: s.conn}
+	repoName, err := repoSource.CloneURLToRepoName(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if repoName == "" {
+		return nil, errors.Errorf("Mercurial repository %q is not below the clone base URL", repoURL)
+	}
+	repoURI, err := repoSource.CloneURLToRepoURI(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The service ID and metadata don't contain the credentials of the clone
+	// URL, since they are visible to all users. The credentials are added
+	// back from the connection when gitserver asks for the clone URL.
+	withoutCredentials := *u
+	withoutCredentials.User = nil
+	service := url.URL{Scheme: u.Scheme, Host: u.Host}
+	serviceID := service.String()
+
+	return &types.Repo{
+		Name: repoName,
+		URI:  repoURI,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(repoName),
+			ServiceType: extsvc.TypeMercurial,
+			ServiceID:   serviceID,
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: repoURL,
+			},
+		},
+		Metadata: &extsvc.OtherRepoMetadata{
+			RelativePath: strings.TrimPrefix(withoutCredentials.String(), serviceID),
+		},
+	}, nil
+}
@@ -0,0 +1,163 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pythonpackages/pypi"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A PythonPackagesSource creates git repositories from the distribution files
+// of Python packages served by Python package indexes.
+type PythonPackagesSource struct {
+	svc    *types.ExternalService
+	config *schema.PythonPackagesConnection
+	client *pypi.Client
+}
+
+// NewPythonPackagesSource returns a new PythonPackagesSource from the given
+// external service.
+func NewPythonPackagesSource(svc *types.ExternalService, cf *httpcli.Factory) (*PythonPackagesSource, error) {
+	var c schema.PythonPackagesConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PythonPackagesSource{
+		svc:    svc,
+		config: &c,
+		client: pypi.NewClient(&c, cli),
+	}, nil
+}
+
+// ListRepos returns all Python packages configured in the external service.
+func (s *PythonPackagesSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	packages, err := PythonPackages(*s.config)
+	if err != nil {
+		results <- SourceResult{Err: err}
+		return
+	}
+	for _, pkg := range packages {
+		results <- SourceResult{
+			Source: s,
+			Repo:   s.makeRepo(pkg),
+		}
+	}
+}
+
+func (s *PythonPackagesSource) GetRepo(ctx context.Context, packagePath string) (*types.Repo, error) {
+	pkg, err := reposource.ParsePythonPackageFromRepoURL(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := PythonDependencies(*s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	nonExistentDependencies := make([]reposource.PythonDependency, 0)
+	hasAtLeastOneValidDependency := false
+	for _, dep := range dependencies {
+		if dep.PythonPackage != pkg {
+			continue
+		}
+		if _, err := s.client.Version(ctx, dep); err != nil {
+			nonExistentDependencies = append(nonExistentDependencies, dep)
+			continue
+		}
+		hasAtLeastOneValidDependency = true
+	}
+
+	if !hasAtLeastOneValidDependency {
+		return nil, &pythonDependencyNotFound{
+			dependencies: nonExistentDependencies,
+		}
+	}
+
+	for _, nonExistentDependency := range nonExistentDependencies {
+		// Don't reject all versions if a single version fails to resolve.
+		log15.Warn("Skipping non-existing Python package", "nonExistentDependency", nonExistentDependency.PackageManagerSyntax())
+	}
+
+	return s.makeRepo(pkg), nil
+}
+
+type pythonDependencyNotFound struct {
+	dependencies []reposource.PythonDependency
+}
+
+func (e *pythonDependencyNotFound) Error() string {
+	return fmt.Sprintf("not found: python dependency '%v'", e.dependencies)
+}
+
+func (s *PythonPackagesSource) makeRepo(pkg reposource.PythonPackage) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: pkg.RepoName(),
+		URI:  string(pkg.RepoName()),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(pkg.RepoName()),
+			ServiceID:   extsvc.TypePythonPackages,
+			ServiceType: extsvc.TypePythonPackages,
+		},
+		Private: false,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: pkg.CloneURL(),
+			},
+		},
+		Metadata: &pythonpackages.Metadata{
+			Package: pkg,
+		},
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *PythonPackagesSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func PythonDependencies(connection schema.PythonPackagesConnection) (dependencies []reposource.PythonDependency, err error) {
+	for _, dep := range connection.Dependencies {
+		dependency, err := reposource.ParsePythonDependency(dep)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing Python dependency")
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func PythonPackages(connection schema.PythonPackagesConnection) ([]reposource.PythonPackage, error) {
+	isAdded := make(map[reposource.PythonPackage]bool)
+	packages := []reposource.PythonPackage{}
+	dependencies, err := PythonDependencies(connection)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range dependencies {
+		if !isAdded[dep.PythonPackage] {
+			packages = append(packages, dep.PythonPackage)
+		}
+		isAdded[dep.PythonPackage] = true
+	}
+	return packages, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/sourcegraph/sourcegraph/internal/honey"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
@@ -175,6 +176,11 @@ func (m *meteredSearcher) StreamSearch(ctx context.Context, q query.Q, opts *zoe
 		code = "error"
 	}
 
+	if !isLeaf {
+		// The aggregator sums up the shards of all Zoekt replicas.
+		explain.AddShards(ctx, statsAgg.ShardsScanned, statsAgg.ShardsSkipped+statsAgg.ShardsSkippedFilter)
+	}
+
 	fields := []log.Field{
 		log.Int("filematches", nFilesMatches),
 		log.Int("events", nEvents),
@@ -0,0 +1,234 @@
+// Package codeowners parses CODEOWNERS files and resolves the owners of paths
+// in a repository.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Paths are the locations at which a CODEOWNERS file is looked up, in order
+// of precedence. This matches the locations GitHub and GitLab support, and
+// must be kept in sync with query.CodeownersPathPattern.
+var Paths = []string{
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// maxFileSize is the maximum number of bytes of a CODEOWNERS file that we
+// read. GitHub ignores CODEOWNERS files larger than 3MB.
+const maxFileSize = 3 * 1024 * 1024
+
+// Rule is a single line of a CODEOWNERS file, associating a path pattern with
+// a list of owners.
+type Rule struct {
+	// Pattern is the gitignore style pattern as written in the file.
+	Pattern string
+
+	// Owners are the owner handles or emails as written in the file.
+	Owners []string
+
+	re *regexp.Regexp
+}
+
+// PathRegexp returns a regular expression which matches the paths the
+// pattern of r applies to. The expression is valid as a file: filter.
+func (r *Rule) PathRegexp() string {
+	return r.re.String()
+}
+
+// Match returns true if r applies to path.
+func (r *Rule) Match(path string) bool {
+	return r.re.MatchString(path)
+}
+
+// OwnedBy returns true if owner is listed as an owner of r. The comparison
+// is case insensitive and ignores a leading "@".
+func (r *Rule) OwnedBy(owner string) bool {
+	for _, o := range r.Owners {
+		if sameOwner(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameOwner(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(a, "@"), strings.TrimPrefix(b, "@"))
+}
+
+// Ruleset is a parsed CODEOWNERS file. Rules are ordered as they appear in
+// the file, and the last rule matching a path determines its owners.
+type Ruleset struct {
+	Rules []*Rule
+}
+
+// Parse parses the contents of a CODEOWNERS file. Comments, blank lines and
+// GitLab section headers are skipped.
+func Parse(r io.Reader) (*Ruleset, error) {
+	rs := &Ruleset{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		re, err := patternToRegexp(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		rs.Rules = append(rs.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			re:      re,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// Match returns the rule which determines the owners of path, or nil if no
+// rule applies.
+func (rs *Ruleset) Match(path string) *Rule {
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].Match(path) {
+			return rs.Rules[i]
+		}
+	}
+	return nil
+}
+
+// FindOwners returns the owners of path. It returns nil if path has no
+// owners.
+func (rs *Ruleset) FindOwners(path string) []string {
+	if rule := rs.Match(path); rule != nil {
+		return rule.Owners
+	}
+	return nil
+}
+
+// OwnedPathRegexps describes the paths owned by owner as a list of
+// conjunctions of path regular expressions. A path is owned by owner if it
+// satisfies any of the returned conjunctions. Each conjunction is a regexp
+// the path must match, followed by regexps the path must not match because
+// a later rule assigns them to someone else.
+func (rs *Ruleset) OwnedPathRegexps(owner string) [][]string {
+	var result [][]string
+	for i, rule := range rs.Rules {
+		if !rule.OwnedBy(owner) {
+			continue
+		}
+		conjunction := []string{rule.PathRegexp()}
+		for _, later := range rs.Rules[i+1:] {
+			if !later.OwnedBy(owner) {
+				conjunction = append(conjunction, later.PathRegexp())
+			}
+		}
+		result = append(result, conjunction)
+	}
+	return result
+}
+
+// patternToRegexp converts a gitignore style CODEOWNERS pattern to a regular
+// expression matching repository relative paths.
+func patternToRegexp(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+
+	// A pattern containing a slash anywhere but at the end is relative to
+	// the root of the repository. Otherwise it matches at any depth.
+	anchored := strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	p = strings.TrimPrefix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return nil, errors.Errorf("invalid pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					// "**/" matches zero or more directories.
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(p[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// A pattern matching a directory applies to everything inside of it.
+	// The exception is a trailing "/*", which only matches direct children.
+	if strings.HasSuffix(p, "/*") && !strings.HasSuffix(p, "**/*") {
+		b.WriteString("$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
+
+// Load reads and parses the CODEOWNERS file of repo at commit, looking in
+// each of Paths in turn. It returns nil if the repository has no CODEOWNERS
+// file.
+func Load(ctx context.Context, repo api.RepoName, commit api.CommitID) (*Ruleset, error) {
+	for _, path := range Paths {
+		rs, err := LoadFile(ctx, repo, commit, path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return rs, err
+	}
+	return nil, nil
+}
+
+// LoadFile reads and parses the CODEOWNERS file at path in repo at commit.
+func LoadFile(ctx context.Context, repo api.RepoName, commit api.CommitID, path string) (*Ruleset, error) {
+	data, err := git.ReadFile(ctx, repo, commit, path, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(data))
+}
@@ -0,0 +1,149 @@
+package codeowners
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+# Global owners
+*       @global-owner
+
+*.js    @js-owner # inline comment
+/build/logs/ @doctocat
+docs/*  docs@example.com
+
+[Section]
+apps/   @octocat
+/scripts/ @doctocat @octocat
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var patterns []string
+	for _, rule := range rs.Rules {
+		patterns = append(patterns, rule.Pattern)
+	}
+	want := []string{"*", "*.js", "/build/logs/", "docs/*", "apps/", "/scripts/"}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Fatalf("got patterns %v, want %v", patterns, want)
+	}
+	if got := rs.Rules[1].Owners; !reflect.DeepEqual(got, []string{"@js-owner"}) {
+		t.Fatalf("got owners %v, want [@js-owner]", got)
+	}
+}
+
+func TestRuleset_FindOwners(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+*                @global-owner
+*.js             @js-owner
+/build/logs/     @doctocat
+docs/*           docs@example.com
+apps/            @octocat
+**/logs          @logger
+/src/**/test.go  @tester
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@global-owner"}},
+		{"main.js", []string{"@js-owner"}},
+		{"web/src/main.js", []string{"@js-owner"}},
+		{"build/logs/out.txt", []string{"@logger"}},
+		{"build/logs", []string{"@logger"}},
+		{"docs/getting-started.md", []string{"docs@example.com"}},
+		{"docs/build-app/troubleshooting.md", []string{"@global-owner"}},
+		{"apps/main.go", []string{"@octocat"}},
+		{"web/apps/main.go", []string{"@octocat"}},
+		{"deep/nested/logs/x", []string{"@logger"}},
+		{"src/test.go", []string{"@tester"}},
+		{"src/a/b/test.go", []string{"@tester"}},
+		{"other/src/test.go", []string{"@global-owner"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := rs.FindOwners(tc.path); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRuleset_OwnedPathRegexps(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+/src/         @team
+/src/vendor/  @vendors
+/src/vendor/ours/ @Team
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := rs.OwnedPathRegexps("team")
+	want := [][]string{
+		{`^src(?:/.*)?$`, `^src/vendor(?:/.*)?$`},
+		{`^src/vendor/ours(?:/.*)?$`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if got := rs.OwnedPathRegexps("@nobody"); got != nil {
+		t.Fatalf("expected no paths, got %q", got)
+	}
+}
+
+func TestWithOwners(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+/src/  @team
+/docs/ @docs
+/lib/  @Team
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := types.RepoName{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}
+	resolver := newOwnersResolver()
+	resolver.rulesets[repoCommit{repo: repo.Name, commit: "deadbeef"}] = rs
+
+	fileMatch := func(path string) result.Match {
+		return &result.FileMatch{File: result.File{Repo: repo, CommitID: "deadbeef", Path: path}}
+	}
+
+	var sent []map[string]int
+	stream := withOwners(context.Background(), streaming.StreamFunc(func(event streaming.SearchEvent) {
+		fileCounts := map[string]int{}
+		for _, m := range event.Results {
+			om := m.(*result.OwnerMatch)
+			fileCounts[om.Handle] = om.FileCount
+		}
+		sent = append(sent, fileCounts)
+	}), resolver)
+
+	stream.Send(streaming.SearchEvent{Results: []result.Match{fileMatch("src/a.go"), fileMatch("docs/index.md")}})
+	stream.Send(streaming.SearchEvent{Results: []result.Match{fileMatch("src/b.go"), fileMatch("lib/c.go"), fileMatch("README.md")}})
+
+	// Owners seen in earlier events are sent again with their total count.
+	// Handles differing only in case are the same owner.
+	want := []map[string]int{
+		{"@team": 1, "@docs": 1},
+		{"@team": 3},
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Fatalf("got %v, want %v", sent, want)
+	}
+}
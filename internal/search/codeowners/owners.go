@@ -0,0 +1,127 @@
+package codeowners
+
+import (
+	"context"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+type repoCommit struct {
+	repo   api.RepoName
+	commit api.CommitID
+}
+
+// ownersResolver maps file matches to owner matches. It caches the rulesets
+// it loads so that each CODEOWNERS file is only read once per search.
+type ownersResolver struct {
+	mu       sync.Mutex
+	rulesets map[repoCommit]*Ruleset
+}
+
+func newOwnersResolver() *ownersResolver {
+	return &ownersResolver{rulesets: make(map[repoCommit]*Ruleset)}
+}
+
+func (o *ownersResolver) ruleset(ctx context.Context, repo api.RepoName, commit api.CommitID) (*Ruleset, error) {
+	key := repoCommit{repo: repo, commit: commit}
+
+	o.mu.Lock()
+	rs, ok := o.rulesets[key]
+	o.mu.Unlock()
+	if ok {
+		return rs, nil
+	}
+
+	rs, err := Load(ctx, repo, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	o.rulesets[key] = rs
+	o.mu.Unlock()
+	return rs, nil
+}
+
+// ownerMatches returns an owner match for every owner of every file match in
+// matches. Matches of other types are dropped.
+func (o *ownersResolver) ownerMatches(ctx context.Context, matches []result.Match) ([]result.Match, error) {
+	dedup := result.NewDeduper()
+	for _, match := range matches {
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+
+		rs, err := o.ruleset(ctx, fm.Repo.Name, fm.CommitID)
+		if err != nil {
+			return nil, err
+		}
+		if rs == nil {
+			continue
+		}
+
+		for _, owner := range rs.FindOwners(fm.Path) {
+			dedup.Add(&result.OwnerMatch{
+				Handle:    owner,
+				Repo:      fm.Repo,
+				CommitID:  fm.CommitID,
+				InputRev:  fm.InputRev,
+				FileCount: 1,
+			})
+		}
+	}
+	return dedup.Results(), nil
+}
+
+// ToOwnerMatches replaces the file matches in matches with the aggregated
+// list of owners of those files, based on the CODEOWNERS file of each
+// repository at the searched revision. Matches of other types are dropped.
+func ToOwnerMatches(ctx context.Context, matches []result.Match) ([]result.Match, error) {
+	return newOwnersResolver().ownerMatches(ctx, matches)
+}
+
+// WithOwners returns a child Stream of parent which replaces file matches
+// with the owners of the matched files. An owner which was already sent for
+// files of earlier events is sent again with its updated FileCount, which is
+// the number of matched files it owns across all events so far. Consumers
+// should replace the owner matches they received before with the same key.
+func WithOwners(ctx context.Context, parent streaming.Sender) streaming.Sender {
+	return withOwners(ctx, parent, newOwnersResolver())
+}
+
+func withOwners(ctx context.Context, parent streaming.Sender, resolver *ownersResolver) streaming.Sender {
+	var mu sync.Mutex
+	totals := make(map[result.Key]*result.OwnerMatch)
+
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		owners, err := resolver.ownerMatches(ctx, event.Results)
+		if err != nil {
+			log15.Warn("codeowners: failed to resolve owners", "error", err)
+			owners = nil
+		}
+
+		mu.Lock()
+		for i, owner := range owners {
+			om := owner.(*result.OwnerMatch)
+			total, ok := totals[om.Key()]
+			if !ok {
+				total = &result.OwnerMatch{Handle: om.Handle, Repo: om.Repo, CommitID: om.CommitID, InputRev: om.InputRev}
+				totals[om.Key()] = total
+			}
+			total.AppendMatches(om)
+			// Send a copy, since we keep counting on total.
+			updated := *total
+			owners[i] = &updated
+		}
+		mu.Unlock()
+
+		event.Results = owners
+		parent.Send(event)
+	})
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 
+	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -67,6 +68,8 @@ func ResolveCommitParameters(ctx context.Context, tp *search.TextParameters) (*s
 	patternInfo := &search.CommitPatternInfo{
 		Pattern:                      old.Pattern,
 		IsRegExp:                     old.IsRegExp,
+		IsStructuralPat:              old.IsStructuralPat,
+		CombyRule:                    old.CombyRule,
 		IsCaseSensitive:              old.IsCaseSensitive,
 		FileMatchLimit:               old.FileMatchLimit,
 		IncludePatterns:              old.IncludePatterns,
@@ -92,7 +95,7 @@ func commitParametersToDiffParameters(ctx context.Context, db dbutil.DB, op *sea
 			"--unified=0",
 		)
 	}
-	if op.PatternInfo.IsRegExp {
+	if op.PatternInfo.IsRegExp || op.PatternInfo.IsStructuralPat {
 		args = append(args, "--extended-regexp")
 	}
 	if !op.Query.IsCaseSensitive() {
@@ -189,6 +192,15 @@ func commitParametersToDiffParameters(ctx context.Context, db dbutil.DB, op *sea
 		IsRegExp:        op.PatternInfo.IsRegExp,
 		IsCaseSensitive: op.PatternInfo.IsCaseSensitive,
 	}
+	onlyMatchingHunks := true
+	if op.PatternInfo.IsStructuralPat {
+		// git can't evaluate structural patterns. We only ask it for the
+		// commits which add or remove a literal of the pattern, and run comby
+		// over the whole hunks afterwards.
+		textSearchOptions.Pattern = regexp.QuoteMeta(comby.LongestLiteral(op.PatternInfo.Pattern))
+		textSearchOptions.IsRegExp = true
+		onlyMatchingHunks = false
+	}
 	return &search.DiffParameters{
 		Repo: op.RepoRevs.GitserverRepo(),
 		Options: git.RawLogDiffSearchOptions{
@@ -200,7 +212,7 @@ func commitParametersToDiffParameters(ctx context.Context, db dbutil.DB, op *sea
 				IsRegExp:        op.PatternInfo.PathPatternsAreRegExps,
 			},
 			Diff:              op.Diff,
-			OnlyMatchingHunks: true,
+			OnlyMatchingHunks: onlyMatchingHunks,
 			Args:              args,
 		},
 	}, nil
@@ -241,6 +253,13 @@ func searchCommitsInRepoStream(ctx context.Context, db dbutil.DB, op search.Comm
 	for event := range events {
 		timedOut = timedOut || !event.Complete || ctx.Err() == context.DeadlineExceeded
 
+		if op.PatternInfo.IsStructuralPat && op.Diff {
+			event.Results, err = structuralDiffMatches(ctx, op.PatternInfo, event.Results)
+			if err != nil {
+				return errors.Wrapf(err, "failed to search commit diffs %s", op.RepoRevs.String())
+			}
+		}
+
 		results = logCommitSearchResultsToMatches(&op, op.RepoRevs.Repo, event.Results)
 		if len(results) > 0 {
 			resultCount += len(event.Results)
@@ -0,0 +1,207 @@
+package commit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// hunkSide is the text of the added or the removed lines of a single hunk in
+// a diff.
+type hunkSide struct {
+	// result is the index of the commit search result the hunk belongs to.
+	result int
+
+	// path is the path of the changed file, used by comby to infer the
+	// language of the hunk.
+	path string
+
+	// lines are the 0-indexed lines in the raw diff of each line of text.
+	lines []int
+
+	text []string
+}
+
+// diffHunkSides splits a raw diff into the added and removed sides of each
+// of its hunks. Structural patterns are matched against each side on its
+// own, so that a match never spans old and new code.
+func diffHunkSides(rawDiff string) []hunkSide {
+	var (
+		sides             []hunkSide
+		origPath, newPath string
+		inHeader          bool
+		removed, added    hunkSide
+	)
+
+	flush := func() {
+		for _, side := range []*hunkSide{&removed, &added} {
+			if len(side.lines) > 0 {
+				sides = append(sides, *side)
+			}
+			*side = hunkSide{}
+		}
+	}
+
+	appendLine := func(side *hunkSide, i int, line string) {
+		if len(side.lines) == 0 {
+			side.path = newPath
+			if side.path == "" || side.path == "/dev/null" {
+				side.path = origPath
+			}
+		}
+		side.lines = append(side.lines, i)
+		side.text = append(side.text, line[1:])
+	}
+
+	for i, line := range strings.Split(rawDiff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			flush()
+			inHeader = true
+			origPath, newPath = "", ""
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHeader = false
+		case inHeader && strings.HasPrefix(line, "--- "):
+			origPath = strings.TrimPrefix(line, "--- ")
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			newPath = strings.TrimPrefix(line, "+++ ")
+		case inHeader:
+			// Other extended header lines, such as "index" or "new file mode".
+		case strings.HasPrefix(line, "-"):
+			appendLine(&removed, i, line)
+		case strings.HasPrefix(line, "+"):
+			appendLine(&added, i, line)
+		}
+	}
+	flush()
+	return sides
+}
+
+// combyFileName is the name of the file hunk side i is written to. It keeps
+// the extension of the changed file so that comby picks the right matcher.
+func combyFileName(i int, path string) string {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		ext = ".txt"
+	}
+	return strconv.Itoa(i) + ext
+}
+
+// structuralDiffMatches runs comby with the structural pattern of p over the
+// added and removed lines of the diffs in results. It returns the results
+// which have at least one match, with their highlights replaced by the
+// ranges comby matched.
+func structuralDiffMatches(ctx context.Context, p *search.CommitPatternInfo, results []*git.LogCommitSearchResult) ([]*git.LogCommitSearchResult, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "structural-diff-search-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var sides []hunkSide
+	for i, r := range results {
+		if r.Diff == nil {
+			continue
+		}
+		for _, side := range diffHunkSides(r.Diff.Raw) {
+			side.result = i
+			name := combyFileName(len(sides), side.path)
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Join(side.text, "\n")), 0600); err != nil {
+				return nil, err
+			}
+			sides = append(sides, side)
+		}
+	}
+	if len(sides) == 0 {
+		return nil, nil
+	}
+
+	fileMatches, err := comby.Matches(ctx, comby.Args{
+		Input:         comby.DirPath(dir),
+		MatchTemplate: p.Pattern,
+		Rule:          p.CombyRule,
+		MatchOnly:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	highlights := make(map[int][]git.Highlight, len(results))
+	for _, fm := range fileMatches {
+		name := filepath.Base(fm.URI)
+		i, err := strconv.Atoi(strings.TrimSuffix(name, filepath.Ext(name)))
+		if err != nil || i < 0 || i >= len(sides) {
+			return nil, errors.Errorf("unexpected comby result for file %q", fm.URI)
+		}
+		side := sides[i]
+		for _, m := range fm.Matches {
+			highlights[side.result] = append(highlights[side.result], side.highlights(m.Range)...)
+		}
+	}
+
+	matching := results[:0]
+	for i, r := range results {
+		if hs, ok := highlights[i]; ok {
+			r.DiffHighlights = hs
+			matching = append(matching, r)
+		}
+	}
+	return matching, nil
+}
+
+// highlights converts a range comby matched in the text of s to highlights
+// in the raw diff, one per line the range spans. Like the highlights
+// computed by git diff search, lines are 1-indexed and characters are
+// 0-indexed including the leading "+" or "-" of the diff line.
+func (s hunkSide) highlights(r comby.Range) []git.Highlight {
+	var highlights []git.Highlight
+	for line := r.Start.Line; line <= r.End.Line; line++ {
+		if line < 1 || line > len(s.text) {
+			continue
+		}
+		text := s.text[line-1]
+
+		start, end := 0, len(text)
+		if line == r.Start.Line {
+			start = clampColumn(r.Start.Column-1, text)
+		}
+		if line == r.End.Line {
+			end = clampColumn(r.End.Column-1, text)
+		}
+		if start >= end {
+			continue
+		}
+
+		highlights = append(highlights, git.Highlight{
+			Line:      s.lines[line-1] + 1,
+			Character: utf8.RuneCountInString(text[:start]) + 1,
+			Length:    utf8.RuneCountInString(text[start:end]),
+		})
+	}
+	return highlights
+}
+
+// clampColumn bounds the 0-indexed byte column c to the length of text.
+func clampColumn(c int, text string) int {
+	if c < 0 {
+		return 0
+	}
+	if c > len(text) {
+		return len(text)
+	}
+	return c
+}
@@ -0,0 +1,70 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestDiffHunkSides(t *testing.T) {
+	raw := "diff --git a.go a.go\n" +
+		"index d8649da..1193ff4 100644\n" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -3,1 +3,2 @@\n" +
+		"-f.Close()\n" +
+		"+defer f.Close()\n" +
+		"+return nil\n" +
+		"diff --git b.py b.py\n" +
+		"deleted file mode 100644\n" +
+		"index d8649da..0000000\n" +
+		"--- b.py\n" +
+		"+++ /dev/null\n" +
+		"@@ -1,1 +0,0 @@\n" +
+		"-print(1)\n"
+
+	got := diffHunkSides(raw)
+	want := []hunkSide{
+		{path: "a.go", lines: []int{5}, text: []string{"f.Close()"}},
+		{path: "a.go", lines: []int{6, 7}, text: []string{"defer f.Close()", "return nil"}},
+		{path: "b.py", lines: []int{14}, text: []string{"print(1)"}},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(hunkSide{})); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestHunkSideHighlights(t *testing.T) {
+	side := hunkSide{
+		lines: []int{6, 7},
+		text:  []string{"defer f.Close()", "return nil"},
+	}
+
+	t.Run("single line", func(t *testing.T) {
+		got := side.highlights(comby.Range{
+			Start: comby.Location{Line: 1, Column: 1},
+			End:   comby.Location{Line: 1, Column: 16},
+		})
+		want := []git.Highlight{{Line: 7, Character: 1, Length: 15}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("multiple lines", func(t *testing.T) {
+		got := side.highlights(comby.Range{
+			Start: comby.Location{Line: 1, Column: 7},
+			End:   comby.Location{Line: 2, Column: 7},
+		})
+		want := []git.Highlight{
+			{Line: 7, Character: 7, Length: 9},
+			{Line: 8, Character: 1, Length: 6},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
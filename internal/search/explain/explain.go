@@ -0,0 +1,185 @@
+// Package explain records how a search is evaluated for queries containing
+// explain:yes. It records a tree of the plan the query is transformed to and
+// the jobs which ran for it, with what each job searched, how long it took and
+// whether it hit a limit.
+//
+// The tree is returned to the user in the search response, so it is kept in
+// memory for the duration of the search rather than derived from
+// observation operations or trace spans: those are only emitted to logs,
+// metrics and the tracer, which may be disabled or sampled, and search jobs
+// are instrumented with trace.New rather than observation operations. The
+// nodes also carry search-specific counts, like shards and timed out
+// repositories, which have no counterpart in observation.Args.
+package explain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// Node is a node of the tree explaining a search. The root node is the plan
+// of the query. Its descendants are the queries of the plan and the jobs
+// which evaluate them.
+//
+// The methods of Node are safe for concurrent use, and are no-ops on a nil
+// Node, so callers don't need to check whether a search is explained. The
+// exported fields are set by Finish.
+type Node struct {
+	// Name is the name of the plan node or job, like "Zoekt" or "Searcher".
+	Name string `json:"name"`
+
+	// Query is the query the node evaluates, if any.
+	Query string `json:"query,omitempty"`
+
+	DurationMs    int64  `json:"durationMs"`
+	Repositories  int    `json:"repositories"`
+	Shards        int    `json:"shards"`
+	SkippedShards int    `json:"skippedShards"`
+	Matches       int    `json:"matches"`
+	Timedout      int    `json:"timedout"`
+	LimitHit      bool   `json:"limitHit"`
+	Error         string `json:"error,omitempty"`
+
+	Children []*Node `json:"children"`
+
+	mu           sync.Mutex
+	start        time.Time
+	repositories int
+	repos        map[api.RepoID]struct{}
+	timedout     map[api.RepoID]struct{}
+}
+
+func newNode(name, query string) *Node {
+	return &Node{
+		Name:     name,
+		Query:    query,
+		Children: []*Node{},
+		start:    time.Now(),
+		repos:    make(map[api.RepoID]struct{}),
+		timedout: make(map[api.RepoID]struct{}),
+	}
+}
+
+type contextKey struct{}
+
+// New returns the root node of the tree explaining a search of query, and a
+// child context of ctx which records the jobs started with it below the root.
+func New(ctx context.Context, name, query string) (context.Context, *Node) {
+	n := newNode(name, query)
+	return context.WithValue(ctx, contextKey{}, n), n
+}
+
+// FromContext returns the node ctx records jobs below, or nil if the search
+// is not explained.
+func FromContext(ctx context.Context) *Node {
+	n, _ := ctx.Value(contextKey{}).(*Node)
+	return n
+}
+
+// Start records a job named name as a child of the node of ctx, and returns a
+// child context of ctx which records jobs below the new node. If the search
+// is not explained, Start returns ctx and a nil Node.
+func Start(ctx context.Context, name, query string) (context.Context, *Node) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+
+	n := newNode(name, query)
+	parent.mu.Lock()
+	parent.Children = append(parent.Children, n)
+	parent.mu.Unlock()
+	return context.WithValue(ctx, contextKey{}, n), n
+}
+
+// AddShards records that a Zoekt search of the node of ctx scanned and
+// skipped shards.
+func AddShards(ctx context.Context, scanned, skipped int) {
+	n := FromContext(ctx)
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	n.Shards += scanned
+	n.SkippedShards += skipped
+	n.mu.Unlock()
+}
+
+// AddMatches records that the job found count matches.
+func (n *Node) AddMatches(count int) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	n.Matches += count
+	n.mu.Unlock()
+}
+
+// AddRepos records that the job searched the repositories ids.
+func (n *Node) AddRepos(ids ...api.RepoID) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	for _, id := range ids {
+		n.repos[id] = struct{}{}
+	}
+	n.mu.Unlock()
+}
+
+// AddTimedout records that the job timed out searching the repositories ids.
+func (n *Node) AddTimedout(ids ...api.RepoID) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	for _, id := range ids {
+		n.timedout[id] = struct{}{}
+	}
+	n.mu.Unlock()
+}
+
+// SetRepositories records the number of repositories the job searches, if it
+// is known before the job runs.
+func (n *Node) SetRepositories(count int) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	n.repositories = count
+	n.mu.Unlock()
+}
+
+// SetLimitHit records that the job did not find all matches because it hit a
+// limit.
+func (n *Node) SetLimitHit() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	n.LimitHit = true
+	n.mu.Unlock()
+}
+
+// Finish records the duration of the job and the error it failed with, if
+// any. It should be called once, when the job and its children are done.
+func (n *Node) Finish(err error) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.DurationMs = time.Since(n.start).Milliseconds()
+	n.Repositories = n.repositories
+	if len(n.repos) > n.Repositories {
+		n.Repositories = len(n.repos)
+	}
+	n.Timedout = len(n.timedout)
+	if err != nil {
+		n.Error = err.Error()
+	}
+}
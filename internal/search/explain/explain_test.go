@@ -0,0 +1,62 @@
+package explain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestExplain(t *testing.T) {
+	ctx, root := New(context.Background(), "Plan", "foo")
+
+	textCtx, text := Start(ctx, "Text", "")
+	text.SetRepositories(3)
+
+	zoektCtx, zoekt := Start(textCtx, "Zoekt", "")
+	AddShards(zoektCtx, 5, 1)
+	zoekt.AddMatches(2)
+	zoekt.AddRepos(1, 2, 1)
+	zoekt.AddTimedout(2)
+	zoekt.SetLimitHit()
+	zoekt.Finish(nil)
+
+	_, searcher := Start(textCtx, "Searcher", "")
+	searcher.Finish(errors.New("boom"))
+	text.Finish(nil)
+	root.Finish(nil)
+
+	if len(root.Children) != 1 || root.Children[0] != text {
+		t.Fatalf("expected the text job to be the only child of the root, got %v", root.Children)
+	}
+	if len(text.Children) != 2 || text.Children[0] != zoekt || text.Children[1] != searcher {
+		t.Fatalf("expected the Zoekt and searcher jobs to be the children of the text job, got %v", text.Children)
+	}
+	if text.Repositories != 3 {
+		t.Errorf("got %d repositories for the text job, want 3", text.Repositories)
+	}
+	if zoekt.Repositories != 2 || zoekt.Timedout != 1 || zoekt.Matches != 2 || !zoekt.LimitHit {
+		t.Errorf("unexpected Zoekt job %+v", zoekt)
+	}
+	if zoekt.Shards != 5 || zoekt.SkippedShards != 1 {
+		t.Errorf("got %d shards and %d skipped shards for the Zoekt job, want 5 and 1", zoekt.Shards, zoekt.SkippedShards)
+	}
+	if searcher.Error != "boom" {
+		t.Errorf("got error %q for the searcher job, want boom", searcher.Error)
+	}
+}
+
+func TestExplain_Disabled(t *testing.T) {
+	ctx := context.Background()
+	jobCtx, n := Start(ctx, "Zoekt", "")
+	if n != nil || jobCtx != ctx {
+		t.Fatal("expected Start to be a no-op if the search is not explained")
+	}
+
+	// Methods of a nil Node are no-ops.
+	AddShards(jobCtx, 1, 0)
+	n.AddMatches(1)
+	n.AddRepos(1)
+	n.SetLimitHit()
+	n.Finish(nil)
+}
@@ -12,6 +12,8 @@ const (
 	File       = "file"
 	Repository = "repo"
 	Symbol     = "symbol"
+
+	Owners = "owners"
 )
 
 // SelectPath represents a parsed and validated select value
@@ -43,6 +45,7 @@ var validSelectors = object{
 	File: {
 		"directory": nil,
 		"path":      nil,
+		Owners:      nil,
 	},
 	Repository: nil,
 	Symbol: object{
@@ -0,0 +1,198 @@
+// Package fuzzy implements typo tolerant fuzzy search over file paths.
+package fuzzy
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// scoreMatch is the score of each matched rune of the pattern.
+	scoreMatch = 16
+
+	// bonusBoundary is added for matches at the start of a path segment or
+	// word, such as after "/", "_" or at a camelCase hump.
+	bonusBoundary = 8
+
+	// bonusConsecutive is added for matches directly following the previous
+	// match.
+	bonusConsecutive = 4
+
+	// bonusBasename is added for matches in the last path segment.
+	bonusBasename = 4
+
+	// maxGapPenalty caps the penalty of one rune per rune skipped between
+	// two matches.
+	maxGapPenalty = 8
+
+	// penaltyTypo is subtracted from the score of a match that needed to
+	// skip a rune of the pattern.
+	penaltyTypo = 3 * scoreMatch
+
+	// minTypoLength is the minimum length of a pattern for which a typo is
+	// tolerated. Dropping a rune of shorter patterns matches too much.
+	minTypoLength = 4
+)
+
+// normalize lower cases pattern and removes whitespace from it, so that
+// "search results" matches "search_results.go".
+func normalize(pattern string) []rune {
+	return toLower([]rune(strings.Join(strings.Fields(pattern), "")))
+}
+
+// toLower lower cases each rune of rs. Unlike strings.ToLower, the result
+// has the same number of runes, so indexes into it are indexes into rs.
+func toLower(rs []rune) []rune {
+	lower := make([]rune, len(rs))
+	for i, r := range rs {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// Score returns how well path matches pattern, and whether it matches at
+// all. A path matches if it contains the runes of pattern in order, ignoring
+// case. To tolerate a typo, one rune of patterns of at least minTypoLength
+// runes may be missing, at a penalty. Higher scores are better matches.
+func Score(pattern, path string) (int, bool) {
+	p := normalize(pattern)
+	if len(p) == 0 {
+		return 0, false
+	}
+
+	text := []rune(path)
+	lower := toLower(text)
+	if s, ok := score(p, text, lower); ok {
+		return s, true
+	}
+	if len(p) < minTypoLength {
+		return 0, false
+	}
+
+	best, found := 0, false
+	for i := range p {
+		if s, ok := score(withoutRune(p, i), text, lower); ok && (!found || s > best) {
+			best, found = s, true
+		}
+	}
+	return best - penaltyTypo, found
+}
+
+// CandidateRegexp returns a case insensitive regular expression which matches
+// a superset of the paths Score matches pattern against. It is used to narrow
+// down the paths to score with indexed search.
+func CandidateRegexp(pattern string) string {
+	p := normalize(pattern)
+
+	subsequence := func(rs []rune) string {
+		parts := make([]string, 0, len(rs))
+		for _, r := range rs {
+			parts = append(parts, regexp.QuoteMeta(string(r)))
+		}
+		return strings.Join(parts, ".*")
+	}
+
+	alternatives := []string{subsequence(p)}
+	if len(p) >= minTypoLength {
+		seen := map[string]bool{alternatives[0]: true}
+		for i := range p {
+			alt := subsequence(withoutRune(p, i))
+			if !seen[alt] {
+				seen[alt] = true
+				alternatives = append(alternatives, alt)
+			}
+		}
+	}
+	return "(?i)(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+func withoutRune(p []rune, i int) []rune {
+	q := make([]rune, 0, len(p)-1)
+	q = append(q, p[:i]...)
+	return append(q, p[i+1:]...)
+}
+
+// score returns the score of the best of two alignments of the runes of p
+// in text: the shortest match ending at the earliest possible position, and
+// the match ending as late as possible, which favors the file name.
+func score(p, text, lower []rune) (int, bool) {
+	end := -1
+	for i, j := 0, 0; i < len(lower); i++ {
+		if lower[i] == p[j] {
+			j++
+			if j == len(p) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+
+	best := scorePositions(alignBackward(p, lower, end), text)
+	if s := scorePositions(alignBackward(p, lower, len(lower)-1), text); s > best {
+		best = s
+	}
+	return best, true
+}
+
+// alignBackward matches the runes of p in lower from last to first, starting
+// at index end and matching each rune as late as possible. p must be a
+// subsequence of lower[:end+1].
+func alignBackward(p, lower []rune, end int) []int {
+	positions := make([]int, len(p))
+	j := len(p) - 1
+	for i := end; i >= 0 && j >= 0; i-- {
+		if lower[i] == p[j] {
+			positions[j] = i
+			j--
+		}
+	}
+	return positions
+}
+
+func scorePositions(positions []int, text []rune) int {
+	basename := 0
+	for i, r := range text {
+		if r == '/' {
+			basename = i + 1
+		}
+	}
+
+	s := 0
+	for i, pos := range positions {
+		s += scoreMatch
+		if isBoundary(text, pos) {
+			s += bonusBoundary
+		}
+		if pos >= basename {
+			s += bonusBasename
+		}
+		if i == 0 {
+			continue
+		}
+		if gap := pos - positions[i-1] - 1; gap == 0 {
+			s += bonusConsecutive
+		} else if gap < maxGapPenalty {
+			s -= gap
+		} else {
+			s -= maxGapPenalty
+		}
+	}
+	return s
+}
+
+// isBoundary returns true if text[i] starts a path segment or word.
+func isBoundary(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := text[i-1], text[i]
+	switch prev {
+	case '/', '_', '-', '.', ' ':
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
@@ -0,0 +1,94 @@
+package fuzzy
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"srchresults", "cmd/frontend/graphqlbackend/search_results.go", true},
+		{"SearchResults", "cmd/frontend/graphqlbackend/search_results.go", true},
+		{"search results", "cmd/frontend/graphqlbackend/search_results.go", true},
+		{"serach", "internal/search/search.go", true},
+		{"resultz", "internal/search/result/result.go", true},
+		{"xyz", "internal/search/result/result.go", false},
+		{"sacrh", "internal/search/search.go", true},
+		{"hcraes", "internal/search/search.go", false},
+		{"", "internal/search/search.go", false},
+		// Short patterns don't tolerate typos.
+		{"mni", "main.go", false},
+		{"mai", "main.go", true},
+	}
+	for _, c := range cases {
+		if _, ok := Score(c.pattern, c.path); ok != c.match {
+			t.Errorf("Score(%q, %q) matched %v, want %v", c.pattern, c.path, ok, c.match)
+		}
+	}
+}
+
+func TestScore_Ranking(t *testing.T) {
+	rank := func(pattern string, paths ...string) []string {
+		sort.SliceStable(paths, func(i, j int) bool {
+			a, _ := Score(pattern, paths[i])
+			b, _ := Score(pattern, paths[j])
+			return a > b
+		})
+		return paths
+	}
+
+	cases := []struct {
+		pattern string
+		want    []string
+	}{{
+		// Word boundaries beat matches in the middle of words.
+		pattern: "sr",
+		want:    []string{"search_results.go", "user.go"},
+	}, {
+		// The file name beats a directory.
+		pattern: "search",
+		want:    []string{"cmd/search.go", "search/cmd.go"},
+	}, {
+		// Consecutive matches beat scattered ones.
+		pattern: "main",
+		want:    []string{"cmd/main.go", "cmd/my_aging.go"},
+	}, {
+		// Exact matches beat typos.
+		pattern: "search",
+		want:    []string{"search.go", "serach.go"},
+	}}
+	for _, c := range cases {
+		reversed := make([]string, len(c.want))
+		for i, p := range c.want {
+			reversed[len(c.want)-1-i] = p
+		}
+		if diff := cmp.Diff(c.want, rank(c.pattern, reversed...)); diff != "" {
+			t.Errorf("%s: %s", c.pattern, diff)
+		}
+	}
+}
+
+func TestCandidateRegexp(t *testing.T) {
+	paths := []string{
+		"cmd/frontend/graphqlbackend/search_results.go",
+		"internal/search/search.go",
+		"internal/search/result/result.go",
+		"main.go",
+		"README.md",
+	}
+	for _, pattern := range []string{"srchresults", "serach", "resultz", "mai", "mian", "a.b", "read me"} {
+		re := regexp.MustCompile(CandidateRegexp(pattern))
+		for _, path := range paths {
+			if _, ok := Score(pattern, path); ok && !re.MatchString(path) {
+				t.Errorf("CandidateRegexp(%q) does not match %q, which Score matches", pattern, path)
+			}
+		}
+	}
+}
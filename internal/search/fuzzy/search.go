@@ -0,0 +1,269 @@
+package fuzzy
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxIndexedCandidates is the maximum number of paths we ask Zoekt for. They
+// are narrowed down with CandidateRegexp before they are scored.
+const maxIndexedCandidates = 10000
+
+// maxUnindexedCandidates is the maximum number of matching paths we collect
+// per revision of an unindexed repository.
+const maxUnindexedCandidates = 10000
+
+// FileSearch is a search job which ranks the paths of the files in the
+// searched repositories by how well they fuzzy match the pattern. Paths in
+// indexed repositories come from Zoekt, paths in unindexed repositories are
+// listed with git ls-tree.
+type FileSearch struct {
+	// Args are the search arguments. The repositories to search are
+	// resolved after the job is created.
+	Args *search.TextParameters
+
+	// Limit is the number of paths to return.
+	Limit int
+}
+
+func (s *FileSearch) Name() string {
+	return "Fuzzy"
+}
+
+func (s *FileSearch) Run(ctx context.Context, stream streaming.Sender) error {
+	pattern := s.Args.PatternInfo.Pattern
+	r := &ranker{pattern: pattern}
+
+	p := *s.Args.PatternInfo
+	p.Pattern = CandidateRegexp(pattern)
+	p.IsRegExp = true
+	p.IsCaseSensitive = false
+	p.PatternMatchesContent = false
+	p.PatternMatchesPath = true
+	p.FileMatchLimit = maxIndexedCandidates
+	args := *s.Args
+	args.PatternInfo = &p
+
+	request, err := zoektutil.NewIndexedSearchRequest(ctx, &args, search.TextRequest, zoektutil.MissingRepoRevStatus(stream))
+	if err != nil {
+		return err
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	if s.Args.Mode != search.SearcherOnly {
+		g.Go(func() error {
+			return request.Search(ctx, streaming.StreamFunc(func(event streaming.SearchEvent) {
+				for _, match := range event.Results {
+					if fm, ok := match.(*result.FileMatch); ok {
+						r.add(fm)
+					}
+				}
+				// Pass on the status of the searched repositories.
+				event.Results = nil
+				stream.Send(event)
+			}))
+		})
+	}
+	if s.Args.Mode != search.SkipUnindexed {
+		filter, err := newPathFilter(&p)
+		if err != nil {
+			return err
+		}
+		for _, repoRevs := range request.UnindexedRepos() {
+			repoRevs := repoRevs
+			g.Go(func() error {
+				return searchUnindexed(ctx, repoRevs, filter, r, stream)
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	matches, limitHit := r.ranked(s.Limit)
+	stream.Send(streaming.SearchEvent{
+		Results: matches,
+		Stats:   streaming.Stats{IsLimitHit: limitHit},
+	})
+	return nil
+}
+
+// searchUnindexed scores the paths of the files at each revision of
+// repoRevs. Like for searcher, revisions which can't be searched because the
+// repository is missing or still cloning are reported in the status of the
+// repository instead of failing the search.
+func searchUnindexed(ctx context.Context, repoRevs *search.RepositoryRevisions, filter *pathFilter, r *ranker, stream streaming.Sender) error {
+	revs, err := repoRevs.ExpandedRevSpecs(ctx)
+	if err != nil {
+		stats, err := searchrepos.HandleRepoSearchResult(repoRevs, false, false, err)
+		stream.Send(streaming.SearchEvent{Stats: stats})
+		return err
+	}
+	if len(revs) == 0 {
+		// The default branch.
+		revs = []string{""}
+	}
+
+	for _, rev := range revs {
+		repoRev := &search.RepositoryRevisions{Repo: repoRevs.Repo, Revs: []search.RevisionSpecifier{{RevSpec: rev}}}
+		limitHit, err := searchUnindexedRev(ctx, repoRev.Repo, rev, filter, r)
+		stats, err := searchrepos.HandleRepoSearchResult(repoRev, limitHit, false, err)
+		stream.Send(streaming.SearchEvent{Stats: stats})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchUnindexedRev scores the paths of the files at rev of repo. It stops
+// after maxUnindexedCandidates matching paths and reports that the limit was
+// hit.
+func searchUnindexedRev(ctx context.Context, repo types.RepoName, rev string, filter *pathFilter, r *ranker) (limitHit bool, err error) {
+	commit, err := git.ResolveRevision(ctx, repo.Name, rev, git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return false, errors.Wrapf(err, "resolving %s@%s", repo.Name, rev)
+	}
+
+	// ReadDir lists the tree recursively with git ls-tree.
+	files, err := git.ReadDir(ctx, repo.Name, commit, "", true)
+	if err != nil {
+		return false, err
+	}
+
+	candidates := 0
+	for _, f := range files {
+		if f.IsDir() || !filter.matches(f.Name()) {
+			continue
+		}
+		if candidates == maxUnindexedCandidates {
+			return true, nil
+		}
+		if r.add(&result.FileMatch{
+			File: result.File{
+				Repo:     repo,
+				CommitID: commit,
+				InputRev: &rev,
+				Path:     f.Name(),
+			},
+		}) {
+			candidates++
+		}
+	}
+	return false, nil
+}
+
+// pathFilter applies the file: and -file: filters of a query to paths, like
+// Zoekt does for indexed repositories.
+type pathFilter struct {
+	include []*regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newPathFilter(p *search.TextPatternInfo) (*pathFilter, error) {
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if !p.PathPatternsAreCaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		return regexp.Compile(pattern)
+	}
+
+	var f pathFilter
+	for _, pattern := range p.IncludePatterns {
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, re)
+	}
+	if p.ExcludePattern != "" {
+		re, err := compile(p.ExcludePattern)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = re
+	}
+	return &f, nil
+}
+
+func (f *pathFilter) matches(path string) bool {
+	for _, re := range f.include {
+		if !re.MatchString(path) {
+			return false
+		}
+	}
+	return f.exclude == nil || !f.exclude.MatchString(path)
+}
+
+type scoredMatch struct {
+	match *result.FileMatch
+	score int
+}
+
+// ranker collects the file matches whose paths match pattern.
+type ranker struct {
+	pattern string
+
+	mu      sync.Mutex
+	matches []scoredMatch
+}
+
+// add adds fm if its path matches the pattern, and reports whether it did.
+func (r *ranker) add(fm *result.FileMatch) bool {
+	score, ok := Score(r.pattern, fm.Path)
+	if !ok {
+		return false
+	}
+	// Only the path matched.
+	fm.LineMatches = nil
+	fm.Symbols = nil
+
+	r.mu.Lock()
+	r.matches = append(r.matches, scoredMatch{match: fm, score: score})
+	r.mu.Unlock()
+	return true
+}
+
+// ranked returns the limit best matches, best first, and whether there were
+// more matches.
+func (r *ranker) ranked(limit int) ([]result.Match, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sort.Slice(r.matches, func(i, j int) bool {
+		a, b := r.matches[i], r.matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if len(a.match.Path) != len(b.match.Path) {
+			return len(a.match.Path) < len(b.match.Path)
+		}
+		if a.match.Repo.Name != b.match.Repo.Name {
+			return a.match.Repo.Name < b.match.Repo.Name
+		}
+		return a.match.Path < b.match.Path
+	})
+
+	n := len(r.matches)
+	if limit > 0 && n > limit {
+		n = limit
+	}
+	matches := make([]result.Match, 0, n)
+	for _, m := range r.matches[:n] {
+		matches = append(matches, m.match)
+	}
+	return matches, n < len(r.matches)
+}
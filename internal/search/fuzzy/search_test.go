@@ -0,0 +1,66 @@
+package fuzzy
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+func TestSearchUnindexed(t *testing.T) {
+	repoRevs := &search.RepositoryRevisions{Repo: types.RepoName{ID: 1, Name: "foo"}}
+
+	t.Run("cloning", func(t *testing.T) {
+		git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+			return "", &gitdomain.RepoNotExistError{Repo: "foo", CloneInProgress: true}
+		}
+		defer git.ResetMocks()
+
+		r := &ranker{pattern: "main"}
+		_, stats, err := streaming.CollectStream(func(stream streaming.Sender) error {
+			return searchUnindexed(context.Background(), repoRevs, &pathFilter{}, r, stream)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stats.Status.Get(1); got != search.RepoStatusCloning {
+			t.Errorf("got status %s, want cloning", got)
+		}
+	})
+
+	t.Run("candidate limit", func(t *testing.T) {
+		git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+			return "deadbeef", nil
+		}
+		git.Mocks.ReadDir = func(commit api.CommitID, name string, recurse bool) ([]fs.FileInfo, error) {
+			files := make([]fs.FileInfo, 0, maxUnindexedCandidates+1)
+			for i := 0; i <= maxUnindexedCandidates; i++ {
+				files = append(files, &util.FileInfo{Name_: fmt.Sprintf("cmd/%d/main.go", i)})
+			}
+			return files, nil
+		}
+		defer git.ResetMocks()
+
+		r := &ranker{pattern: "main"}
+		_, stats, err := streaming.CollectStream(func(stream streaming.Sender) error {
+			return searchUnindexed(context.Background(), repoRevs, &pathFilter{}, r, stream)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stats.IsLimitHit {
+			t.Error("expected limit hit")
+		}
+		if len(r.matches) != maxUnindexedCandidates {
+			t.Errorf("got %d candidates, want %d", len(r.matches), maxUnindexedCandidates)
+		}
+	})
+}
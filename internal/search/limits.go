@@ -42,3 +42,21 @@ func SearchLimits(c *conf.Unified) schema.SearchLimits {
 
 	return limits
 }
+
+// ResultCache returns the search result cache configuration with defaults
+// applied. The cache is disabled if TtlSeconds is zero.
+func ResultCache(c *conf.Unified) schema.SearchResultCache {
+	var cache schema.SearchResultCache
+	if c.SearchResultCache != nil {
+		cache = *c.SearchResultCache
+	}
+
+	if cache.TtlSeconds < 0 {
+		cache.TtlSeconds = 0
+	}
+	if cache.MaxRepos <= 0 {
+		cache.MaxRepos = 50
+	}
+
+	return cache
+}
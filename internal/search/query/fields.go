@@ -19,6 +19,7 @@ const (
 	FieldContent            = "content"
 	FieldVisibility         = "visibility"
 	FieldRev                = "rev"
+	FieldAt                 = "at"
 	FieldContext            = "context"
 
 	// For diff and commit search only:
@@ -34,6 +35,12 @@ const (
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
 	FieldSelect    = "select"
+	FieldExplain   = "explain"
+
+	// Internal fields produced by predicates, which are not valid in user
+	// queries:
+	FieldRepoHasTopic       = "repohastopic"
+	FieldRepoHasDescription = "repohasdescription"
 )
 
 var allFields = map[string]struct{}{
@@ -71,6 +78,8 @@ var allFields = map[string]struct{}{
 	FieldCombyRule:          empty,
 	FieldRev:                empty,
 	"revision":              empty,
+	FieldAt:                 empty,
+	FieldExplain:            empty,
 	FieldSelect:             empty,
 }
 
@@ -24,9 +24,10 @@ func Fuzz(data []byte) int {
 		SearchTypeLiteral,
 		SearchTypeRegex,
 		SearchTypeStructural,
+		SearchTypeFuzzy,
 	}
 	rand.Seed(time.Now().UnixNano())
-	option := options[rand.Intn(3)]
+	option := options[rand.Intn(len(options))]
 	_, err := Pipeline(Init(string(data), option))
 	if err != nil {
 		// uninteresting: error but no crash
@@ -0,0 +1,225 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// DiagnosticKind identifies the problem a Diagnostic reports.
+type DiagnosticKind string
+
+const (
+	DiagnosticRegexpPatternInLiteralSearch DiagnosticKind = "REGEXP_PATTERN_IN_LITERAL_SEARCH"
+	DiagnosticNoMatchingRepositories       DiagnosticKind = "NO_MATCHING_REPOSITORIES"
+	DiagnosticRedundantType                DiagnosticKind = "REDUNDANT_TYPE"
+	DiagnosticUnsupportedStructuralSearch  DiagnosticKind = "UNSUPPORTED_STRUCTURAL_SEARCH"
+	DiagnosticDeprecatedRepoGroup          DiagnosticKind = "DEPRECATED_REPOGROUP"
+)
+
+// DiagnosticSeverity is how likely a Diagnostic points out a mistake.
+type DiagnosticSeverity string
+
+const (
+	SeverityWarning     DiagnosticSeverity = "WARNING"
+	SeverityInformation DiagnosticSeverity = "INFORMATION"
+)
+
+// Diagnostic describes a problem with a valid query, such as a likely mistake
+// or deprecated syntax.
+type Diagnostic struct {
+	Kind     DiagnosticKind
+	Severity DiagnosticSeverity
+	Message  string
+	// Range is the range of the offending part of the input query.
+	Range Range
+	// Suggestion is a rewrite of the input query which fixes the problem, if
+	// there is one.
+	Suggestion *Suggestion
+}
+
+// Suggestion is a rewritten query.
+type Suggestion struct {
+	Description string
+	Query       string
+}
+
+// NewDiagnostic returns a Diagnostic for the part of the input query in r.
+func NewDiagnostic(kind DiagnosticKind, severity DiagnosticSeverity, r Range, message string) Diagnostic {
+	return Diagnostic{Kind: kind, Severity: severity, Message: message, Range: r}
+}
+
+// withRewrite returns d with a suggestion to replace the part of the input
+// query in r with replacement.
+func (d Diagnostic) withRewrite(in string, r Range, replacement, description string) Diagnostic {
+	d.Suggestion = &Suggestion{Description: description, Query: rewrite(in, r, replacement)}
+	return d
+}
+
+// rewrite replaces the part of in in r with replacement. If replacement is
+// empty, the whitespace separating the removed part is removed, too.
+func rewrite(in string, r Range, replacement string) string {
+	start, end := r.Start.Column, r.End.Column
+	if start < 0 || start > end || end > len(in) {
+		return in
+	}
+	if replacement == "" {
+		for end < len(in) && in[end] == ' ' {
+			end++
+		}
+		if end == len(in) {
+			for start > 0 && in[start-1] == ' ' {
+				start--
+			}
+		}
+	}
+	return in[:start] + replacement + in[end:]
+}
+
+// Lint returns the diagnostics of the basic queries of plan, which was parsed
+// from the input query in. Diagnostics which are reported for several basic
+// queries, like for the parameters they share, are only returned once.
+func Lint(in string, plan Plan) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := map[string]struct{}{}
+	for _, b := range plan {
+		for _, linter := range []func(string, Basic) []Diagnostic{
+			lintRegexpPatternInLiteralSearch,
+			lintRedundantType,
+			lintStructuralSearch,
+			lintRepoGroup,
+		} {
+			for _, d := range linter(in, b) {
+				key := string(d.Kind) + d.Range.String()
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+	return diagnostics
+}
+
+// regexpMetasyntaxHeuristic matches regular expression syntax which is
+// unlikely to be meant literally, like .* or \b. Syntax like ( or | is common
+// in code and not matched.
+var regexpMetasyntaxHeuristic = lazyregexp.New(`\.[*+]|\\[bdswBDSW]|^\^|\$$|\(\?i\)`)
+
+// lintRegexpPatternInLiteralSearch reports patterns of literal searches
+// which look like regular expressions.
+func lintRegexpPatternInLiteralSearch(in string, b Basic) []Diagnostic {
+	if b.Pattern == nil {
+		return nil
+	}
+	var diagnostics []Diagnostic
+	VisitPattern([]Node{b.Pattern}, func(value string, _ bool, annotation Annotation) {
+		if !annotation.Labels.IsSet(Literal) || annotation.Labels.IsSet(Quoted) {
+			return
+		}
+		if !regexpMetasyntaxHeuristic.MatchString(value) {
+			return
+		}
+		if _, err := regexp.Compile(value); err != nil {
+			return
+		}
+		d := NewDiagnostic(DiagnosticRegexpPatternInLiteralSearch, SeverityWarning, annotation.Range,
+			fmt.Sprintf("The pattern %q looks like a regular expression, but it is matched literally.", value))
+		diagnostics = append(diagnostics, withPatternTypeRewrite(in, b, d, "regexp", "Search with regular expressions"))
+	})
+	return diagnostics
+}
+
+// withPatternTypeRewrite returns d with a suggestion to search with
+// patternType, which replaces the patterntype: parameter of b or is appended
+// to the query.
+func withPatternTypeRewrite(in string, b Basic, d Diagnostic, patternType, description string) Diagnostic {
+	replacement := "patternType:" + patternType
+	for _, p := range b.Parameters {
+		if p.Field == FieldPatternType {
+			return d.withRewrite(in, p.Annotation.Range, replacement, description)
+		}
+	}
+	end := newRange(len(in), len(in))
+	return d.withRewrite(in, end, " "+replacement, description)
+}
+
+// lintRedundantType reports type: parameters which repeat an earlier type:
+// parameter.
+func lintRedundantType(in string, b Basic) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := map[string]struct{}{}
+	for _, p := range b.Parameters {
+		if p.Field != FieldType || p.Negated {
+			continue
+		}
+		value := strings.ToLower(p.Value)
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			continue
+		}
+		d := NewDiagnostic(DiagnosticRedundantType, SeverityInformation, p.Annotation.Range,
+			fmt.Sprintf("type:%s is already part of the query.", p.Value))
+		diagnostics = append(diagnostics, d.withRewrite(in, p.Annotation.Range, "", "Remove the redundant type:"+p.Value))
+	}
+	return diagnostics
+}
+
+// structuralHole matches the holes of structural patterns, like :[x].
+var structuralHole = lazyregexp.New(`:\[[^\]]*\]`)
+
+// lintStructuralSearch reports combinations of patterns which structural
+// search does not support: several structural patterns in one query, and
+// holes in the regular expression and negated patterns which narrow down the
+// files structural patterns run on.
+func lintStructuralSearch(_ string, b Basic) []Diagnostic {
+	if b.Pattern == nil {
+		return nil
+	}
+
+	var structural []Annotation
+	var filtersWithHoles []Pattern
+	VisitPattern([]Node{b.Pattern}, func(value string, negated bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Structural) {
+			structural = append(structural, annotation)
+			return
+		}
+		// Regular expression and negated patterns narrow down the files
+		// structural patterns run on.
+		if (negated || annotation.Labels.IsSet(Regexp)) && structuralHole.MatchString(value) {
+			filtersWithHoles = append(filtersWithHoles, Pattern{Value: value, Negated: negated, Annotation: annotation})
+		}
+	})
+	if len(structural) == 0 {
+		return nil
+	}
+
+	var diagnostics []Diagnostic
+	for _, annotation := range structural[1:] {
+		diagnostics = append(diagnostics, NewDiagnostic(DiagnosticUnsupportedStructuralSearch, SeverityWarning, annotation.Range,
+			"Only one structural pattern is supported per query. Structural patterns combined with and are searched separately and their results are intersected by file."))
+	}
+	for _, p := range filtersWithHoles {
+		diagnostics = append(diagnostics, NewDiagnostic(DiagnosticUnsupportedStructuralSearch, SeverityWarning, p.Annotation.Range,
+			fmt.Sprintf("The pattern %q is matched as a regular expression, so its holes are not supported. Holes are only supported in the structural pattern, which cannot be negated.", p.Value)))
+	}
+	return diagnostics
+}
+
+// lintRepoGroup reports the deprecated repogroup: field, which search
+// contexts replace.
+func lintRepoGroup(in string, b Basic) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, p := range b.Parameters {
+		if p.Field != FieldRepoGroup {
+			continue
+		}
+		d := NewDiagnostic(DiagnosticDeprecatedRepoGroup, SeverityWarning, p.Annotation.Range,
+			"repogroup: is deprecated and will be removed. Use search contexts instead.")
+		diagnostics = append(diagnostics, d.withRewrite(in, p.Annotation.Range, "context:"+p.Value, fmt.Sprintf("Search the search context %q, which replaces the repogroup once it is converted", p.Value)))
+	}
+	return diagnostics
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		input      string
+		searchType SearchType
+		want       []string // kind, followed by the suggested query if any
+	}{
+		{
+			input:      "repo:foo bar",
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input:      "func.*Error",
+			searchType: SearchTypeLiteral,
+			want:       []string{"REGEXP_PATTERN_IN_LITERAL_SEARCH: func.*Error patternType:regexp"},
+		},
+		{
+			input:      `\bfoo\b patterntype:literal`,
+			searchType: SearchTypeLiteral,
+			want:       []string{`REGEXP_PATTERN_IN_LITERAL_SEARCH: \bfoo\b patternType:regexp`},
+		},
+		{
+			input:      "fmt.Println(x)",
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input:      "func.*Error",
+			searchType: SearchTypeRegex,
+		},
+		{
+			input:      "type:commit foo type:commit",
+			searchType: SearchTypeLiteral,
+			want:       []string{"REDUNDANT_TYPE: type:commit foo"},
+		},
+		{
+			input:      "type:commit type:diff foo",
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input:      "repogroup:go foo",
+			searchType: SearchTypeLiteral,
+			want:       []string{"DEPRECATED_REPOGROUP: context:go foo"},
+		},
+		{
+			input:      "foo(:[args]) NOT /bar/",
+			searchType: SearchTypeStructural,
+		},
+		{
+			input:      "foo(:[args]) NOT bar(:[x])",
+			searchType: SearchTypeStructural,
+			want:       []string{"UNSUPPORTED_STRUCTURAL_SEARCH"},
+		},
+		{
+			input:      "foo(:[args]) and bar(:[x])",
+			searchType: SearchTypeStructural,
+			want:       []string{"UNSUPPORTED_STRUCTURAL_SEARCH"},
+		},
+		{
+			input:      "NOT bar(:[x])",
+			searchType: SearchTypeRegex,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			plan, err := Pipeline(Init(c.input, c.searchType))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range Lint(c.input, plan) {
+				s := string(d.Kind)
+				if d.Suggestion != nil {
+					s += ": " + d.Suggestion.Query
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Fatalf("unexpected diagnostics (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
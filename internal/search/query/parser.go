@@ -810,6 +810,14 @@ func (p *parser) ParsePattern(label labels) Pattern {
 		}
 	}
 
+	if p.leafParser == SearchTypeStructural && p.match(SLASH) {
+		// Structural queries may combine structural patterns with regexp
+		// patterns delimited by /.../.
+		if pattern, ok := p.TryParseDelimitedPattern(); ok {
+			return pattern
+		}
+	}
+
 	if isSet(p.heuristics, parensAsPatterns) {
 		if pattern, ok := p.TryScanBalancedPattern(label); ok {
 			return pattern
@@ -1155,7 +1163,7 @@ func Parse(in string, searchType SearchType) ([]Node, error) {
 			nodes = hoistedNodes
 		}
 	}
-	if searchType == SearchTypeLiteral {
+	if searchType == SearchTypeLiteral || searchType == SearchTypeFuzzy {
 		err = validatePureLiteralPattern(nodes, parser.balanced == 0)
 		if err != nil {
 			return nil, err
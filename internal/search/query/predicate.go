@@ -31,10 +31,13 @@ var DefaultPredicateRegistry = predicateRegistry{
 		"contains.file":         func() Predicate { return &RepoContainsFilePredicate{} },
 		"contains.content":      func() Predicate { return &RepoContainsContentPredicate{} },
 		"contains.commit.after": func() Predicate { return &RepoContainsCommitAfterPredicate{} },
+		"has.topic":             func() Predicate { return &RepoHasTopicPredicate{} },
+		"has.description":       func() Predicate { return &RepoHasDescriptionPredicate{} },
 	},
 	FieldFile: {
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
 		"contains":         func() Predicate { return &FileContainsContentPredicate{} },
+		"has.owner":        func() Predicate { return &FileHasOwnerPredicate{} },
 	},
 }
 
@@ -249,6 +252,78 @@ func (f *RepoContainsCommitAfterPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* repo:has.topic(name) */
+
+// RepoHasTopicPredicate represents the `repo:has.topic()` predicate, which
+// filters to repos tagged with a topic on their code host.
+type RepoHasTopicPredicate struct {
+	Topic string
+}
+
+func (f *RepoHasTopicPredicate) ParseParams(params string) error {
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return errors.Errorf("repo:has.topic argument should not be empty")
+	}
+	if strings.ContainsAny(params, " \t") {
+		return errors.Errorf("repo:has.topic argument should be a single topic, got %q", params)
+	}
+	f.Topic = params
+	return nil
+}
+
+func (f RepoHasTopicPredicate) Field() string { return FieldRepo }
+func (f RepoHasTopicPredicate) Name() string  { return "has.topic" }
+func (f *RepoHasTopicPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasTopic,
+		Value: f.Topic,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
+/* repo:has.description(pattern) */
+
+// RepoHasDescriptionPredicate represents the `repo:has.description()`
+// predicate, which filters to repos whose code host description matches a
+// regular expression.
+type RepoHasDescriptionPredicate struct {
+	Pattern string
+}
+
+func (f *RepoHasDescriptionPredicate) ParseParams(params string) error {
+	if _, err := regexp.Compile(params); err != nil {
+		return errors.Errorf("repo:has.description argument: %w", err)
+	}
+	if params == "" {
+		return errors.Errorf("repo:has.description argument should not be empty")
+	}
+	f.Pattern = params
+	return nil
+}
+
+func (f RepoHasDescriptionPredicate) Field() string { return FieldRepo }
+func (f RepoHasDescriptionPredicate) Name() string  { return "has.description" }
+func (f *RepoHasDescriptionPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasDescription,
+		Value: f.Pattern,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
 type FileContainsContentPredicate struct {
 	Pattern string
 }
@@ -284,6 +359,53 @@ func (f *FileContainsContentPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* file:has.owner(owner) */
+
+// CodeownersPathPattern matches the paths at which CODEOWNERS files are
+// looked up.
+const CodeownersPathPattern = `^(\.github/|\.gitlab/|docs/)?CODEOWNERS$`
+
+// FileHasOwnerPredicate represents the `file:has.owner()` predicate, which
+// filters to files owned by the given owner according to the CODEOWNERS file
+// of the repository.
+type FileHasOwnerPredicate struct {
+	Owner string
+}
+
+func (f *FileHasOwnerPredicate) ParseParams(params string) error {
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return errors.Errorf("file:has.owner argument should not be empty")
+	}
+	if strings.ContainsAny(params, " \t") {
+		return errors.Errorf("file:has.owner argument should be a single owner, got %q", params)
+	}
+	f.Owner = params
+	return nil
+}
+
+func (f FileHasOwnerPredicate) Field() string { return FieldFile }
+func (f FileHasOwnerPredicate) Name() string  { return "has.owner" }
+
+// Plan returns a plan which finds the CODEOWNERS files of the repositories in
+// scope. The owned files are resolved from the contents of these files.
+func (f *FileHasOwnerPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldSelect,
+		Value: "file",
+	}, Parameter{
+		Field: FieldFile,
+		Value: CodeownersPathPattern,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
 // nonPredicateRepos returns the repo nodes in a query that aren't predicates,
 // respecting parameters that determine repo results.
 func nonPredicateRepos(q Basic) []Node {
@@ -56,6 +56,82 @@ func TestRepoContainsPredicate(t *testing.T) {
 	})
 }
 
+func TestFileHasOwnerPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		valid := []struct {
+			params   string
+			expected *FileHasOwnerPredicate
+		}{
+			{`@sourcegraph/search`, &FileHasOwnerPredicate{Owner: "@sourcegraph/search"}},
+			{` alice@example.com `, &FileHasOwnerPredicate{Owner: "alice@example.com"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.params, func(t *testing.T) {
+				p := &FileHasOwnerPredicate{}
+				if err := p.ParseParams(tc.params); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		for _, params := range []string{``, ` `, `@alice @bob`} {
+			t.Run(params, func(t *testing.T) {
+				p := &FileHasOwnerPredicate{}
+				if err := p.ParseParams(params); err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
+func TestRepoHasTopicPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		p := &RepoHasTopicPredicate{}
+		if err := p.ParseParams(` payments `); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := (&RepoHasTopicPredicate{Topic: "payments"}); !reflect.DeepEqual(want, p) {
+			t.Fatalf("expected %#v, got %#v", want, p)
+		}
+
+		for _, params := range []string{``, ` `, `go payments`} {
+			t.Run(params, func(t *testing.T) {
+				p := &RepoHasTopicPredicate{}
+				if err := p.ParseParams(params); err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
+func TestRepoHasDescriptionPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		p := &RepoHasDescriptionPredicate{}
+		if err := p.ParseParams(`payment.*service`); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := (&RepoHasDescriptionPredicate{Pattern: "payment.*service"}); !reflect.DeepEqual(want, p) {
+			t.Fatalf("expected %#v, got %#v", want, p)
+		}
+
+		for _, params := range []string{``, `(`} {
+			t.Run(params, func(t *testing.T) {
+				p := &RepoHasDescriptionPredicate{}
+				if err := p.ParseParams(params); err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
 func TestParseAsPredicate(t *testing.T) {
 	tests := []struct {
 		input  string
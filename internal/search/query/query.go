@@ -52,12 +52,15 @@ func With(enabled bool, step step) step {
 func For(searchType SearchType) step {
 	var processType step
 	switch searchType {
-	case SearchTypeLiteral:
+	case SearchTypeLiteral, SearchTypeFuzzy:
 		processType = succeeds(substituteConcat(space))
 	case SearchTypeRegex:
 		processType = succeeds(escapeParensHeuristic, substituteConcat(fuzzyRegexp))
 	case SearchTypeStructural:
-		processType = succeeds(labelStructural, ellipsesForHoles, substituteConcat(space))
+		processType = sequence(
+			succeeds(labelStructural, ellipsesForHoles, andRegexpPatterns, substituteConcat(space)),
+			validateStructuralNegation,
+		)
 	}
 	normalize := succeeds(LowercaseFieldNames, SubstituteAliases(searchType), SubstituteCountAll)
 	return sequence(normalize, processType)
@@ -50,3 +50,36 @@ func TestPipeline(t *testing.T) {
 
 	autogold.Want("contains(...) spans newlines", `"repo:contains.file(\nfoo\n)"`).Equal(t, test("repo:contains.file(\nfoo\n)"))
 }
+
+func TestSplitStructural(t *testing.T) {
+	test := func(input string) string {
+		plan, err := Pipeline(InitStructural(input))
+		if err != nil {
+			return err.Error()
+		}
+		structural, filters, ok := plan[0].SplitStructural()
+		if !ok {
+			return "not split"
+		}
+		result := []string{structural.Pattern.(Pattern).Value}
+		for _, filter := range filters {
+			v := filter.Value
+			if filter.Annotation.Labels.IsSet(Regexp) {
+				v = "/" + v + "/"
+			}
+			if filter.Negated {
+				v = "not " + v
+			}
+			result = append(result, v)
+		}
+		return strings.Join(result, ", ")
+	}
+
+	autogold.Want("negated regexp", "foo(:[args]), not /generated/").Equal(t, test("foo(:[args]) NOT /generated/"))
+	autogold.Want("negated content", "foo(:[args]), not // generated").Equal(t, test(`foo(:[args]) -content:"// generated"`))
+	autogold.Want("and regexp", "foo(:[args]), /ba+r/").Equal(t, test("foo(:[args]) AND /ba+r/"))
+	autogold.Want("concatenated regexp", "foo(:[args]), /ba+r/").Equal(t, test("foo(:[args]) /ba+r/"))
+	autogold.Want("ellipses only in structural pattern", "foo(:[_]), /a...b/").Equal(t, test("foo(...) /a...b/"))
+	autogold.Want("structural pattern only", "not split").Equal(t, test("foo(:[args])"))
+	autogold.Want("two structural patterns", "not split").Equal(t, test("foo(:[args]) AND bar(:[args])"))
+}
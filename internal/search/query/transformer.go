@@ -680,6 +680,17 @@ func ConcatRevFilters(b Basic) Basic {
 // a postprocessing step to keep the parser lean.
 func labelStructural(nodes []Node) []Node {
 	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		if negated || annotation.Labels.IsSet(Regexp) {
+			// Negated patterns and regexp patterns delimited by /.../
+			// narrow down the files structural patterns run on. They
+			// are not structural patterns themselves, so queries with
+			// only negated patterns fail validateStructuralNegation.
+			return Pattern{
+				Value:      value,
+				Negated:    negated,
+				Annotation: annotation,
+			}
+		}
 		annotation.Labels.unset(Literal)
 		annotation.Labels.set(Structural)
 		return Pattern{
@@ -693,14 +704,57 @@ func labelStructural(nodes []Node) []Node {
 // ellipsesForHoles substitutes ellipses ... for :[_] holes in structural search queries.
 func ellipsesForHoles(nodes []Node) []Node {
 	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		if annotation.Labels.IsSet(Structural) {
+			value = strings.ReplaceAll(value, "...", ":[_]")
+		}
 		return Pattern{
-			Value:      strings.ReplaceAll(value, "...", ":[_]"),
+			Value:      value,
 			Negated:    negated,
 			Annotation: annotation,
 		}
 	})
 }
 
+// andRegexpPatterns converts concatenations of structural and regexp patterns
+// in structural queries to AND expressions. A regexp pattern delimited by
+// /.../ is not part of the structural pattern it follows, so
+// `foo(:[args]) /bar/` means `foo(:[args]) AND /bar/`.
+func andRegexpPatterns(nodes []Node) []Node {
+	isRegexpPattern := func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(Regexp)
+	}
+
+	var newNodes []Node
+	for _, node := range nodes {
+		operator, ok := node.(Operator)
+		if !ok {
+			newNodes = append(newNodes, node)
+			continue
+		}
+		operands := andRegexpPatterns(operator.Operands)
+		if operator.Kind != Concat || !Exists(operands, isRegexpPattern) {
+			newNodes = append(newNodes, newOperator(operands, operator.Kind)...)
+			continue
+		}
+
+		// Keep concatenating the patterns between regexp patterns.
+		var and, concat []Node
+		for _, operand := range operands {
+			if !isRegexpPattern(operand) {
+				concat = append(concat, operand)
+				continue
+			}
+			and = append(and, newOperator(concat, Concat)...)
+			and = append(and, operand)
+			concat = nil
+		}
+		and = append(and, newOperator(concat, Concat)...)
+		newNodes = append(newNodes, newOperator(and, And)...)
+	}
+	return newNodes
+}
+
 func OverrideField(nodes []Node, field, value string) []Node {
 	// First remove any fields that exist.
 	nodes = MapField(nodes, field, func(_ string, _ bool) Node {
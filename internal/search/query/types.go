@@ -29,6 +29,7 @@ const (
 	SearchTypeRegex SearchType = iota
 	SearchTypeLiteral
 	SearchTypeStructural
+	SearchTypeFuzzy
 )
 
 func (s SearchType) String() string {
@@ -39,6 +40,8 @@ func (s SearchType) String() string {
 		return "literal"
 	case SearchTypeStructural:
 		return "structural"
+	case SearchTypeFuzzy:
+		return "fuzzy"
 	default:
 		return fmt.Sprintf("unknown{%d}", s)
 	}
@@ -173,6 +176,35 @@ func (b Basic) IsStructural() bool {
 	return b.HasPatternLabel(Structural)
 }
 
+// SplitStructural splits a query whose pattern is an AND of one structural
+// pattern and regexp or negated patterns, like `foo(:[args]) NOT /generated/`.
+// It returns the query with only the structural pattern, and the other
+// patterns, which narrow down the files the structural pattern runs on. ok is
+// false if the pattern of b does not have this form.
+func (b Basic) SplitStructural() (structural Basic, filters []Pattern, ok bool) {
+	operator, isOperator := b.Pattern.(Operator)
+	if !isOperator || operator.Kind != And {
+		return b, nil, false
+	}
+
+	var structuralPatterns []Pattern
+	for _, operand := range operator.Operands {
+		pattern, isPattern := operand.(Pattern)
+		if !isPattern {
+			return b, nil, false
+		}
+		if pattern.Annotation.Labels.IsSet(Structural) {
+			structuralPatterns = append(structuralPatterns, pattern)
+		} else {
+			filters = append(filters, pattern)
+		}
+	}
+	if len(structuralPatterns) != 1 || len(filters) == 0 {
+		return b, nil, false
+	}
+	return Basic{Parameters: b.Parameters, Pattern: structuralPatterns[0]}, filters, true
+}
+
 // FindParameter calls f on parameters matching field in b.
 func (b Basic) FindParameter(field string, f func(value string, negated bool, annotation Annotation)) {
 	for _, p := range b.Parameters {
@@ -326,6 +358,12 @@ func (q Q) IsCaseSensitive() bool {
 	return q.BoolValue("case")
 }
 
+// Explain returns true if the query contains explain:yes, which explains how
+// the search is evaluated.
+func (q Q) Explain() bool {
+	return q.BoolValue(FieldExplain)
+}
+
 func (q Q) Repositories() (repos []string, negatedRepos []string) {
 	VisitField(q, FieldRepo, func(value string, negated bool, _ Annotation) {
 		if negated {
@@ -367,7 +405,8 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		return []*Value{{String: &value}}
 
 	case
-		FieldCase:
+		FieldCase,
+		FieldExplain:
 		b, _ := parseBool(value)
 		return []*Value{{Bool: &b}}
 
@@ -393,7 +432,7 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		FieldContent:
 		return []*Value{{String: &value}}
 
-	case FieldRepoHasFile:
+	case FieldRepoHasFile, FieldRepoHasDescription:
 		return []*Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
 	case
@@ -52,6 +52,10 @@ func IsStreamingCompatible(p Plan) bool {
 			if len(node.Operands) == 1 {
 				return true
 			}
+			if _, _, ok := p[0].SplitStructural(); ok {
+				// Runs as a single structural search.
+				return true
+			}
 		case Pattern:
 			return true
 		}
@@ -294,7 +298,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldDefault:
 		// Search patterns are not validated here, as it depends on the search type.
 	case
-		FieldCase:
+		FieldCase,
+		FieldExplain:
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
@@ -349,6 +354,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldRev:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldAt:
+		return satisfies(isSingular, isNotNegated, isValidGitDate)
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
@@ -415,27 +423,41 @@ func validateCommitParameters(nodes []Node) error {
 func validateTypeStructural(nodes []Node) error {
 	seenStructural := false
 	seenType := false
-	typeDiff := false
 	invalid := Exists(nodes, func(node Node) bool {
 		if p, ok := node.(Pattern); ok && p.Annotation.Labels.IsSet(Structural) {
 			seenStructural = true
 		}
-		if p, ok := node.(Parameter); ok && p.Field == FieldType {
+		if p, ok := node.(Parameter); ok && p.Field == FieldType && p.Value != "diff" {
+			// Structural search over the added and removed lines of
+			// diffs is supported.
 			seenType = true
-			typeDiff = p.Value == "diff"
 		}
 		return seenStructural && seenType
 	})
 	if invalid {
-		basic := "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents"
-		if typeDiff {
-			basic = basic + " and is not currently supported for diff searches"
-		}
-		return errors.New(basic)
+		return errors.New("this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and diffs")
 	}
 	return nil
 }
 
+// validateStructuralNegation returns an error if a disjunct of a structural
+// query contains a negated pattern but no structural pattern. Negated patterns
+// only narrow down the files structural patterns run on.
+func validateStructuralNegation(nodes []Node) ([]Node, error) {
+	for _, disjunct := range Dnf(nodes) {
+		seenNegated := false
+		seenStructural := false
+		VisitPattern(disjunct, func(_ string, negated bool, annotation Annotation) {
+			seenNegated = seenNegated || negated
+			seenStructural = seenStructural || annotation.Labels.IsSet(Structural)
+		})
+		if seenNegated && !seenStructural {
+			return nil, errors.New("the query contains a negated search pattern but no structural pattern. Structural search only supports negated search patterns alongside a structural pattern")
+		}
+	}
+	return nodes, nil
+}
+
 func validateRefGlobs(nodes []Node) error {
 	if !ContainsRefGlobs(nodes) {
 		return nil
@@ -532,9 +554,6 @@ func validatePattern(nodes []Node) error {
 		if annotation.Labels.IsSet(Regexp) {
 			_, err = regexp.Compile(value)
 		}
-		if annotation.Labels.IsSet(Structural) && negated {
-			err = errors.New("the query contains a negated search pattern. Structural search does not support negated search patterns at the moment")
-		}
 	})
 	return err
 }
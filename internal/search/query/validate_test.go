@@ -55,18 +55,35 @@ func TestValidation(t *testing.T) {
 		},
 		{
 			input:      `-content:"foo"`,
-			want:       "the query contains a negated search pattern. Structural search does not support negated search patterns at the moment",
+			want:       "the query contains a negated search pattern but no structural pattern. Structural search only supports negated search patterns alongside a structural pattern",
 			searchType: SearchTypeStructural,
 		},
 		{
 			input:      `NOT foo`,
-			want:       "the query contains a negated search pattern. Structural search does not support negated search patterns at the moment",
+			want:       "the query contains a negated search pattern but no structural pattern. Structural search only supports negated search patterns alongside a structural pattern",
+			searchType: SearchTypeStructural,
+		},
+		{
+			input:      `foo(:[args]) or NOT bar`,
+			want:       "the query contains a negated search pattern but no structural pattern. Structural search only supports negated search patterns alongside a structural pattern",
 			searchType: SearchTypeStructural,
 		},
 		{
 			input: "repo:foo rev:a rev:b",
 			want:  `field "rev" may not be used more than once`,
 		},
+		{
+			input: "at:2021-06-01 at:2021-07-01",
+			want:  `field "at" may not be used more than once`,
+		},
+		{
+			input: "-at:2021-06-01",
+			want:  `field "at" does not support negation`,
+		},
+		{
+			input: "explain:maybe",
+			want:  `invalid boolean "maybe"`,
+		},
 		{
 			input: "repo:foo@a rev:b",
 			want:  "invalid syntax. You specified both @ and rev: for a repo: filter and I don't know how to interpret this. Remove either @ or rev: and try again",
@@ -101,12 +118,12 @@ func TestValidation(t *testing.T) {
 		},
 		{
 			input:      "nice try type:repo",
-			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents",
+			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and diffs",
 			searchType: SearchTypeStructural,
 		},
 		{
-			input:      "type:diff nice try",
-			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
+			input:      "type:commit nice try",
+			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and diffs",
 			searchType: SearchTypeStructural,
 		},
 	}
@@ -105,9 +105,22 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 		return Resolved{}, err
 	}
 
+	// For auto-defined search contexts we only search the main branch
+	var searchContextRepositoryRevisions []*search.RepositoryRevisions
+	if !searchcontexts.IsAutoDefinedSearchContext(searchContext) {
+		searchContextRepositoryRevisions, err = searchcontexts.GetRepositoryRevisions(ctx, r.DB, searchContext)
+		if err != nil {
+			return Resolved{}, err
+		}
+		if searchContext.Query != "" && len(searchContextRepositoryRevisions) == 0 {
+			// The query of the search context matches no repositories.
+			return Resolved{}, nil
+		}
+	}
+
 	var searchableRepos []types.RepoName
 
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && len(op.Topics) == 0 && len(op.DescriptionPatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
 		start := time.Now()
 		searchableRepos, err = searchableRepositories(ctx, r.SearchableReposFunc, excludePatterns)
 		if err != nil {
@@ -142,9 +155,20 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 			OnlyArchived: op.OnlyArchived,
 			NoPrivate:    op.Visibility == query.Public,
 			OnlyPrivate:  op.Visibility == query.Private,
+
+			Topics:              op.Topics,
+			DescriptionPatterns: op.DescriptionPatterns,
 		}
 
-		if searchContext.ID != 0 {
+		if searchContext.Query != "" {
+			// Repositories of search contexts defined by a query are not
+			// stored, so we restrict the listed repositories to the ones the
+			// query matched.
+			options.IDs = make([]api.RepoID, 0, len(searchContextRepositoryRevisions))
+			for _, repositoryRevisions := range searchContextRepositoryRevisions {
+				options.IDs = append(options.IDs, repositoryRevisions.Repo.ID)
+			}
+		} else if searchContext.ID != 0 {
 			options.SearchContextID = searchContext.ID
 		} else if searchContext.NamespaceUserID != 0 {
 			options.UserID = searchContext.NamespaceUserID
@@ -183,14 +207,6 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 	var missingRepoRevs []*search.RepositoryRevisions
 	tr.LazyPrintf("Associate/validate revs - start")
 
-	// For auto-defined search contexts we only search the main branch
-	var searchContextRepositoryRevisions []*search.RepositoryRevisions
-	if !searchcontexts.IsAutoDefinedSearchContext(searchContext) {
-		searchContextRepositoryRevisions, err = searchcontexts.GetRepositoryRevisions(ctx, r.DB, searchContext.ID)
-		if err != nil {
-			return Resolved{}, err
-		}
-	}
 	repoSet := make(map[api.RepoID]types.RepoName, len(repos))
 
 	for _, repo := range repos {
@@ -284,6 +300,14 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 		tr.LazyPrintf("repohascommitafter removed %d repos in %s", before-len(repoRevs), time.Since(start))
 	}
 
+	if op.At != "" && err == nil {
+		start := time.Now()
+		var missing []*search.RepositoryRevisions
+		repoRevs, missing, err = resolveRevisionsAt(ctx, repoRevs, op.At)
+		missingRepoRevs = append(missingRepoRevs, missing...)
+		tr.LazyPrintf("at: resolved revisions of %d repos in %s", len(repoRevs), time.Since(start))
+	}
+
 	return Resolved{
 		RepoRevs:        repoRevs,
 		RepoSet:         repoSet,
@@ -566,6 +590,118 @@ func filterRepoHasCommitAfter(ctx context.Context, revisions []*search.Repositor
 	return pass, err
 }
 
+// resolveRevisionsAt replaces the revisions of each repository with the
+// commit nearest before the date at, so that searches run against the code as
+// it was at that time. Ref globs are expanded to the refs they match first.
+// Revisions without commits before at are returned as missing.
+func resolveRevisionsAt(ctx context.Context, revisions []*search.RepositoryRevisions, at string) (resolved, missing []*search.RepositoryRevisions, err error) {
+	target, err := query.ParseGitDate(at, time.Now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		mut sync.Mutex
+		run = parallel.NewRun(128)
+	)
+
+	for _, revs := range revisions {
+		run.Acquire()
+
+		revs := revs
+		goroutine.Go(func() {
+			defer run.Release()
+
+			revSpecs, err := revs.ExpandedRevSpecs(ctx)
+			if err != nil {
+				run.Error(err)
+				return
+			}
+			if len(revSpecs) == 0 {
+				// The default branch.
+				revSpecs = []string{""}
+			}
+
+			var found, notFound []search.RevisionSpecifier
+			seen := make(map[api.CommitID]bool)
+			for _, revSpec := range revSpecs {
+				if strings.HasPrefix(revSpec, "^") {
+					// Negated revisions only apply to commit search, which
+					// searches the history before a commit anyway.
+					continue
+				}
+
+				commit, err := nearestCommitBefore(ctx, revs.GitserverRepo(), revSpec, target)
+				if gitdomain.IsRepoNotExist(err) {
+					// Keep the revision, so that the status of the
+					// repository is reported by the search.
+					found = append(found, search.RevisionSpecifier{RevSpec: revSpec})
+					continue
+				}
+				if err != nil && !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+					run.Error(err)
+					continue
+				}
+				if commit == "" {
+					if revSpec == "" {
+						// Report as HEAD not "" (empty string) to avoid user confusion.
+						revSpec = "HEAD"
+					}
+					notFound = append(notFound, search.RevisionSpecifier{RevSpec: revSpec})
+					continue
+				}
+				if !seen[commit] {
+					seen[commit] = true
+					found = append(found, search.RevisionSpecifier{RevSpec: string(commit)})
+				}
+			}
+
+			mut.Lock()
+			defer mut.Unlock()
+			if len(found) > 0 {
+				resolved = append(resolved, &search.RepositoryRevisions{Repo: revs.Repo, Revs: found})
+			}
+			if len(notFound) > 0 {
+				missing = append(missing, &search.RepositoryRevisions{Repo: revs.Repo, Revs: notFound})
+			}
+		})
+	}
+
+	err = run.Wait()
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Repo.Name < resolved[j].Repo.Name
+	})
+	return resolved, missing, err
+}
+
+// nearestCommitBefore returns the commit of revSpec nearest before target, or
+// "" if revSpec has no commits before target. git.FindNearestCommit returns
+// the nearest commit on either side of target, so we fall back to the latest
+// commit before target if it returns a later commit.
+func nearestCommitBefore(ctx context.Context, repo api.RepoName, revSpec string, target time.Time) (api.CommitID, error) {
+	commit, err := git.FindNearestCommit(ctx, repo, revSpec, target)
+	if err != nil || commit == nil {
+		return "", err
+	}
+	if !commit.Author.Date.After(target) {
+		return commit.ID, nil
+	}
+
+	if revSpec == "" {
+		revSpec = "HEAD"
+	}
+	commits, err := git.Commits(ctx, repo, git.CommitsOptions{
+		N:         1,
+		Before:    target.Format(time.RFC3339),
+		Range:     revSpec,
+		DateOrder: true,
+	})
+	if err != nil || len(commits) == 0 {
+		return "", err
+	}
+	return commits[0].ID, nil
+}
+
 func optimizeRepoPatternWithHeuristics(repoPattern string) string {
 	if envvar.SourcegraphDotComMode() && (strings.HasPrefix(repoPattern, "github.com") || strings.HasPrefix(repoPattern, `github\.com`)) {
 		repoPattern = "^" + repoPattern
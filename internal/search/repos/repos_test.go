@@ -7,9 +7,11 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/zoekt"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
@@ -21,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git/gitapi"
 )
 
 var dsn = flag.String("dsn", "", "Database connection string to use in integration tests")
@@ -584,3 +587,83 @@ func TestResolveRepositoriesWithSearchContext(t *testing.T) {
 		t.Errorf("got repository revisions %+v, want %+v", resolved.RepoRevs, wantRepositoryRevisions)
 	}
 }
+
+func TestResolveRevisionsAt(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	history := map[api.RepoName][]*gitapi.Commit{
+		"foo": {
+			{ID: "foo-june", Author: gitapi.Signature{Date: date("2021-06-02")}},
+			{ID: "foo-may", Author: gitapi.Signature{Date: date("2021-05-01")}},
+		},
+		"bar": {
+			{ID: "bar-july", Author: gitapi.Signature{Date: date("2021-07-01")}},
+		},
+	}
+
+	git.Mocks.ResolveRevision = func(spec string, _ git.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec == "missing" {
+			return "", &gitdomain.RevisionNotFoundError{Repo: "foo", Spec: spec}
+		}
+		return "head", nil
+	}
+	git.Mocks.Commits = func(repo api.RepoName, opt git.CommitsOptions) ([]*gitapi.Commit, error) {
+		var commits []*gitapi.Commit
+		for _, c := range history[repo] {
+			if opt.After != "" {
+				after, _ := time.Parse(time.RFC3339, opt.After)
+				if !c.Author.Date.After(after) {
+					continue
+				}
+			}
+			if opt.Before != "" {
+				before, _ := time.Parse(time.RFC3339, opt.Before)
+				if c.Author.Date.After(before) {
+					continue
+				}
+			}
+			commits = append(commits, c)
+		}
+		if opt.Reverse {
+			for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+				commits[i], commits[j] = commits[j], commits[i]
+			}
+		}
+		if opt.N > 0 && len(commits) > int(opt.N) {
+			commits = commits[:opt.N]
+		}
+		return commits, nil
+	}
+	defer git.ResetMocks()
+
+	repos := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 1, Name: "foo"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}, {RevSpec: "missing"}}},
+		{Repo: types.RepoName{ID: 2, Name: "bar"}},
+	}
+	resolved, missing, err := resolveRevisionsAt(context.Background(), repos, "2021-06-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The June commit of foo is nearest to the date, but after it.
+	wantResolved := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 1, Name: "foo"}, Revs: []search.RevisionSpecifier{{RevSpec: "foo-may"}}},
+	}
+	if diff := cmp.Diff(wantResolved, resolved, cmpopts.IgnoreUnexported(search.RepositoryRevisions{})); diff != "" {
+		t.Errorf("resolved mismatch (-want +got):\n%s", diff)
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Repo.Name < missing[j].Repo.Name })
+	wantMissing := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 2, Name: "bar"}, Revs: []search.RevisionSpecifier{{RevSpec: "HEAD"}}},
+		{Repo: types.RepoName{ID: 1, Name: "foo"}, Revs: []search.RevisionSpecifier{{RevSpec: "missing"}}},
+	}
+	if diff := cmp.Diff(wantMissing, missing, cmpopts.IgnoreUnexported(search.RepositoryRevisions{})); diff != "" {
+		t.Errorf("missing mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,75 @@
+package result
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// OwnerMatch is an owner, as declared in a CODEOWNERS file, of one or more
+// files matched by a search in a repository. It is produced by
+// select:file.owners.
+type OwnerMatch struct {
+	// Handle is the owner as written in the CODEOWNERS file, for example
+	// "@sourcegraph/search" or "alice@example.com".
+	Handle string
+
+	Repo     types.RepoName
+	CommitID api.CommitID
+
+	// InputRev is the revision the user requested to search, if any.
+	InputRev *string
+
+	// FileCount is the number of matched files owned by Handle.
+	FileCount int
+}
+
+func (om *OwnerMatch) RepoName() types.RepoName {
+	return om.Repo
+}
+
+func (om *OwnerMatch) ResultCount() int {
+	return 1
+}
+
+func (om *OwnerMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (om *OwnerMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: om.Repo.Name,
+			ID:   om.Repo.ID,
+		}
+	case filter.File:
+		if len(path) > 1 && path[1] == filter.Owners {
+			return om
+		}
+	}
+	return nil
+}
+
+// AppendMatches merges the file counts of src into om.
+func (om *OwnerMatch) AppendMatches(src *OwnerMatch) {
+	om.FileCount += src.FileCount
+}
+
+// Key returns a key which identifies the owner in a repository at a commit.
+// Handles are compared case-insensitively, like GitHub usernames and email
+// addresses, so the same owner written differently in CODEOWNERS rules is
+// only counted once.
+func (om *OwnerMatch) Key() Key {
+	return Key{
+		TypeRank: rankOwnerMatch,
+		Repo:     om.Repo.Name,
+		Commit:   om.CommitID,
+		Owner:    strings.ToLower(om.Handle),
+	}
+}
+
+func (om *OwnerMatch) searchResultMarker() {}
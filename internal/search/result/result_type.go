@@ -18,6 +18,7 @@ const (
 	TypeDiff
 	TypeCommit
 	TypeStructural
+	TypeFuzzy
 )
 
 var TypeFromString = map[string]Types{
@@ -28,6 +29,7 @@ var TypeFromString = map[string]Types{
 	"diff":       TypeDiff,
 	"commit":     TypeCommit,
 	"structural": TypeStructural,
+	"fuzzy":      TypeFuzzy,
 }
 
 func (r Types) Has(t Types) bool {
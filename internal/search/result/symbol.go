@@ -295,6 +295,26 @@ var toSelectKind = map[string]string{
 	"annotation":      "type-parameter",
 }
 
+// languageSelectKinds overrides toSelectKind for ctags kinds whose meaning
+// depends on the language of the symbol, keyed by lower case language.
+var languageSelectKinds = map[string]map[string]string{
+	"python":     {"member": "method"},
+	"javascript": {"generator": "function", "getter": "method", "setter": "method"},
+	"typescript": {"generator": "function", "getter": "method", "setter": "method"},
+	"c":          {"prototype": "function"},
+	"c++":        {"prototype": "function"},
+}
+
+// SelectKind returns the symbol selector kind in select.go that s
+// corresponds to, or the empty string if there is none.
+func (s Symbol) SelectKind() string {
+	kind := strings.ToLower(s.Kind)
+	if k, ok := languageSelectKinds[strings.ToLower(s.Language)][kind]; ok {
+		return k
+	}
+	return toSelectKind[kind]
+}
+
 func pick(symbols []*SymbolMatch, satisfy func(*SymbolMatch) bool) []*SymbolMatch {
 	var result []*SymbolMatch
 	for _, symbol := range symbols {
@@ -307,6 +327,6 @@ func pick(symbols []*SymbolMatch, satisfy func(*SymbolMatch) bool) []*SymbolMatc
 
 func SelectSymbolKind(symbols []*SymbolMatch, field string) []*SymbolMatch {
 	return pick(symbols, func(s *SymbolMatch) bool {
-		return field == toSelectKind[strings.ToLower(s.Symbol.Kind)]
+		return field == s.Symbol.SelectKind()
 	})
 }
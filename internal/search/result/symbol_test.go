@@ -67,3 +67,24 @@ func TestSymbolURL(t *testing.T) {
 		})
 	}
 }
+
+func TestSymbolSelectKind(t *testing.T) {
+	cases := []struct {
+		symbol Symbol
+		want   string
+	}{
+		{Symbol{Kind: "func", Language: "Go"}, "function"},
+		{Symbol{Kind: "member", Language: "Go"}, "field"},
+		{Symbol{Kind: "member", Language: "Python"}, "method"},
+		{Symbol{Kind: "getter", Language: "JavaScript"}, "method"},
+		{Symbol{Kind: "prototype", Language: "C++"}, "function"},
+		{Symbol{Kind: "Class", Language: "Java"}, "class"},
+		{Symbol{Kind: "unknown", Language: "Java"}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.symbol.Language+"/"+tc.symbol.Kind, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.symbol.SelectKind())
+		})
+	}
+}
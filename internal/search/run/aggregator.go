@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
@@ -71,59 +72,71 @@ func (a *Aggregator) Error(err error) {
 
 func (a *Aggregator) DoRepoSearch(ctx context.Context, args *search.TextParameters, limit int32) (err error) {
 	tr, ctx := trace.New(ctx, "doRepoSearch", "")
+	ctx, node := explain.Start(ctx, "Repo", "")
 	defer func() {
 		a.Error(err)
 		tr.SetError(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
-	err = SearchRepositories(ctx, args, limit, a)
+	err = SearchRepositories(ctx, args, limit, streaming.WithExplain(a, node))
 	return errors.Wrap(err, "repository search failed")
 }
 
 func (a *Aggregator) DoSearch(ctx context.Context, job Job, mode search.GlobalSearchMode) (err error) {
 	tr, ctx := trace.New(ctx, "DoSearch", job.Name())
 	tr.LogFields(trace.Stringer("global_search_mode", mode))
+	ctx, node := explain.Start(ctx, job.Name(), "")
 	defer func() {
 		a.Error(err)
 		tr.SetErrorIfNotContext(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
-	err = job.Run(ctx, a)
+	err = job.Run(ctx, streaming.WithExplain(a, node))
 	return errors.Wrap(err, job.Name()+" search failed")
 
 }
 
 func (a *Aggregator) DoSymbolSearch(ctx context.Context, args *search.TextParameters, limit int) (err error) {
 	tr, ctx := trace.New(ctx, "doSymbolSearch", "")
+	ctx, node := explain.Start(ctx, "Symbol", "")
+	node.SetRepositories(len(args.Repos))
 	defer func() {
 		a.Error(err)
 		tr.SetError(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
-	err = symbol.Search(ctx, args, limit, a)
+	err = symbol.Search(ctx, args, limit, streaming.WithExplain(a, node))
 	return errors.Wrap(err, "symbol search failed")
 }
 
 func (a *Aggregator) DoFilePathSearch(ctx context.Context, zoektArgs zoektutil.IndexedSearchRequest, searcherArgs *search.SearcherParameters, notSearcherOnly bool, stream streaming.Sender) (err error) {
 	tr, ctx := trace.New(ctx, "doFilePathSearch", "")
+	ctx, node := explain.Start(ctx, "Text", searcherArgs.PatternInfo.Pattern)
 	defer func() {
 		a.Error(err)
 		tr.SetErrorIfNotContext(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
-	return unindexed.SearchFilesInRepos(ctx, zoektArgs, searcherArgs, notSearcherOnly, stream)
+	return unindexed.SearchFilesInRepos(ctx, zoektArgs, searcherArgs, notSearcherOnly, streaming.WithExplain(stream, node))
 }
 
 func (a *Aggregator) DoDiffSearch(ctx context.Context, tp *search.TextParameters) (err error) {
 	tr, ctx := trace.New(ctx, "doDiffSearch", "")
+	ctx, node := explain.Start(ctx, "Diff", "")
+	node.SetRepositories(len(tp.Repos))
 	defer func() {
 		a.Error(err)
 		tr.SetError(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
 	if err := commit.CheckSearchLimits(tp.Query, len(tp.Repos), "diff"); err != nil {
@@ -136,15 +149,18 @@ func (a *Aggregator) DoDiffSearch(ctx context.Context, tp *search.TextParameters
 		return nil
 	}
 
-	return commit.SearchCommitDiffsInRepos(ctx, a.db, args, a)
+	return commit.SearchCommitDiffsInRepos(ctx, a.db, args, streaming.WithExplain(a, node))
 }
 
 func (a *Aggregator) DoCommitSearch(ctx context.Context, tp *search.TextParameters) (err error) {
 	tr, ctx := trace.New(ctx, "doCommitSearch", "")
+	ctx, node := explain.Start(ctx, "Commit", "")
+	node.SetRepositories(len(tp.Repos))
 	defer func() {
 		a.Error(err)
 		tr.SetError(err)
 		tr.Finish()
+		node.Finish(err)
 	}()
 
 	if err := commit.CheckSearchLimits(tp.Query, len(tp.Repos), "commit"); err != nil {
@@ -157,5 +173,5 @@ func (a *Aggregator) DoCommitSearch(ctx context.Context, tp *search.TextParamete
 		return nil
 	}
 
-	return commit.SearchCommitLogInRepos(ctx, a.db, args, a)
+	return commit.SearchCommitLogInRepos(ctx, a.db, args, streaming.WithExplain(a, node))
 }
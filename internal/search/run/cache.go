@@ -0,0 +1,326 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+var resultCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_search_result_cache_requests_total",
+	Help: "The number of searches looked up in the search result cache, by whether they were found.",
+}, []string{"status"})
+
+// ResultCache caches the matches of searches, so that repeating an identical
+// search doesn't hit the search backends again.
+//
+// Entries are keyed by the normalized query and the indexed commits the
+// searched revisions resolve to. Once a push to a searched repository is
+// indexed, its revision resolves to a new commit, so an entry is never served
+// for outdated code: it stops being looked up and expires after the
+// configured TTL. Only searches over indexed revisions are cached, so
+// building the key doesn't need gitserver.
+type ResultCache struct {
+	cache    *rcache.Cache
+	maxRepos int
+}
+
+// NewResultCache returns the result cache configured in the site
+// configuration, or nil if result caching is disabled.
+func NewResultCache(c *conf.Unified) *ResultCache {
+	cfg := search.ResultCache(c)
+	if cfg.TtlSeconds == 0 {
+		return nil
+	}
+	return &ResultCache{
+		cache:    rcache.NewWithTTL("search-results", cfg.TtlSeconds),
+		maxRepos: cfg.MaxRepos,
+	}
+}
+
+// Key returns the cache key of a search for q over repos. It returns the
+// empty string if the search can't be cached, for example because it
+// searches too many repositories, a ref glob or an unindexed revision.
+func (c *ResultCache) Key(ctx context.Context, z zoekt.Streamer, q query.Q, resultTypes result.Types, limit int, repos []*search.RepositoryRevisions) (string, error) {
+	if len(repos) == 0 || len(repos) > c.maxRepos {
+		return "", nil
+	}
+	// Commit and diff searches read the history of repositories from
+	// gitserver rather than the index.
+	if resultTypes.Has(result.TypeCommit | result.TypeDiff) {
+		return "", nil
+	}
+	// So do searches that skip the index, and gitserver may be ahead of it.
+	skipsIndex := false
+	query.VisitField(q, query.FieldIndex, func(value string, _ bool, _ query.Annotation) {
+		if query.ParseYesNoOnly(value) == query.No {
+			skipsIndex = true
+		}
+	})
+	if skipsIndex {
+		return "", nil
+	}
+
+	commits, err := indexedRepoCommits(ctx, z, repos)
+	if err != nil || commits == nil {
+		return "", err
+	}
+
+	return resultCacheKey(q, resultTypes, limit, commits), nil
+}
+
+// Get returns the cached matches for key and whether the search that
+// produced them hit its limit.
+func (c *ResultCache) Get(key string) (matches []result.Match, limitHit bool, ok bool) {
+	b, ok := c.cache.Get(key)
+	if !ok {
+		resultCacheRequests.WithLabelValues("miss").Inc()
+		return nil, false, false
+	}
+
+	var entry cachedResults
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&entry); err != nil {
+		log15.Warn("failed to decode cached search results", "error", err)
+		resultCacheRequests.WithLabelValues("miss").Inc()
+		return nil, false, false
+	}
+
+	resultCacheRequests.WithLabelValues("hit").Inc()
+	return entry.matches(), entry.LimitHit, true
+}
+
+// Set caches matches for key.
+func (c *ResultCache) Set(key string, matches []result.Match, limitHit bool) {
+	entry := cachedResults{
+		Matches:  make([]cachedMatch, 0, len(matches)),
+		LimitHit: limitHit,
+	}
+	for _, m := range matches {
+		cm, err := toCachedMatch(m)
+		if err != nil {
+			log15.Warn("not caching search results", "error", err)
+			return
+		}
+		entry.Matches = append(entry.Matches, cm)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&entry); err != nil {
+		log15.Warn("failed to encode search results for caching", "error", err)
+		return
+	}
+	c.cache.Set(key, buf.Bytes())
+}
+
+// repoCommit is a revision of a repository and the commit it resolved to
+// when the search ran.
+type repoCommit struct {
+	Repo   api.RepoID
+	Rev    string
+	Commit api.CommitID
+}
+
+// indexedRepoCommits returns the commits the revisions of repos resolve to,
+// taken from the list of indexed repositories that indexed search already
+// fetches. It returns nil if any revision isn't indexed and isn't an absolute
+// commit, since resolving it would mean asking gitserver, or if any revision
+// is a ref glob, since the set of refs it matches can change without any of
+// them being listed in the search.
+func indexedRepoCommits(ctx context.Context, z zoekt.Streamer, repos []*search.RepositoryRevisions) ([]repoCommit, error) {
+	if z == nil {
+		return nil, nil
+	}
+	list, err := z.List(ctx, &zoektquery.Const{Value: true}, &zoekt.ListOptions{Minimal: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []repoCommit
+	for _, repoRevs := range repos {
+		if !repoRevs.OnlyExplicit() {
+			return nil, nil
+		}
+		revs := repoRevs.RevSpecs()
+		if len(revs) == 0 {
+			// The default branch.
+			revs = []string{""}
+		}
+		for _, rev := range revs {
+			commit, ok := indexedCommit(list.Minimal[uint32(repoRevs.Repo.ID)], rev)
+			if !ok {
+				return nil, nil
+			}
+			commits = append(commits, repoCommit{Repo: repoRevs.Repo.ID, Rev: rev, Commit: commit})
+		}
+	}
+
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Repo != commits[j].Repo {
+			return commits[i].Repo < commits[j].Repo
+		}
+		return commits[i].Rev < commits[j].Rev
+	})
+	return commits, nil
+}
+
+// indexedCommit returns the commit rev resolves to according to repo, the
+// list entry of an indexed repository. repo is nil if the repository isn't
+// indexed.
+func indexedCommit(repo *zoekt.MinimalRepoListEntry, rev string) (api.CommitID, bool) {
+	if git.IsAbsoluteRevision(rev) {
+		return api.CommitID(rev), true
+	}
+	if repo == nil || len(repo.Branches) == 0 {
+		return "", false
+	}
+	if rev == "" || rev == "HEAD" {
+		// Zoekt lists the default branch first.
+		return api.CommitID(repo.Branches[0].Version), true
+	}
+	for _, branch := range repo.Branches {
+		if branch.Name == rev {
+			return api.CommitID(branch.Version), true
+		}
+	}
+	return "", false
+}
+
+// resultCacheKey hashes everything that determines the results of a search.
+//
+// The actor isn't part of the key. The searched repositories are already
+// filtered by what the actor is allowed to see, and access is granted per
+// repository, so the matches inside them are the same for every actor.
+// Actors with access to the same repositories share entries, and actors
+// without access to one of them never search the same set of repositories.
+func resultCacheKey(q query.Q, resultTypes result.Types, limit int, commits []repoCommit) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "query:%s\n", normalizeQuery(q))
+	fmt.Fprintf(h, "types:%s limit:%d\n", resultTypes, limit)
+	for _, c := range commits {
+		fmt.Fprintf(h, "repo:%d@%s=%s\n", c.Repo, c.Rev, c.Commit)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeQuery returns a representation of q which doesn't depend on how
+// the query was written, for example on whitespace.
+func normalizeQuery(q query.Q) string {
+	var b strings.Builder
+	b.WriteString(q.String())
+
+	// Patterns are printed the same way however they are interpreted.
+	query.VisitPattern(q, func(_ string, _ bool, annotation query.Annotation) {
+		switch {
+		case annotation.Labels.IsSet(query.Structural):
+			b.WriteString(" structural")
+		case annotation.Labels.IsSet(query.Regexp):
+			b.WriteString(" regexp")
+		default:
+			b.WriteString(" literal")
+		}
+	})
+	return b.String()
+}
+
+// cachedResults is the value stored in the result cache.
+type cachedResults struct {
+	Matches  []cachedMatch
+	LimitHit bool
+}
+
+// cachedMatch holds a single result.Match, in the one field matching its
+// type. gob can only encode interface values of registered types, so we list
+// the match types instead.
+type cachedMatch struct {
+	File   *result.FileMatch
+	Repo   *result.RepoMatch
+	Commit *result.CommitMatch
+	Owner  *result.OwnerMatch
+}
+
+func toCachedMatch(m result.Match) (cachedMatch, error) {
+	switch v := m.(type) {
+	case *result.FileMatch:
+		return cachedMatch{File: v}, nil
+	case *result.RepoMatch:
+		return cachedMatch{Repo: v}, nil
+	case *result.CommitMatch:
+		return cachedMatch{Commit: v}, nil
+	case *result.OwnerMatch:
+		return cachedMatch{Owner: v}, nil
+	default:
+		return cachedMatch{}, errors.Errorf("unknown match type %T", m)
+	}
+}
+
+func (e *cachedResults) matches() []result.Match {
+	matches := make([]result.Match, 0, len(e.Matches))
+	for _, cm := range e.Matches {
+		switch {
+		case cm.File != nil:
+			// Symbols point to the file they are in, which gob decoded
+			// as a copy.
+			for _, sym := range cm.File.Symbols {
+				sym.File = &cm.File.File
+			}
+			matches = append(matches, cm.File)
+		case cm.Repo != nil:
+			matches = append(matches, cm.Repo)
+		case cm.Commit != nil:
+			matches = append(matches, cm.Commit)
+		case cm.Owner != nil:
+			matches = append(matches, cm.Owner)
+		}
+	}
+	return matches
+}
+
+// ResultRecorder is a streaming.Sender which passes on events to its parent
+// and keeps the results it sends, so that streamed results can be cached.
+type ResultRecorder struct {
+	parent streaming.Sender
+
+	mu      sync.Mutex
+	results []result.Match
+}
+
+func NewResultRecorder(parent streaming.Sender) *ResultRecorder {
+	return &ResultRecorder{parent: parent}
+}
+
+func (r *ResultRecorder) Send(event streaming.SearchEvent) {
+	r.mu.Lock()
+	r.results = append(r.results, event.Results...)
+	r.mu.Unlock()
+
+	r.parent.Send(event)
+}
+
+// Results returns the results sent so far.
+func (r *ResultRecorder) Results() []result.Match {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results
+}
@@ -0,0 +1,160 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/zoekt"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestResultCache_Key(t *testing.T) {
+	indexed := func(id uint32, branches ...zoekt.RepositoryBranch) *zoekt.RepoListEntry {
+		return &zoekt.RepoListEntry{Repository: zoekt.Repository{ID: id, Branches: branches}}
+	}
+	z := &searchbackend.FakeSearcher{Repos: []*zoekt.RepoListEntry{
+		indexed(1, zoekt.RepositoryBranch{Name: "HEAD", Version: "deadbeef"}),
+		indexed(2, zoekt.RepositoryBranch{Name: "HEAD", Version: "f00d"}, zoekt.RepositoryBranch{Name: "v1", Version: "b0ba"}),
+	}}
+
+	c := &ResultCache{maxRepos: 2}
+	repos := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 1, Name: "foo"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+		{Repo: types.RepoName{ID: 2, Name: "bar"}, Revs: []search.RevisionSpecifier{{RevSpec: "v1"}, {RevSpec: "0123456789abcdef0123456789abcdef01234567"}}},
+	}
+
+	key := func(t *testing.T, ctx context.Context, q string, resultTypes result.Types, repos []*search.RepositoryRevisions) string {
+		t.Helper()
+		parsed, err := query.ParseLiteral(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := c.Key(ctx, z, parsed, resultTypes, 30, repos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	alice := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	bob := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	want := key(t, alice, "repo:foo  bar", result.TypeFile, repos)
+	if want == "" {
+		t.Fatal("expected search to be cacheable")
+	}
+
+	if got := key(t, alice, "repo:foo bar", result.TypeFile, repos); got != want {
+		t.Error("expected queries differing in whitespace to have the same key")
+	}
+	if got := key(t, alice, "repo:foo baz", result.TypeFile, repos); got == want {
+		t.Error("expected different queries to have different keys")
+	}
+	if got := key(t, bob, "repo:foo bar", result.TypeFile, repos); got != want {
+		t.Error("expected actors searching the same repositories to share keys")
+	}
+
+	z.Repos[0] = indexed(1, zoekt.RepositoryBranch{Name: "HEAD", Version: "cafebabe"})
+	if got := key(t, alice, "repo:foo bar", result.TypeFile, repos); got == want {
+		t.Error("expected key to change when a new commit is indexed")
+	}
+
+	unindexed := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 3, Name: "baz"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+	}
+	if got := key(t, alice, "repo:baz bar", result.TypeFile, unindexed); got != "" {
+		t.Error("expected searches over unindexed repositories not to be cached")
+	}
+
+	unindexedBranch := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 2, Name: "bar"}, Revs: []search.RevisionSpecifier{{RevSpec: "v2"}}},
+	}
+	if got := key(t, alice, "repo:bar bar", result.TypeFile, unindexedBranch); got != "" {
+		t.Error("expected searches over unindexed branches not to be cached")
+	}
+
+	if got := key(t, alice, "repo:foo bar index:no", result.TypeFile, repos); got != "" {
+		t.Error("expected searches skipping the index not to be cached")
+	}
+
+	if got := key(t, alice, "repo:foo bar", result.TypeFile|result.TypeCommit, repos); got != "" {
+		t.Error("expected commit searches not to be cached")
+	}
+
+	refGlob := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 1, Name: "foo"}, Revs: []search.RevisionSpecifier{{RefGlob: "refs/heads/*"}}},
+	}
+	if got := key(t, alice, "repo:foo bar", result.TypeFile, refGlob); got != "" {
+		t.Error("expected searches over ref globs not to be cached")
+	}
+
+	tooMany := append(repos, &search.RepositoryRevisions{Repo: types.RepoName{ID: 3, Name: "baz"}})
+	if got := key(t, alice, "repo:foo bar", result.TypeFile, tooMany); got != "" {
+		t.Error("expected searches over more than maxRepos repositories not to be cached")
+	}
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	parse := func(q string, searchType query.SearchType) query.Q {
+		t.Helper()
+		parsed, err := query.ParseSearchType(q, searchType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	literal := normalizeQuery(parse("foo.*", query.SearchTypeLiteral))
+	regexp := normalizeQuery(parse("foo.*", query.SearchTypeRegex))
+	if literal == regexp {
+		t.Fatalf("expected literal and regexp patterns to differ, both are %q", literal)
+	}
+}
+
+func TestResultCache_GetSet(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	c := &ResultCache{cache: rcache.NewWithTTL("search-results", 60), maxRepos: 50}
+
+	fm := &result.FileMatch{
+		File: result.File{
+			Repo:     types.RepoName{ID: 1, Name: "foo"},
+			CommitID: "deadbeef",
+			Path:     "main.go",
+		},
+		LineMatches: []*result.LineMatch{{Preview: "func main() {", LineNumber: 3, OffsetAndLengths: [][2]int32{{5, 4}}}},
+	}
+	fm.Symbols = []*result.SymbolMatch{{Symbol: result.Symbol{Name: "main", Line: 4}, File: &fm.File}}
+	matches := []result.Match{
+		fm,
+		&result.RepoMatch{Name: "foo", ID: 1},
+		&result.OwnerMatch{Handle: "@alice", Repo: types.RepoName{ID: 1, Name: "foo"}, FileCount: 2},
+	}
+
+	if _, _, ok := c.Get("key"); ok {
+		t.Fatal("expected cache miss")
+	}
+
+	c.Set("key", matches, true)
+
+	got, limitHit, ok := c.Get("key")
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if !limitHit {
+		t.Error("expected limitHit to be cached")
+	}
+	if diff := cmp.Diff(matches, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if gotFile := got[0].(*result.FileMatch); gotFile.Symbols[0].File != &gotFile.File {
+		t.Error("expected symbols to point to the file they are in")
+	}
+}
@@ -127,6 +127,50 @@ func TestSearchRepositories(t *testing.T) {
 	}
 }
 
+func TestSearchRepositories_NonFilteringFields(t *testing.T) {
+	// The repositories repo:foo resolves to.
+	repositories := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 123, Name: "foo/one"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+		{Repo: types.RepoName{ID: 456, Name: "foo/two"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+	}
+
+	// Fields which don't filter repositories must not drop the repository
+	// results of the search.
+	for _, q := range []string{"repo:foo", "repo:foo explain:yes", "repo:foo at:2021-06-01"} {
+		t.Run(q, func(t *testing.T) {
+			q, err := query.ParseLiteral(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := query.ToBasicQuery(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pattern := search.ToTextPatternInfo(b, search.Batch, query.Identity)
+			matches, _, err := searchRepositoriesBatch(context.Background(), &search.TextParameters{
+				PatternInfo: pattern,
+				Repos:       repositories,
+				Query:       q,
+				Zoekt:       &searchbackend.FakeSearcher{},
+			}, int32(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, res := range matches {
+				got = append(got, string(res.(*result.RepoMatch).Name))
+			}
+			sort.Strings(got)
+
+			want := []string{"foo/one", "foo/two"}
+			if !cmp.Equal(want, got, cmpopts.EquateEmpty()) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
 func searchRepositoriesBatch(ctx context.Context, args *search.TextParameters, limit int32) ([]result.Match, streaming.Stats, error) {
 	return streaming.CollectStream(func(stream streaming.Sender) error {
 		return SearchRepositories(ctx, args, limit, stream)
@@ -0,0 +1,369 @@
+package searchcontexts
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ghodss/yaml"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// SearchContextsFile is the YAML file which declares the search contexts
+// managed by the instance. It is read from the repository configured in the
+// "search.contexts.source" site configuration.
+type SearchContextsFile struct {
+	SearchContexts []*SearchContextDeclaration `json:"searchContexts"`
+}
+
+// SearchContextDeclaration declares a single search context of a
+// SearchContextsFile.
+type SearchContextDeclaration struct {
+	Name string `json:"name"`
+	// Namespace is the name of the user or org which owns the search context.
+	// Search contexts without a namespace are instance-level search contexts.
+	Namespace    string                                `json:"namespace,omitempty"`
+	Description  string                                `json:"description,omitempty"`
+	Public       bool                                  `json:"public"`
+	Query        string                                `json:"query,omitempty"`
+	Repositories []*SearchContextRepositoryDeclaration `json:"repositories,omitempty"`
+}
+
+// SearchContextRepositoryDeclaration declares the revisions of a repository
+// which a search context searches. Without revisions, the default branch is
+// searched.
+type SearchContextRepositoryDeclaration struct {
+	Repository string   `json:"repository"`
+	Revisions  []string `json:"revisions,omitempty"`
+}
+
+// Spec returns the spec of the declared search context.
+func (d *SearchContextDeclaration) Spec() string {
+	if d.Namespace == "" {
+		return d.Name
+	}
+	return searchContextSpecPrefix + d.Namespace + "/" + d.Name
+}
+
+// ParseSearchContextsFile parses the YAML content of a search contexts file.
+func ParseSearchContextsFile(data []byte) (*SearchContextsFile, error) {
+	var file SearchContextsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "parsing search contexts file")
+	}
+	return &file, nil
+}
+
+// validateSearchContextDeclaration validates d with the same rules as search
+// contexts which are created by users.
+func validateSearchContextDeclaration(d *SearchContextDeclaration) error {
+	if d.Name == GlobalSearchContextName && d.Namespace == "" {
+		return errors.New("cannot override global search context")
+	}
+
+	if err := validateSearchContextName(d.Name); err != nil {
+		return err
+	}
+
+	if err := validateSearchContextDescription(d.Description); err != nil {
+		return err
+	}
+
+	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(d.Repositories))
+	for _, r := range d.Repositories {
+		if r.Repository == "" {
+			return errors.New("repository name must not be empty")
+		}
+		repositoryRevisions = append(repositoryRevisions, &types.SearchContextRepositoryRevisions{
+			Repo:      types.RepoName{Name: api.RepoName(r.Repository)},
+			Revisions: r.Revisions,
+		})
+	}
+	if err := validateSearchContextRepositoryRevisions(repositoryRevisions); err != nil {
+		return err
+	}
+
+	return validateSearchContextQuery(d.Query, repositoryRevisions)
+}
+
+// SearchContextsSyncReport describes the result of reconciling the search
+// contexts in the database with a search contexts file.
+type SearchContextsSyncReport struct {
+	// DryRun is true if the changes were only computed and not applied.
+	DryRun bool `json:"dryRun"`
+	// Created, Updated and Deleted contain the specs of the search contexts
+	// which differ from the file. Unless DryRun is true, the differences have
+	// been resolved.
+	Created []string `json:"created,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+	// Errors describes the declarations which could not be applied and any
+	// error which prevented the sync.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// HasDrift reports whether the search contexts in the database differ from
+// the file.
+func (r *SearchContextsSyncReport) HasDrift() bool {
+	return len(r.Created)+len(r.Updated)+len(r.Deleted) > 0
+}
+
+// managedSearchContext is a search context together with its spec and
+// repository revisions. The spec is stored because GetSearchContextSpec
+// cannot compute it for namespaced search contexts which do not exist yet.
+type managedSearchContext struct {
+	spec                string
+	sc                  *types.SearchContext
+	repositoryRevisions []*types.SearchContextRepositoryRevisions
+}
+
+// SyncSearchContextsFile reconciles the managed search contexts in the
+// database with the declarations of file. Declarations which are invalid, or
+// which conflict with a search context that is not managed by the file, are
+// reported as errors and left alone. If dryRun is true, no changes are made
+// and the report only describes the drift between the database and file.
+func SyncSearchContextsFile(ctx context.Context, db dbutil.DB, file *SearchContextsFile, dryRun bool) (*SearchContextsSyncReport, error) {
+	ctx = actor.WithInternalActor(ctx)
+	report := &SearchContextsSyncReport{DryRun: dryRun}
+	store := database.SearchContexts(db)
+
+	existing, err := listManagedSearchContexts(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make([]*managedSearchContext, 0, len(file.SearchContexts))
+	// keep contains the specs of all declarations, valid or not, so that an
+	// invalid declaration does not cause its search context to be deleted.
+	keep := make(map[string]struct{}, len(file.SearchContexts))
+	for _, d := range file.SearchContexts {
+		spec := d.Spec()
+		if _, ok := keep[spec]; ok {
+			report.Errors = append(report.Errors, errors.Errorf("search context %q: declared more than once", spec).Error())
+			continue
+		}
+		keep[spec] = struct{}{}
+
+		sc, err := resolveSearchContextDeclaration(ctx, db, d)
+		if err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "search context %q", spec).Error())
+			continue
+		}
+		declared = append(declared, sc)
+	}
+
+	create, update, remove := planSearchContextsSync(declared, existing, keep)
+
+	for _, sc := range create {
+		spec := sc.spec
+		if err := validateSearchContextDoesNotExist(ctx, db, sc.sc); err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "search context %q is not managed by the search contexts file", spec).Error())
+			continue
+		}
+		report.Created = append(report.Created, spec)
+		if dryRun {
+			continue
+		}
+		if _, err := store.CreateSearchContextWithRepositoryRevisions(ctx, sc.sc, sc.repositoryRevisions); err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "creating search context %q", spec).Error())
+		}
+	}
+
+	for _, sc := range update {
+		spec := sc.spec
+		report.Updated = append(report.Updated, spec)
+		if dryRun {
+			continue
+		}
+		if _, err := store.UpdateSearchContextWithRepositoryRevisions(ctx, sc.sc, sc.repositoryRevisions); err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "updating search context %q", spec).Error())
+		}
+	}
+
+	for _, sc := range remove {
+		spec := GetSearchContextSpec(sc)
+		report.Deleted = append(report.Deleted, spec)
+		if dryRun {
+			continue
+		}
+		if err := store.DeleteSearchContext(ctx, sc.ID); err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "deleting search context %q", spec).Error())
+		}
+	}
+
+	return report, nil
+}
+
+// planSearchContextsSync returns the declared search contexts which have to
+// be created or updated, and the existing managed search contexts which are
+// no longer declared and have to be deleted. Updated search contexts inherit
+// the ID of the existing search context.
+func planSearchContextsSync(declared, existing []*managedSearchContext, keep map[string]struct{}) (create, update []*managedSearchContext, remove []*types.SearchContext) {
+	existingBySpec := make(map[string]*managedSearchContext, len(existing))
+	for _, sc := range existing {
+		existingBySpec[sc.spec] = sc
+	}
+
+	for _, sc := range declared {
+		current, ok := existingBySpec[sc.spec]
+		if !ok {
+			create = append(create, sc)
+			continue
+		}
+		if searchContextsEqual(current, sc) {
+			continue
+		}
+		sc.sc.ID = current.sc.ID
+		update = append(update, sc)
+	}
+
+	for _, sc := range existing {
+		if _, ok := keep[sc.spec]; !ok {
+			remove = append(remove, sc.sc)
+		}
+	}
+	return create, update, remove
+}
+
+func searchContextsEqual(a, b *managedSearchContext) bool {
+	if a.sc.Description != b.sc.Description || a.sc.Public != b.sc.Public || a.sc.Query != b.sc.Query {
+		return false
+	}
+	return reflect.DeepEqual(repositoryRevisionsByName(a.repositoryRevisions), repositoryRevisionsByName(b.repositoryRevisions))
+}
+
+// repositoryRevisionsByName returns the sorted revisions of each repository,
+// which makes repository revisions comparable regardless of their order.
+func repositoryRevisionsByName(repositoryRevisions []*types.SearchContextRepositoryRevisions) map[api.RepoName][]string {
+	byName := make(map[api.RepoName][]string, len(repositoryRevisions))
+	for _, r := range repositoryRevisions {
+		revisions := append([]string{}, r.Revisions...)
+		sort.Strings(revisions)
+		byName[r.Repo.Name] = revisions
+	}
+	return byName
+}
+
+// resolveSearchContextDeclaration validates d and resolves the namespace and
+// repositories it refers to.
+func resolveSearchContextDeclaration(ctx context.Context, db dbutil.DB, d *SearchContextDeclaration) (*managedSearchContext, error) {
+	if err := validateSearchContextDeclaration(d); err != nil {
+		return nil, err
+	}
+
+	sc := &types.SearchContext{
+		Name:        d.Name,
+		Description: d.Description,
+		Public:      d.Public,
+		Query:       d.Query,
+		Managed:     true,
+	}
+	if d.Namespace != "" {
+		namespace, err := database.Namespaces(db).GetByName(ctx, d.Namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "namespace %q", d.Namespace)
+		}
+		sc.NamespaceUserID, sc.NamespaceOrgID = namespace.User, namespace.Organization
+		if namespace.User != 0 {
+			sc.NamespaceUserName = namespace.Name
+		} else {
+			sc.NamespaceOrgName = namespace.Name
+		}
+	}
+
+	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(d.Repositories))
+	for _, r := range d.Repositories {
+		repo, err := database.Repos(db).GetByName(ctx, api.RepoName(r.Repository))
+		if err != nil {
+			return nil, errors.Wrapf(err, "repository %q", r.Repository)
+		}
+		revisions := r.Revisions
+		if len(revisions) == 0 {
+			revisions = []string{"HEAD"}
+		}
+		repositoryRevisions = append(repositoryRevisions, &types.SearchContextRepositoryRevisions{
+			Repo:      types.RepoName{ID: repo.ID, Name: repo.Name},
+			Revisions: revisions,
+		})
+	}
+	return &managedSearchContext{spec: d.Spec(), sc: sc, repositoryRevisions: repositoryRevisions}, nil
+}
+
+func listManagedSearchContexts(ctx context.Context, store *database.SearchContextsStore) ([]*managedSearchContext, error) {
+	searchContexts, err := listAllSearchContexts(ctx, store, database.ListSearchContextsOptions{Managed: true})
+	if err != nil {
+		return nil, err
+	}
+	managed := make([]*managedSearchContext, 0, len(searchContexts))
+	for _, sc := range searchContexts {
+		repositoryRevisions, err := store.GetSearchContextRepositoryRevisions(ctx, sc.ID)
+		if err != nil {
+			return nil, err
+		}
+		managed = append(managed, &managedSearchContext{spec: GetSearchContextSpec(sc), sc: sc, repositoryRevisions: repositoryRevisions})
+	}
+	return managed, nil
+}
+
+func listAllSearchContexts(ctx context.Context, store *database.SearchContextsStore, opts database.ListSearchContextsOptions) ([]*types.SearchContext, error) {
+	const pageSize = 100
+	var all []*types.SearchContext
+	for {
+		page, err := store.ListSearchContexts(ctx, database.ListSearchContextsPageOptions{First: pageSize, After: int32(len(all))}, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// ExportSearchContextsFile returns a search contexts file which declares all
+// search contexts of the instance, so that they can be managed by a file.
+func ExportSearchContextsFile(ctx context.Context, db dbutil.DB) ([]byte, error) {
+	ctx = actor.WithInternalActor(ctx)
+	store := database.SearchContexts(db)
+
+	searchContexts, err := listAllSearchContexts(ctx, store, database.ListSearchContextsOptions{OrderBy: database.SearchContextsOrderBySpec})
+	if err != nil {
+		return nil, err
+	}
+
+	file := &SearchContextsFile{SearchContexts: make([]*SearchContextDeclaration, 0, len(searchContexts))}
+	for _, sc := range searchContexts {
+		d := &SearchContextDeclaration{
+			Name:        sc.Name,
+			Description: sc.Description,
+			Public:      sc.Public,
+			Query:       sc.Query,
+		}
+		if sc.NamespaceUserName != "" {
+			d.Namespace = sc.NamespaceUserName
+		} else {
+			d.Namespace = sc.NamespaceOrgName
+		}
+
+		if sc.Query == "" {
+			repositoryRevisions, err := store.GetSearchContextRepositoryRevisions(ctx, sc.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range repositoryRevisions {
+				d.Repositories = append(d.Repositories, &SearchContextRepositoryDeclaration{
+					Repository: string(r.Repo.Name),
+					Revisions:  r.Revisions,
+				})
+			}
+		}
+		file.SearchContexts = append(file.SearchContexts, d)
+	}
+	return yaml.Marshal(file)
+}
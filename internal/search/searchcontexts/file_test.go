@@ -0,0 +1,193 @@
+package searchcontexts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestParseSearchContextsFile(t *testing.T) {
+	data := []byte(`
+searchContexts:
+  - name: backend
+    description: Backend services
+    public: true
+    repositories:
+      - repository: github.com/example/api
+        revisions: [main, release-1.0]
+      - repository: github.com/example/worker
+  - name: mine
+    namespace: alice
+    query: repo:^github\.com/alice/
+`)
+	file, err := ParseSearchContextsFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &SearchContextsFile{SearchContexts: []*SearchContextDeclaration{
+		{
+			Name:        "backend",
+			Description: "Backend services",
+			Public:      true,
+			Repositories: []*SearchContextRepositoryDeclaration{
+				{Repository: "github.com/example/api", Revisions: []string{"main", "release-1.0"}},
+				{Repository: "github.com/example/worker"},
+			},
+		},
+		{Name: "mine", Namespace: "alice", Query: `repo:^github\.com/alice/`},
+	}}
+	if diff := cmp.Diff(want, file); diff != "" {
+		t.Fatalf("unexpected file (-want +got):\n%s", diff)
+	}
+
+	if got, want := file.SearchContexts[1].Spec(), "@alice/mine"; got != want {
+		t.Fatalf("got spec %q, want %q", got, want)
+	}
+}
+
+func TestValidateSearchContextDeclaration(t *testing.T) {
+	tests := []struct {
+		name        string
+		declaration *SearchContextDeclaration
+		wantErr     bool
+	}{
+		{
+			name:        "valid repositories",
+			declaration: &SearchContextDeclaration{Name: "ctx", Repositories: []*SearchContextRepositoryDeclaration{{Repository: "github.com/example/api"}}},
+		},
+		{
+			name:        "valid query",
+			declaration: &SearchContextDeclaration{Name: "ctx", Query: "repo:example fork:yes"},
+		},
+		{
+			name:        "global",
+			declaration: &SearchContextDeclaration{Name: GlobalSearchContextName},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid name",
+			declaration: &SearchContextDeclaration{Name: "with space"},
+			wantErr:     true,
+		},
+		{
+			name:        "empty repository name",
+			declaration: &SearchContextDeclaration{Name: "ctx", Repositories: []*SearchContextRepositoryDeclaration{{Revisions: []string{"main"}}}},
+			wantErr:     true,
+		},
+		{
+			name: "query and repositories",
+			declaration: &SearchContextDeclaration{
+				Name:         "ctx",
+				Query:        "repo:example",
+				Repositories: []*SearchContextRepositoryDeclaration{{Repository: "github.com/example/api"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "query with pattern",
+			declaration: &SearchContextDeclaration{Name: "ctx", Query: "repo:example foo"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchContextDeclaration(tt.declaration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlanSearchContextsSync(t *testing.T) {
+	repoRevs := func(name string, revisions ...string) []*types.SearchContextRepositoryRevisions {
+		return []*types.SearchContextRepositoryRevisions{{Repo: types.RepoName{Name: api.RepoName("github.com/example/" + name)}, Revisions: revisions}}
+	}
+
+	existing := []*managedSearchContext{
+		{spec: "unchanged", sc: &types.SearchContext{ID: 1, Name: "unchanged", Managed: true}, repositoryRevisions: repoRevs("a", "main", "dev")},
+		{spec: "changed", sc: &types.SearchContext{ID: 2, Name: "changed", Managed: true}, repositoryRevisions: repoRevs("a", "main")},
+		{spec: "removed", sc: &types.SearchContext{ID: 3, Name: "removed", Managed: true}},
+		{spec: "invalid", sc: &types.SearchContext{ID: 4, Name: "invalid", Managed: true}},
+	}
+	declared := []*managedSearchContext{
+		{spec: "unchanged", sc: &types.SearchContext{Name: "unchanged", Managed: true}, repositoryRevisions: repoRevs("a", "dev", "main")},
+		{spec: "changed", sc: &types.SearchContext{Name: "changed", Managed: true}, repositoryRevisions: repoRevs("a", "main", "dev")},
+		{spec: "new", sc: &types.SearchContext{Name: "new", Managed: true}},
+		{spec: "@alice/new", sc: &types.SearchContext{Name: "new", NamespaceUserID: 1, Managed: true}},
+	}
+	keep := map[string]struct{}{"unchanged": {}, "changed": {}, "new": {}, "@alice/new": {}, "invalid": {}}
+
+	create, update, remove := planSearchContextsSync(declared, existing, keep)
+
+	specs := func(searchContexts []*managedSearchContext) []string {
+		var specs []string
+		for _, sc := range searchContexts {
+			specs = append(specs, sc.spec)
+		}
+		return specs
+	}
+	if diff := cmp.Diff([]string{"new", "@alice/new"}, specs(create)); diff != "" {
+		t.Errorf("unexpected created search contexts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"changed"}, specs(update)); diff != "" {
+		t.Errorf("unexpected updated search contexts (-want +got):\n%s", diff)
+	}
+	if len(update) == 1 && update[0].sc.ID != 2 {
+		t.Errorf("updated search context has ID %d, want 2", update[0].sc.ID)
+	}
+	if len(remove) != 1 || remove[0].ID != 3 {
+		t.Errorf("unexpected deleted search contexts %+v", remove)
+	}
+}
+
+func TestSyncSearchContextsFile_RepositoriesToQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	internalCtx := actor.WithInternalActor(context.Background())
+	db := dbtesting.GetDB(t)
+
+	_, err := createRepos(internalCtx, database.Repos(db))
+	require.NoError(t, err)
+
+	sync := func(d *SearchContextDeclaration) *SearchContextsSyncReport {
+		t.Helper()
+		report, err := SyncSearchContextsFile(context.Background(), db, &SearchContextsFile{SearchContexts: []*SearchContextDeclaration{d}}, false)
+		require.NoError(t, err)
+		require.Empty(t, report.Errors)
+		return report
+	}
+
+	report := sync(&SearchContextDeclaration{
+		Name:         "ctx",
+		Repositories: []*SearchContextRepositoryDeclaration{{Repository: "github.com/example/a"}},
+	})
+	require.Equal(t, []string{"ctx"}, report.Created)
+
+	withQuery := &SearchContextDeclaration{Name: "ctx", Query: "repo:^github\\.com/example/b$"}
+	report = sync(withQuery)
+	require.Equal(t, []string{"ctx"}, report.Updated)
+
+	sc, err := database.SearchContexts(db).GetSearchContext(internalCtx, database.GetSearchContextOptions{Name: "ctx"})
+	require.NoError(t, err)
+	repositoryRevisions, err := database.SearchContexts(db).GetSearchContextRepositoryRevisions(internalCtx, sc.ID)
+	require.NoError(t, err)
+	require.Empty(t, repositoryRevisions)
+
+	// The previous repositories must be gone, otherwise every sync reports
+	// the search context as updated.
+	report = sync(withQuery)
+	require.False(t, report.HasDrift(), "unexpected drift %+v", report)
+}
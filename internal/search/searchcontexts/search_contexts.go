@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -22,9 +23,12 @@ const (
 	maxSearchContextNameLength        = 32
 	maxSearchContextDescriptionLength = 1024
 	maxRevisionLength                 = 255
+	maxSearchContextQueryLength       = 1024
 )
 
 var (
+	errManagedSearchContext = errors.New("search context is managed by the search contexts file and cannot be changed")
+
 	validateSearchContextNameRegexp   = lazyregexp.New(`^[a-zA-Z0-9_\-\/\.]+$`)
 	namespacedSearchContextSpecRegexp = lazyregexp.New(searchContextSpecPrefix + `(.*?)\/(.*)`)
 )
@@ -145,6 +149,71 @@ func validateSearchContextRepositoryRevisions(repositoryRevisions []*types.Searc
 	return nil
 }
 
+// searchContextQueryFields are the fields a search context query may contain.
+// They only scope the repositories which are searched.
+var searchContextQueryFields = map[string]struct{}{
+	query.FieldRepo:       {},
+	query.FieldFork:       {},
+	query.FieldArchived:   {},
+	query.FieldVisibility: {},
+}
+
+func validateSearchContextQuery(searchContextQuery string, repositoryRevisions []*types.SearchContextRepositoryRevisions) error {
+	if searchContextQuery == "" {
+		return nil
+	}
+
+	if len(repositoryRevisions) > 0 {
+		return errors.New("search context query and repositories are mutually exclusive")
+	}
+
+	if len(searchContextQuery) > maxSearchContextQueryLength {
+		return errors.Errorf("search context query exceeds maximum allowed length (%d)", maxSearchContextQueryLength)
+	}
+
+	q, err := query.ParseRegexp(searchContextQuery)
+	if err != nil {
+		return err
+	}
+	disjuncts := query.Dnf(q)
+	if len(disjuncts) != 1 {
+		return errors.New("search context query must not contain or expressions")
+	}
+	if err := query.Validate(disjuncts); err != nil {
+		return err
+	}
+
+	// Report the first invalid part of the query.
+	var invalid error
+	fail := func(err error) {
+		if invalid == nil {
+			invalid = err
+		}
+	}
+	query.VisitPattern(q, func(value string, _ bool, _ query.Annotation) {
+		fail(errors.Errorf("search context query must not contain search patterns, got %q", value))
+	})
+	query.VisitParameter(q, func(field, value string, negated bool, annotation query.Annotation) {
+		if _, ok := searchContextQueryFields[field]; !ok {
+			fail(errors.Errorf("search context query must not contain the field %q", field))
+			return
+		}
+		if field != query.FieldRepo {
+			return
+		}
+		if annotation.Labels.IsSet(query.IsPredicate) {
+			if name, _ := query.ParseAsPredicate(value); name != "has.topic" || negated {
+				fail(errors.Errorf("search context query only supports the repo:has.topic() predicate, got %q", value))
+			}
+			return
+		}
+		if strings.Contains(value, "@") {
+			fail(errors.Errorf("search context query must not contain revisions, got %q", value))
+		}
+	})
+	return invalid
+}
+
 func validateSearchContextDoesNotExist(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext) error {
 	_, err := database.SearchContexts(db).GetSearchContext(ctx, database.GetSearchContextOptions{
 		Name:            searchContext.Name,
@@ -186,6 +255,11 @@ func CreateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 		return nil, err
 	}
 
+	err = validateSearchContextQuery(searchContext.Query, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	err = validateSearchContextDoesNotExist(ctx, db, searchContext)
 	if err != nil {
 		return nil, err
@@ -203,6 +277,10 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 		return nil, errors.New("cannot update global search context")
 	}
 
+	if searchContext.Managed {
+		return nil, errManagedSearchContext
+	}
+
 	err := ValidateSearchContextWriteAccessForCurrentUser(ctx, db, searchContext.NamespaceUserID, searchContext.NamespaceOrgID, searchContext.Public)
 	if err != nil {
 		return nil, err
@@ -223,6 +301,11 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 		return nil, err
 	}
 
+	err = validateSearchContextQuery(searchContext.Query, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	searchContext, err = database.SearchContexts(db).UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
@@ -235,6 +318,10 @@ func DeleteSearchContext(ctx context.Context, db dbutil.DB, searchContext *types
 		return errors.New("cannot delete auto-defined search context")
 	}
 
+	if searchContext.Managed {
+		return errManagedSearchContext
+	}
+
 	err := ValidateSearchContextWriteAccessForCurrentUser(ctx, db, searchContext.NamespaceUserID, searchContext.NamespaceOrgID, searchContext.Public)
 	if err != nil {
 		return err
@@ -256,8 +343,16 @@ func GetAutoDefinedSearchContexts(ctx context.Context, db dbutil.DB) ([]*types.S
 	return searchContexts, nil
 }
 
-func GetRepositoryRevisions(ctx context.Context, db dbutil.DB, searchContextID int64) ([]*search.RepositoryRevisions, error) {
-	searchContextRepositoryRevisions, err := database.SearchContexts(db).GetSearchContextRepositoryRevisions(ctx, searchContextID)
+// GetRepositoryRevisions returns the repository revisions searched by
+// searchContext. The repositories of a search context defined by a query are
+// the repositories the query matches at the time of the call, which are
+// searched at their default branch.
+func GetRepositoryRevisions(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext) ([]*search.RepositoryRevisions, error) {
+	if searchContext.Query != "" {
+		return getQueryRepositoryRevisions(ctx, db, searchContext.Query)
+	}
+
+	searchContextRepositoryRevisions, err := database.SearchContexts(db).GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -273,6 +368,76 @@ func GetRepositoryRevisions(ctx context.Context, db dbutil.DB, searchContextID i
 	return repositoryRevisions, nil
 }
 
+func getQueryRepositoryRevisions(ctx context.Context, db dbutil.DB, searchContextQuery string) ([]*search.RepositoryRevisions, error) {
+	options, err := reposListOptionsFromQuery(searchContextQuery)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := database.Repos(db).ListRepoNames(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryRevisions := make([]*search.RepositoryRevisions, 0, len(repos))
+	for _, repo := range repos {
+		repositoryRevisions = append(repositoryRevisions, &search.RepositoryRevisions{Repo: repo, Revs: []search.RevisionSpecifier{{RevSpec: ""}}})
+	}
+	return repositoryRevisions, nil
+}
+
+// reposListOptionsFromQuery returns the options listing the repositories
+// matched by a search context query, which was validated with
+// validateSearchContextQuery. Like in searches, forks and archived
+// repositories are excluded unless the query includes them.
+func reposListOptionsFromQuery(searchContextQuery string) (database.ReposListOptions, error) {
+	q, err := query.ParseRegexp(searchContextQuery)
+	if err != nil {
+		return database.ReposListOptions{}, err
+	}
+
+	var includePatterns, excludePatterns, topics []string
+	query.VisitField(q, query.FieldRepo, func(value string, negated bool, annotation query.Annotation) {
+		if annotation.Labels.IsSet(query.IsPredicate) {
+			_, topic := query.ParseAsPredicate(value)
+			topics = append(topics, topic)
+			return
+		}
+		if negated {
+			excludePatterns = append(excludePatterns, value)
+			return
+		}
+		includePatterns = append(includePatterns, value)
+	})
+
+	fork, archived := query.No, query.No
+	if setFork := q.Fork(); setFork != nil {
+		fork = *setFork
+	}
+	if setArchived := q.Archived(); setArchived != nil {
+		archived = *setArchived
+	}
+
+	visibilityStr, _ := q.StringValue(query.FieldVisibility)
+	visibility := query.ParseVisibility(visibilityStr)
+
+	var excludePattern string
+	if len(excludePatterns) > 0 {
+		excludePattern = "(?:" + strings.Join(excludePatterns, ")|(?:") + ")"
+	}
+
+	return database.ReposListOptions{
+		IncludePatterns: includePatterns,
+		ExcludePattern:  excludePattern,
+		Topics:          topics,
+		NoForks:         fork == query.No,
+		OnlyForks:       fork == query.Only,
+		NoArchived:      archived == query.No,
+		OnlyArchived:    archived == query.Only,
+		NoPrivate:       visibility == query.Public,
+		OnlyPrivate:     visibility == query.Private,
+	}, nil
+}
+
 func IsAutoDefinedSearchContext(searchContext *types.SearchContext) bool {
 	return searchContext.ID == 0
 }
@@ -373,6 +373,32 @@ func TestCreatingSearchContexts(t *testing.T) {
 			},
 			wantErr: fmt.Sprintf("revision %q exceeds maximum allowed length (255)", tooLongRevision),
 		},
+		{
+			name:          "can create search context with query",
+			searchContext: &types.SearchContext{Name: "ctx-query", Query: "repo:^github\\.com/sourcegraph/ repo:has.topic(go) archived:no"},
+			userID:        user1.ID,
+		},
+		{
+			name:          "cannot create search context with query and repositories",
+			searchContext: &types.SearchContext{Name: "ctx", Query: "repo:foo"},
+			userID:        user1.ID,
+			repositoryRevisions: []*types.SearchContextRepositoryRevisions{
+				{Repo: repos[0], Revisions: []string{"HEAD"}},
+			},
+			wantErr: "search context query and repositories are mutually exclusive",
+		},
+		{
+			name:          "cannot create search context with search pattern in query",
+			searchContext: &types.SearchContext{Name: "ctx", Query: "repo:foo bar"},
+			userID:        user1.ID,
+			wantErr:       "search context query must not contain search patterns, got \"bar\"",
+		},
+		{
+			name:          "cannot create search context with non-repo field in query",
+			searchContext: &types.SearchContext{Name: "ctx", Query: "repo:foo file:bar"},
+			userID:        user1.ID,
+			wantErr:       "search context query must not contain the field \"file\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -395,6 +421,41 @@ func TestCreatingSearchContexts(t *testing.T) {
 	}
 }
 
+func TestReposListOptionsFromQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  database.ReposListOptions
+	}{
+		{
+			query: "repo:foo",
+			want:  database.ReposListOptions{IncludePatterns: []string{"foo"}, NoForks: true, NoArchived: true},
+		},
+		{
+			query: "repo:foo -repo:bar -repo:baz fork:yes archived:only",
+			want:  database.ReposListOptions{IncludePatterns: []string{"foo"}, ExcludePattern: "(?:bar)|(?:baz)", OnlyArchived: true},
+		},
+		{
+			query: "repo:has.topic(go) visibility:private fork:only",
+			want:  database.ReposListOptions{Topics: []string{"go"}, OnlyForks: true, NoArchived: true, OnlyPrivate: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if err := validateSearchContextQuery(tt.query, nil); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			got, err := reposListOptionsFromQuery(tt.query)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestUpdatingSearchContexts(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
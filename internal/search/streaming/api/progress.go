@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 )
 
 // BuildProgressEvent builds a progress event from a final results resolver.
@@ -22,6 +24,7 @@ func BuildProgressEvent(stats ProgressStats) Progress {
 		DurationMs:        stats.ElapsedMilliseconds,
 		Skipped:           skipped,
 		Trace:             stats.Trace,
+		Explain:           stats.Explain,
 	}
 }
 
@@ -48,6 +51,8 @@ type ProgressStats struct {
 	Trace string // only filled if requested
 
 	DisplayLimit int
+
+	Explain *explain.Node // only filled if requested with explain:yes
 }
 
 func skippedReposHandler(repos []Namer, titleVerb, messageReason string, base Skipped) (Skipped, bool) {
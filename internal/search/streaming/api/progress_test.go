@@ -6,6 +6,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 )
 
@@ -50,6 +51,23 @@ func TestSearchProgress(t *testing.T) {
 		"traced": {
 			Trace: "abcd",
 		},
+		"explained": {
+			Explain: &explain.Node{
+				Name:       "Plan",
+				Query:      "foo explain:yes",
+				DurationMs: 12,
+				Children: []*explain.Node{{
+					Name:          "Zoekt",
+					DurationMs:    10,
+					Repositories:  2,
+					Shards:        3,
+					SkippedShards: 1,
+					Matches:       1,
+					LimitHit:      true,
+					Children:      []*explain.Node{},
+				}},
+			},
+		},
 	}
 
 	for name, c := range cases {
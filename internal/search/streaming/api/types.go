@@ -1,5 +1,7 @@
 package api
 
+import "github.com/sourcegraph/sourcegraph/internal/search/explain"
+
 // Progress is an aggregate type representing a progress update.
 type Progress struct {
 	// Done is true if this is a final progress event.
@@ -25,6 +27,10 @@ type Progress struct {
 
 	// Trace is the URL of an associated trace if the query is logging one.
 	Trace string `json:"trace,omitempty"`
+
+	// Explain is the tree explaining how the search was evaluated, if the
+	// query contains explain:yes. It is only set on the final progress event.
+	Explain *explain.Node `json:"explain,omitempty"`
 }
 
 // Skipped is a description of shards or documents that were skipped.
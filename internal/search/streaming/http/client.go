@@ -25,6 +25,13 @@ func NewRequest(baseURL string, query string) (*http.Request, error) {
 	return req, nil
 }
 
+// NewExportRequest returns an http.Request against the streaming API which
+// exports all results for query in format. See ExportCSV and ExportJSONL.
+func NewExportRequest(baseURL string, query string, format string) (*http.Request, error) {
+	u := baseURL + "/search/stream?q=" + url.QueryEscape(query) + "&export=" + url.QueryEscape(format)
+	return http.NewRequest("GET", u, nil)
+}
+
 // FrontendStreamDecoder decodes streaming events from the frontend service
 type FrontendStreamDecoder struct {
 	OnProgress func(*api.Progress)
@@ -128,6 +135,8 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case OwnerMatchType:
+		r.EventMatch = &EventOwnerMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
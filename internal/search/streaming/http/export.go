@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Export formats supported by the streaming search endpoint. They are
+// requested with the "export" URL parameter.
+const (
+	ExportCSV   = "csv"
+	ExportJSONL = "jsonl"
+)
+
+// ExportErrorTrailer is the HTTP trailer set when a search fails after rows
+// have been written to an export.
+const ExportErrorTrailer = "X-Sourcegraph-Search-Error"
+
+// ExportRow is a single row of a search result export. A match produces one
+// row per line, symbol or commit it matched.
+type ExportRow struct {
+	// Type is the type of the match, for example "content" or "commit".
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	Revision   string `json:"revision,omitempty"`
+	Path       string `json:"path,omitempty"`
+
+	// LineNumber is 1-indexed. It is 0 for rows which don't refer to a line.
+	LineNumber int32 `json:"lineNumber,omitempty"`
+
+	// Preview is the matched line, the name of a symbol, the subject of a
+	// commit or the handle of an owner.
+	Preview string `json:"preview,omitempty"`
+}
+
+var exportCSVHeader = []string{"type", "repository", "revision", "path", "lineNumber", "preview"}
+
+func (r *ExportRow) csvRecord() []string {
+	var lineNumber string
+	if r.LineNumber > 0 {
+		lineNumber = strconv.Itoa(int(r.LineNumber))
+	}
+	return []string{r.Type, r.Repository, r.Revision, r.Path, lineNumber, r.Preview}
+}
+
+// ExportWriter writes search results as rows of CSV or JSON lines instead of
+// SSE events.
+type ExportWriter struct {
+	w     http.ResponseWriter
+	flush func()
+
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+// IsValidExportFormat returns true if format is a supported export format.
+func IsValidExportFormat(format string) bool {
+	return format == ExportCSV || format == ExportJSONL
+}
+
+func NewExportWriter(w http.ResponseWriter, format string) (*ExportWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("http flushing not supported")
+	}
+
+	e := &ExportWriter{
+		w:     w,
+		flush: flusher.Flush,
+	}
+
+	switch format {
+	case ExportCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		e.csv = csv.NewWriter(w)
+	case ExportJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		e.json = json.NewEncoder(w)
+	default:
+		return nil, errors.Errorf("unsupported export format %q", format)
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="search-results.`+format+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Trailer", ExportErrorTrailer)
+
+	// This informs nginx to not buffer. See NewWriter.
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	if e.csv != nil {
+		if err := e.csv.Write(exportCSVHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	// Send the headers right away so clients know the export started.
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Write writes row. Rows are buffered until Flush is called.
+func (e *ExportWriter) Write(row ExportRow) error {
+	if e.csv != nil {
+		return e.csv.Write(row.csvRecord())
+	}
+	return e.json.Encode(row)
+}
+
+// Flush sends the rows written so far to the client.
+func (e *ExportWriter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	e.flush()
+	return nil
+}
+
+// Error reports err to the client in the ExportErrorTrailer trailer. It must
+// be called after the last row is written.
+func (e *ExportWriter) Error(err error) {
+	_ = e.Flush()
+	e.w.Header().Set(ExportErrorTrailer, strings.ReplaceAll(err.Error(), "\n", " "))
+}
+
+// ReadExportRows reads the rows of a JSON lines export from r.
+func ReadExportRows(r io.Reader) ([]ExportRow, error) {
+	var rows []ExportRow
+	dec := json.NewDecoder(r)
+	for {
+		var row ExportRow
+		if err := dec.Decode(&row); err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
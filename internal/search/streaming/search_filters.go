@@ -154,6 +154,12 @@ func (s *SearchFilters) Update(event SearchEvent) {
 			// We leave "rev" empty, instead of using "CommitMatch.Commit.ID". This way we
 			// get 1 filter per repo instead of 1 filter per sha in the side-bar.
 			addRepoFilter(v.Repo.Name, v.Repo.ID, "", int32(v.ResultCount()))
+		case *result.OwnerMatch:
+			rev := ""
+			if v.InputRev != nil {
+				rev = *v.InputRev
+			}
+			addRepoFilter(v.Repo.Name, v.Repo.ID, rev, 1)
 		}
 	}
 }
@@ -6,6 +6,9 @@ import (
 
 	"go.uber.org/atomic"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
@@ -107,6 +110,34 @@ func WithSelect(parent Sender, s filter.SelectPath) Sender {
 	})
 }
 
+// WithExplain returns a child Stream of parent which records the matches,
+// repositories and limits of each event in node. It returns parent if node
+// is nil, which it is if the search is not explained.
+func WithExplain(parent Sender, node *explain.Node) Sender {
+	if node == nil {
+		return parent
+	}
+
+	return StreamFunc(func(e SearchEvent) {
+		matches := 0
+		for _, match := range e.Results {
+			matches += match.ResultCount()
+			node.AddRepos(match.RepoName().ID)
+		}
+		node.AddMatches(matches)
+		e.Stats.Status.Iterate(func(id api.RepoID, status search.RepoStatus) {
+			node.AddRepos(id)
+			if status&search.RepoStatusTimedout != 0 {
+				node.AddTimedout(id)
+			}
+		})
+		if e.Stats.IsLimitHit {
+			node.SetLimitHit()
+		}
+		parent.Send(e)
+	})
+}
+
 type StreamFunc func(SearchEvent)
 
 func (f StreamFunc) Send(se SearchEvent) {
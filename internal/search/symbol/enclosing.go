@@ -0,0 +1,162 @@
+package symbol
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// maxFileSymbols is the maximum number of symbols we list per file to find
+// the symbols enclosing content matches.
+const maxFileSymbols = 10000
+
+// enclosingKinds are the symbol selector kinds of symbols that contain code,
+// and so can enclose a content match.
+var enclosingKinds = map[string]bool{
+	"module":      true,
+	"namespace":   true,
+	"package":     true,
+	"class":       true,
+	"method":      true,
+	"constructor": true,
+	"enum":        true,
+	"interface":   true,
+	"function":    true,
+	"struct":      true,
+}
+
+// enclosingSymbol returns the symbol in symbols which encloses the 1-indexed
+// line, or nil if there is none. ctags only reports the line a symbol is
+// defined on, so a symbol defined on line itself is preferred. Otherwise it
+// is the closest symbol defined before line which can contain code.
+func enclosingSymbol(symbols []result.Symbol, line int) *result.Symbol {
+	var enclosing *result.Symbol
+	for i := range symbols {
+		s := &symbols[i]
+		switch {
+		case s.Line == line:
+			return s
+		case s.Line > line || !enclosingKinds[s.SelectKind()]:
+			continue
+		case enclosing == nil || s.Line > enclosing.Line:
+			enclosing = s
+		}
+	}
+	return enclosing
+}
+
+type fileKey struct {
+	repo   api.RepoName
+	commit api.CommitID
+	path   string
+}
+
+// enclosingSymbolsResolver adds the symbols enclosing their line matches to
+// file matches. It caches the symbols of each file so that they are only
+// listed once per search.
+type enclosingSymbolsResolver struct {
+	listTags func(context.Context, search.SymbolsParameters) (result.Symbols, error)
+
+	mu    sync.Mutex
+	files map[fileKey][]result.Symbol
+}
+
+func newEnclosingSymbolsResolver() *enclosingSymbolsResolver {
+	return &enclosingSymbolsResolver{
+		listTags: backend.Symbols.ListTags,
+		files:    make(map[fileKey][]result.Symbol),
+	}
+}
+
+// fileSymbols returns the symbols ctags finds in the file of fm, at the
+// commit fm was found at.
+func (e *enclosingSymbolsResolver) fileSymbols(ctx context.Context, fm *result.FileMatch) ([]result.Symbol, error) {
+	key := fileKey{repo: fm.Repo.Name, commit: fm.CommitID, path: fm.Path}
+
+	e.mu.Lock()
+	symbols, ok := e.files[key]
+	e.mu.Unlock()
+	if ok {
+		return symbols, nil
+	}
+
+	all, err := e.listTags(ctx, search.SymbolsParameters{
+		Repo:            fm.Repo.Name,
+		CommitID:        fm.CommitID,
+		IncludePatterns: []string{"^" + regexp.QuoteMeta(fm.Path) + "$"},
+		IsCaseSensitive: true,
+		First:           maxFileSymbols,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range all {
+		if s.Path == fm.Path {
+			symbols = append(symbols, s)
+		}
+	}
+
+	e.mu.Lock()
+	e.files[key] = symbols
+	e.mu.Unlock()
+	return symbols, nil
+}
+
+// addEnclosingSymbols sets the symbols of each content match in matches to
+// the symbols enclosing its line matches. Other matches are left untouched.
+func (e *enclosingSymbolsResolver) addEnclosingSymbols(ctx context.Context, matches []result.Match) error {
+	for _, match := range matches {
+		fm, ok := match.(*result.FileMatch)
+		if !ok || len(fm.LineMatches) == 0 || len(fm.Symbols) > 0 {
+			continue
+		}
+
+		symbols, err := e.fileSymbols(ctx, fm)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[*result.Symbol]bool)
+		for _, lm := range fm.LineMatches {
+			s := enclosingSymbol(symbols, int(lm.LineNumber)+1)
+			if s == nil || seen[s] {
+				continue
+			}
+			seen[s] = true
+			fm.Symbols = append(fm.Symbols, &result.SymbolMatch{
+				Symbol: *s,
+				File:   &fm.File,
+			})
+		}
+	}
+	return nil
+}
+
+// AddEnclosingSymbols sets the symbols of the content matches in matches to
+// the symbols enclosing their line matches, based on the symbols ctags finds
+// at the searched commit. It allows select:symbol to be used with content
+// search.
+func AddEnclosingSymbols(ctx context.Context, matches []result.Match) error {
+	return newEnclosingSymbolsResolver().addEnclosingSymbols(ctx, matches)
+}
+
+// WithEnclosingSymbols returns a child Stream of parent which adds the
+// symbols enclosing the line matches of content matches, like
+// AddEnclosingSymbols.
+func WithEnclosingSymbols(ctx context.Context, parent streaming.Sender) streaming.Sender {
+	resolver := newEnclosingSymbolsResolver()
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		if err := resolver.addEnclosingSymbols(ctx, event.Results); err != nil {
+			log15.Warn("symbol: failed to find enclosing symbols", "error", err)
+		}
+		parent.Send(event)
+	})
+}
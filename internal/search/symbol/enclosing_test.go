@@ -0,0 +1,75 @@
+package symbol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAddEnclosingSymbols(t *testing.T) {
+	symbols := result.Symbols{
+		{Name: "main", Path: "main.go", Line: 1, Kind: "package", Language: "Go"},
+		{Name: "exitCode", Path: "main.go", Line: 3, Kind: "var", Language: "Go"},
+		{Name: "run", Path: "main.go", Line: 5, Kind: "func", Language: "Go"},
+		{Name: "buf", Path: "main.go", Line: 6, Kind: "var", Language: "Go"},
+		{Name: "fail", Path: "main.go", Line: 12, Kind: "func", Language: "Go"},
+		{Name: "other", Path: "other.go", Line: 13, Kind: "func", Language: "Go"},
+	}
+
+	calls := 0
+	resolver := newEnclosingSymbolsResolver()
+	resolver.listTags = func(_ context.Context, args search.SymbolsParameters) (result.Symbols, error) {
+		calls++
+		return symbols, nil
+	}
+
+	file := func(lines ...int32) *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo:     types.RepoName{ID: 1, Name: "foo"},
+				CommitID: "deadbeef",
+				Path:     "main.go",
+			},
+		}
+		for _, line := range lines {
+			// LineNumber is 0-indexed.
+			fm.LineMatches = append(fm.LineMatches, &result.LineMatch{LineNumber: line - 1})
+		}
+		return fm
+	}
+
+	fm1 := file(3, 8, 9, 14)
+	fm2 := file(2)
+	if err := resolver.addEnclosingSymbols(context.Background(), []result.Match{fm1, fm2}); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(fm *result.FileMatch) []string {
+		var names []string
+		for _, s := range fm.Symbols {
+			names = append(names, s.Symbol.Name)
+			if s.File != &fm.File {
+				t.Errorf("expected symbol %s to point to its file match", s.Symbol.Name)
+			}
+		}
+		return names
+	}
+
+	// Line 3 is the line exitCode is defined on. Lines 8 and 9 are in run,
+	// skipping the variable buf, and line 14 is in fail, ignoring the
+	// function in another file.
+	if diff := cmp.Diff([]string{"exitCode", "run", "fail"}, names(fm1)); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"main"}, names(fm2)); diff != "" {
+		t.Error(diff)
+	}
+	if calls != 1 {
+		t.Errorf("expected symbols of main.go to be listed once, got %d calls", calls)
+	}
+}
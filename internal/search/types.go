@@ -46,6 +46,8 @@ type DiffParameters struct {
 type CommitPatternInfo struct {
 	Pattern         string
 	IsRegExp        bool
+	IsStructuralPat bool
+	CombyRule       string
 	IsCaseSensitive bool
 	FileMatchLimit  int32
 
@@ -305,6 +307,15 @@ type RepoOptions struct {
 	Limit             int
 	CacheLookup       bool
 	Query             query.Q
+
+	// Topics and DescriptionPatterns filter repositories by the metadata
+	// of their code host. See repo:has.topic() and repo:has.description().
+	Topics              []string
+	DescriptionPatterns []string
+
+	// At resolves the revisions of each repository to the commit nearest
+	// before this date. See at:.
+	At string
 }
 
 func (op *RepoOptions) String() string {
@@ -328,6 +339,15 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != "" {
 		_, _ = fmt.Fprintf(&b, " CommitAfter=%q", op.CommitAfter)
 	}
+	if len(op.Topics) > 0 {
+		_, _ = fmt.Fprintf(&b, " Topics=%v", op.Topics)
+	}
+	if len(op.DescriptionPatterns) > 0 {
+		_, _ = fmt.Fprintf(&b, " DescriptionPatterns=%v", op.DescriptionPatterns)
+	}
+	if op.At != "" {
+		_, _ = fmt.Fprintf(&b, " At=%q", op.At)
+	}
 
 	if op.NoForks {
 		b.WriteString(" NoForks")
@@ -2,6 +2,10 @@ package unindexed
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
@@ -175,13 +179,255 @@ func runStructuralSearch(ctx context.Context, args *search.SearcherParameters, r
 	return err
 }
 
+// maxFilterFileMatches is the maximum number of files we find for each
+// regexp or negated pattern which narrows down the files structural search
+// runs on.
+const maxFilterFileMatches = 10000
+
+// maxPathsRegexpSize is the maximum size of the regular expressions which
+// restrict structural search to the narrowed down files of a repository.
+// Repositories with more files are searched without them, and their results
+// are filtered instead.
+const maxPathsRegexpSize = 64 * 1024
+
+// NegatedPatternLimitError is returned by structural search if a negated
+// pattern matched more than Max files. Not all files to exclude are known
+// then, so structural search doesn't run.
+type NegatedPatternLimitError struct {
+	Max int
+}
+
+func (e *NegatedPatternLimitError) Error() string {
+	return fmt.Sprintf("negated pattern matched more than %d files", e.Max)
+}
+
+// fileSet is a set of file paths per repository.
+type fileSet map[api.RepoName]map[string]struct{}
+
+func (s fileSet) add(repo api.RepoName, path string) {
+	paths, ok := s[repo]
+	if !ok {
+		paths = make(map[string]struct{})
+		s[repo] = paths
+	}
+	paths[path] = struct{}{}
+}
+
+// intersect returns the paths in both s and other.
+func (s fileSet) intersect(other fileSet) fileSet {
+	intersection := make(fileSet)
+	for repo, paths := range s {
+		for path := range paths {
+			if _, ok := other[repo][path]; ok {
+				intersection.add(repo, path)
+			}
+		}
+	}
+	return intersection
+}
+
+func (s fileSet) contains(repo api.RepoName, path string) bool {
+	_, ok := s[repo][path]
+	return ok
+}
+
+// pathsRegexp returns a regular expression which matches exactly the paths
+// in repo. It returns false if the regular expression would be larger than
+// maxPathsRegexpSize.
+func (s fileSet) pathsRegexp(repo api.RepoName) (string, bool) {
+	paths := make([]string, 0, len(s[repo]))
+	size := 0
+	for path := range s[repo] {
+		quoted := regexp.QuoteMeta(path)
+		size += len(quoted) + 1
+		if size > maxPathsRegexpSize {
+			return "", false
+		}
+		paths = append(paths, quoted)
+	}
+	sort.Strings(paths)
+	return "^(?:" + strings.Join(paths, "|") + ")$", true
+}
+
+// narrowedFiles are the files a structural search runs on, based on the files
+// its regexp and negated patterns match.
+type narrowedFiles struct {
+	// include are the only files to search. It is nil if all files can be
+	// searched.
+	include fileSet
+
+	// exclude are the files not to search.
+	exclude fileSet
+
+	// limitHit is true if a pattern matched more than maxFilterFileMatches
+	// files, so not all files were narrowed down.
+	limitHit bool
+
+	// excludeLimitHit is true if a negated pattern matched more than
+	// maxFilterFileMatches files, so exclude is incomplete.
+	excludeLimitHit bool
+}
+
+// narrowFiles searches for the files matching filters. Indexed repositories
+// are searched with Zoekt, unindexed repositories with searcher. A file is
+// included if it matches every pattern which is not negated, and excluded if
+// it matches a negated pattern.
+func narrowFiles(ctx context.Context, args *search.SearcherParameters, repoFetcher *RepoFetcher, filters []*search.TextPatternInfo) (*narrowedFiles, error) {
+	narrowed := &narrowedFiles{exclude: make(fileSet)}
+	for _, filter := range filters {
+		p := *filter
+		p.IsNegated = false
+		p.FileMatchLimit = maxFilterFileMatches
+		textArgs := *repoFetcher.args
+		textArgs.PatternInfo = &p
+
+		// Missing revisions are reported by the structural search itself.
+		request, err := zoektutil.NewIndexedSearchRequest(ctx, &textArgs, search.TextRequest, func([]*search.RepositoryRevisions) {})
+		if err != nil {
+			return nil, err
+		}
+		searcherArgs := &search.SearcherParameters{
+			SearcherURLs:    args.SearcherURLs,
+			PatternInfo:     &p,
+			UseFullDeadline: args.UseFullDeadline,
+		}
+		fileMatches, stats, err := SearchFilesInReposBatch(ctx, request, searcherArgs, repoFetcher.mode != search.SearcherOnly)
+		if err != nil {
+			return nil, err
+		}
+		narrowed.limitHit = narrowed.limitHit || stats.IsLimitHit
+
+		files := make(fileSet)
+		for _, fm := range fileMatches {
+			files.add(fm.Repo.Name, fm.Path)
+		}
+		switch {
+		case filter.IsNegated:
+			narrowed.excludeLimitHit = narrowed.excludeLimitHit || stats.IsLimitHit
+			for repo, paths := range files {
+				for path := range paths {
+					narrowed.exclude.add(repo, path)
+				}
+			}
+		case narrowed.include == nil:
+			narrowed.include = files
+		default:
+			narrowed.include = narrowed.include.intersect(files)
+		}
+	}
+	return narrowed, nil
+}
+
+// restrict returns a copy of p which only matches the narrowed down files of
+// repo. It returns false if there are no files to search in repo. Paths
+// which don't fit in a regular expression of maxPathsRegexpSize are left to
+// filter.
+func (n *narrowedFiles) restrict(p *search.TextPatternInfo, repo api.RepoName) (*search.TextPatternInfo, bool) {
+	restricted := *p
+	if n.include != nil {
+		if len(n.include[repo]) == 0 {
+			return nil, false
+		}
+		if re, ok := n.include.pathsRegexp(repo); ok {
+			restricted.IncludePatterns = append(append([]string{}, p.IncludePatterns...), re)
+		}
+	}
+	if len(n.exclude[repo]) > 0 {
+		if re, ok := n.exclude.pathsRegexp(repo); ok {
+			excludePatterns := []string{re}
+			if p.ExcludePattern != "" {
+				excludePatterns = append(excludePatterns, p.ExcludePattern)
+			}
+			restricted.ExcludePattern = "(?:" + strings.Join(excludePatterns, ")|(?:") + ")"
+		}
+	}
+	return &restricted, true
+}
+
+// filter returns a stream which only passes on the file matches in the
+// narrowed down files to parent.
+func (n *narrowedFiles) filter(parent streaming.Sender) streaming.Sender {
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		matches := make([]result.Match, 0, len(event.Results))
+		for _, match := range event.Results {
+			if fm, ok := match.(*result.FileMatch); ok {
+				included := n.include == nil || n.include.contains(fm.Repo.Name, fm.Path)
+				if !included || n.exclude.contains(fm.Repo.Name, fm.Path) {
+					continue
+				}
+			}
+			matches = append(matches, match)
+		}
+		event.Results = matches
+		parent.Send(event)
+	})
+}
+
+// runFilteredStructuralSearch runs structural search on the files matching
+// filters. Each repository is searched with include and exclude patterns
+// for the paths of the files found for it.
+func runFilteredStructuralSearch(ctx context.Context, args *search.SearcherParameters, repoFetcher *RepoFetcher, filters []*search.TextPatternInfo, stream streaming.Sender) error {
+	narrowed, err := narrowFiles(ctx, args, repoFetcher, filters)
+	if err != nil {
+		return err
+	}
+	if narrowed.excludeLimitHit {
+		// Searching without all files to exclude would return results in
+		// excluded files.
+		stream.Send(streaming.SearchEvent{Stats: streaming.Stats{IsLimitHit: true}})
+		return &NegatedPatternLimitError{Max: maxFilterFileMatches}
+	}
+	if narrowed.limitHit {
+		log15.Warn("Structural search patterns matched too many files to narrow down. Results may have been missed.", "limit", maxFilterFileMatches)
+		stream.Send(streaming.SearchEvent{Stats: streaming.Stats{IsLimitHit: true}})
+	}
+
+	ctx, stream, cleanup := streaming.WithLimit(ctx, stream, int(args.PatternInfo.FileMatchLimit))
+	defer cleanup()
+	stream = narrowed.filter(stream)
+
+	repos, err := repoFetcher.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	jobs := []*searchRepos{}
+	for _, repoSet := range repos {
+		for _, repoRevs := range repoSet.AsList() {
+			patternInfo, ok := narrowed.restrict(args.PatternInfo, repoRevs.Repo.Name)
+			if !ok {
+				continue
+			}
+			searcherArgs := &search.SearcherParameters{
+				SearcherURLs:    args.SearcherURLs,
+				PatternInfo:     patternInfo,
+				UseFullDeadline: args.UseFullDeadline,
+			}
+
+			var repo repoData = UnindexedList{repoRevs}
+			if repoSet.IsIndexed() {
+				repo = IndexedMap{repoRevs.Repo.ID: repoRevs}
+			}
+			jobs = append(jobs, &searchRepos{args: searcherArgs, stream: stream, repoSet: repo})
+		}
+	}
+	return runJobs(ctx, jobs)
+}
+
 type StructuralSearch struct {
 	RepoFetcher  RepoFetcher
 	Mode         search.GlobalSearchMode
 	SearcherArgs search.SearcherParameters
+
+	// Filters are regexp and negated patterns. Structural search only runs
+	// on the files matching all of them. See query.Basic.SplitStructural.
+	Filters []*search.TextPatternInfo
 }
 
 func (s *StructuralSearch) Run(ctx context.Context, stream streaming.Sender) error {
+	if len(s.Filters) > 0 {
+		return runFilteredStructuralSearch(ctx, &s.SearcherArgs, &s.RepoFetcher, s.Filters, stream)
+	}
 	return runStructuralSearch(ctx, &s.SearcherArgs, &s.RepoFetcher, stream)
 }
 
@@ -0,0 +1,118 @@
+package unindexed
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestNarrowedFiles_Restrict(t *testing.T) {
+	include := make(fileSet)
+	include.add("foo", "a.go")
+	include.add("foo", "b/c.go")
+	exclude := make(fileSet)
+	exclude.add("foo", "a.go")
+	exclude.add("bar", "gen.go")
+
+	p := &search.TextPatternInfo{
+		Pattern:         "foo(:[args])",
+		IsStructuralPat: true,
+		IncludePatterns: []string{`\.go$`},
+		ExcludePattern:  "vendor/",
+	}
+
+	t.Run("include and exclude", func(t *testing.T) {
+		n := &narrowedFiles{include: include, exclude: exclude}
+		got, ok := n.restrict(p, "foo")
+		if !ok {
+			t.Fatal("expected foo to be searched")
+		}
+		if diff := cmp.Diff([]string{`\.go$`, `^(?:a\.go|b/c\.go)$`}, got.IncludePatterns); diff != "" {
+			t.Error(diff)
+		}
+		if want := `(?:^(?:a\.go)$)|(?:vendor/)`; got.ExcludePattern != want {
+			t.Errorf("got exclude pattern %q, want %q", got.ExcludePattern, want)
+		}
+		if len(p.IncludePatterns) != 1 {
+			t.Errorf("restrict modified the include patterns of its argument: %v", p.IncludePatterns)
+		}
+
+		if _, ok := n.restrict(p, "bar"); ok {
+			t.Error("expected bar not to be searched, no files match its patterns")
+		}
+	})
+
+	t.Run("only exclude", func(t *testing.T) {
+		n := &narrowedFiles{exclude: exclude}
+		got, ok := n.restrict(p, "bar")
+		if !ok {
+			t.Fatal("expected bar to be searched")
+		}
+		if diff := cmp.Diff([]string{`\.go$`}, got.IncludePatterns); diff != "" {
+			t.Error(diff)
+		}
+		if want := `(?:^(?:gen\.go)$)|(?:vendor/)`; got.ExcludePattern != want {
+			t.Errorf("got exclude pattern %q, want %q", got.ExcludePattern, want)
+		}
+	})
+}
+
+func TestNarrowedFiles_TooManyPaths(t *testing.T) {
+	// Enough paths that their regular expression is larger than
+	// maxPathsRegexpSize.
+	include := make(fileSet)
+	for i := 0; i < maxPathsRegexpSize/10; i++ {
+		include.add("foo", fmt.Sprintf("dir/%d.go", i))
+	}
+	exclude := make(fileSet)
+	exclude.add("foo", "dir/0.go")
+	n := &narrowedFiles{include: include, exclude: exclude}
+
+	p := &search.TextPatternInfo{Pattern: "foo(:[args])", IsStructuralPat: true}
+	got, ok := n.restrict(p, "foo")
+	if !ok {
+		t.Fatal("expected foo to be searched")
+	}
+	if len(got.IncludePatterns) != 0 {
+		t.Errorf("expected no include patterns, got %d", len(got.IncludePatterns))
+	}
+
+	// The files are filtered after the search instead.
+	var paths []string
+	stream := n.filter(streaming.StreamFunc(func(event streaming.SearchEvent) {
+		for _, match := range event.Results {
+			paths = append(paths, match.(*result.FileMatch).Path)
+		}
+	}))
+	repo := types.RepoName{Name: "foo"}
+	stream.Send(streaming.SearchEvent{Results: []result.Match{
+		&result.FileMatch{File: result.File{Repo: repo, Path: "dir/0.go"}},
+		&result.FileMatch{File: result.File{Repo: repo, Path: "dir/1.go"}},
+		&result.FileMatch{File: result.File{Repo: repo, Path: "other.go"}},
+	}})
+	if diff := cmp.Diff([]string{"dir/1.go"}, paths); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFileSet_Intersect(t *testing.T) {
+	a := make(fileSet)
+	a.add("foo", "a.go")
+	a.add("foo", "b.go")
+	a.add("bar", "a.go")
+	b := make(fileSet)
+	b.add("foo", "b.go")
+	b.add("bar", "b.go")
+
+	want := make(fileSet)
+	want.add("foo", "b.go")
+	if diff := cmp.Diff(want, a.intersect(b)); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/explain"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
@@ -49,14 +50,20 @@ func SearchFilesInRepos(ctx context.Context, zoektArgs zoektutil.IndexedSearchRe
 
 	if notSearcherOnly {
 		// Run literal and regexp searches on indexed repositories.
-		g.Go(func() error {
-			return zoektArgs.Search(ctx, stream)
+		g.Go(func() (err error) {
+			ctx, node := explain.Start(ctx, "Zoekt", "")
+			node.SetRepositories(len(zoektArgs.IndexedRepos()))
+			defer func() { node.Finish(err) }()
+			return zoektArgs.Search(ctx, streaming.WithExplain(stream, node))
 		})
 	}
 
 	// Concurrently run searcher for all unindexed repos regardless whether text or regexp.
-	g.Go(func() error {
-		return callSearcherOverRepos(ctx, searcherArgs, stream, zoektArgs.UnindexedRepos(), false)
+	g.Go(func() (err error) {
+		ctx, node := explain.Start(ctx, "Searcher", "")
+		node.SetRepositories(len(zoektArgs.UnindexedRepos()))
+		defer func() { node.Finish(err) }()
+		return callSearcherOverRepos(ctx, searcherArgs, streaming.WithExplain(stream, node), zoektArgs.UnindexedRepos(), false)
 	})
 
 	return g.Wait()
@@ -16,6 +16,12 @@ import (
 // Client is capable of posting a message to a Slack webhook
 type Client struct {
 	WebhookURL string
+
+	// BotToken and Channel, if set, post messages with the chat.postMessage
+	// Web API method as the Slack app of the bot token instead of posting to
+	// WebhookURL. See PostMessage.
+	BotToken string
+	Channel  string
 }
 
 // New creates a new Slack client
@@ -23,6 +29,15 @@ func New(webhookURL string) *Client {
 	return &Client{WebhookURL: webhookURL}
 }
 
+// NewBot creates a new Slack client which posts to channel as the Slack app
+// of botToken, which needs the chat:write scope.
+func NewBot(botToken, channel string) *Client {
+	return &Client{BotToken: botToken, Channel: channel}
+}
+
+// postMessageURL is the URL of the chat.postMessage Web API method.
+const postMessageURL = "https://slack.com/api/chat.postMessage"
+
 // Payload is the wrapper for a Slack message, defined at:
 // https://api.slack.com/docs/message-formatting
 type Payload struct {
@@ -32,6 +47,10 @@ type Payload struct {
 	UnfurlMedia bool          `json:"unfurl_media,omitempty"`
 	Text        string        `json:"text,omitempty"`
 	Attachments []*Attachment `json:"attachments,omitempty"`
+
+	// Channel and ThreadTS are only used by the Web API, see PostMessage.
+	Channel  string `json:"channel,omitempty"`
+	ThreadTS string `json:"thread_ts,omitempty"`
 }
 
 // Attachment is a Slack message attachment, defined at:
@@ -62,34 +81,76 @@ type Field struct {
 
 // Post sends payload to a Slack channel.
 func (c *Client) Post(ctx context.Context, payload *Payload) error {
-	if c.WebhookURL == "" {
-		return nil
+	_, err := c.PostMessage(ctx, payload)
+	return err
+}
+
+// PostMessage sends payload to a Slack channel and returns the timestamp of
+// the posted message, which can be used as the ThreadTS of later payloads to
+// reply in its thread.
+//
+// Incoming webhooks don't report the timestamp of the messages posted to them,
+// so the timestamp is only returned if the client has a BotToken. Otherwise
+// the empty string is returned.
+func (c *Client) PostMessage(ctx context.Context, payload *Payload) (string, error) {
+	webAPI := c.BotToken != ""
+	u := c.WebhookURL
+	if webAPI {
+		u = postMessageURL
+		if payload.Channel == "" {
+			withChannel := *payload
+			withChannel.Channel = c.Channel
+			payload = &withChannel
+		}
+	}
+	if u == "" {
+		return "", nil
 	}
 
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return errors.Wrap(err, "slack: marshal json")
+		return "", errors.Wrap(err, "slack: marshal json")
 	}
-	req, err := http.NewRequest("POST", c.WebhookURL, bytes.NewReader(payloadJSON))
+	req, err := http.NewRequest("POST", u, bytes.NewReader(payloadJSON))
 	if err != nil {
-		return errors.Wrap(err, "slack: create post request")
+		return "", errors.Wrap(err, "slack: create post request")
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if webAPI {
+		req.Header.Set("Authorization", "Bearer "+c.BotToken)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
 	defer cancel()
 
 	resp, err := http.DefaultClient.Do(req.WithContext(timeoutCtx))
 	if err != nil {
-		return errors.Wrap(err, "slack: http request")
+		return "", errors.Wrap(err, "slack: http request")
 	}
 	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-		return errors.Errorf("slack: %s failed with %d %s", payloadJSON, resp.StatusCode, string(body))
+		return "", errors.Errorf("slack: %s failed with %d %s", payloadJSON, resp.StatusCode, string(body))
+	}
+	if !webAPI {
+		return "", nil
+	}
+
+	// The Web API responds with 200 OK even if posting failed, the outcome is
+	// in the response body.
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", errors.Wrap(err, "slack: unmarshal response")
+	}
+	if !result.OK {
+		return "", errors.Errorf("slack: %s failed with %s", payloadJSON, result.Error)
 	}
-	return nil
+	return result.TS, nil
 }
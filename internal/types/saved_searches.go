@@ -10,4 +10,7 @@ type SavedSearch struct {
 	UserID          *int32  // if non-nil, the owner is this user. UserID/OrgID are mutually exclusive.
 	OrgID           *int32  // if non-nil, the owner is this organization. UserID/OrgID are mutually exclusive.
 	SlackWebhookURL *string // if non-nil && NotifySlack == true, indicates that this Slack webhook URL should be used instead of the owners default Slack webhook.
+	SlackChannel    *string // if non-nil && NotifySlack == true and the site has a Slack bot token, the Slack channel notifications are posted to as the bot instead of SlackWebhookURL.
+	NotifyTeams     bool    // whether or not to notify the owner(s) of this saved search via Microsoft Teams
+	TeamsWebhookURL *string // the Microsoft Teams incoming webhook URL notifications are posted to if NotifyTeams == true
 }
@@ -64,8 +64,21 @@ func (e *ExternalService) RedactConfigSecrets() (string, error) {
 		newCfg, err = redactField(e.Config)
 	case *schema.OtherExternalServiceConnection:
 		newCfg, err = redactField(e.Config, []string{"url"})
+	case *schema.MercurialConnection:
+		newCfg, err = redactField(e.Config, []string{"url"})
 	case *schema.JVMPackagesConnection:
 		newCfg, err = e.Config, nil
+	case *schema.GoModulesConnection:
+		newCfg, err = e.Config, nil
+	case *schema.PythonPackagesConnection:
+		newCfg, err = e.Config, nil
+	case *schema.NpmPackagesConnection:
+		// Credentials are optional for npm registries
+		var fields [][]string
+		if cfg.Credentials != "" {
+			fields = append(fields, []string{"credentials"})
+		}
+		newCfg, err = redactField(e.Config, fields...)
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("RedactExternalServiceConfig: kind %q not implemented", e.Kind)
@@ -145,8 +158,21 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		unredacted, err = unredactField(old.Config, e.Config, &cfg)
 	case *schema.OtherExternalServiceConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"url"}, &cfg.Url})
+	case *schema.MercurialConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"url"}, &cfg.Url})
 	case *schema.JVMPackagesConnection:
 		unredacted, err = e.Config, nil
+	case *schema.GoModulesConnection:
+		unredacted, err = e.Config, nil
+	case *schema.PythonPackagesConnection:
+		unredacted, err = e.Config, nil
+	case *schema.NpmPackagesConnection:
+		// Credentials are optional for npm registries
+		var fields []jsonStringField
+		if cfg.Credentials != "" {
+			fields = append(fields, jsonStringField{[]string{"credentials"}, &cfg.Credentials})
+		}
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("UnRedactExternalServiceConfig: kind %q not implemented", e.Kind)
@@ -463,6 +463,16 @@ type GitserverRepo struct {
 	UpdatedAt   time.Time
 }
 
+// GitserverRepoReplica represents the clone status of a repo on one of the
+// gitservers it is replicated to.
+type GitserverRepoReplica struct {
+	RepoID api.RepoID
+	// The hostname of the gitserver
+	ShardID     string
+	CloneStatus CloneStatus
+	UpdatedAt   time.Time
+}
+
 // ExternalService is a connection to an external service.
 type ExternalService struct {
 	ID              int64
@@ -1195,6 +1205,14 @@ type SearchContext struct {
 	NamespaceUserID int32 // if non-zero, the owner is this user. NamespaceUserID/NamespaceOrgID are mutually exclusive.
 	NamespaceOrgID  int32 // if non-zero, the owner is this organization. NamespaceUserID/NamespaceOrgID are mutually exclusive.
 	UpdatedAt       time.Time
+	// Query is a repo-scoping search query, such as "repo:^github\.com/org/ fork:no".
+	// If set, the search context contains the repositories matched by the query when it is
+	// searched, instead of a static list of repository revisions.
+	Query string
+	// Managed is true if the search context is declared in the search contexts file of the
+	// site configuration. Managed search contexts are kept in sync with the file, so they
+	// cannot be updated or deleted otherwise.
+	Managed bool
 
 	// We cache namespace names to avoid separate database lookups when constructing the search context spec
 
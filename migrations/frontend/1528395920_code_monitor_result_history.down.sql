@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS cm_result_history;
+DROP TABLE IF EXISTS cm_result_fingerprints;
+ALTER TABLE cm_queries DROP COLUMN IF EXISTS fingerprints_updated_at;
+
+COMMIT;
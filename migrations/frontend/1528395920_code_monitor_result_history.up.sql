@@ -0,0 +1,32 @@
+BEGIN;
+
+ALTER TABLE cm_queries ADD COLUMN IF NOT EXISTS fingerprints_updated_at timestamp with time zone;
+COMMENT ON COLUMN cm_queries.fingerprints_updated_at IS 'The last time the fingerprints of the matches of a content search trigger were recorded. NULL until the first run, which only records the matches it finds without triggering actions';
+
+CREATE TABLE IF NOT EXISTS cm_result_fingerprints (
+    query bigint NOT NULL REFERENCES cm_queries(id) ON DELETE CASCADE,
+    fingerprint text NOT NULL,
+    PRIMARY KEY (query, fingerprint)
+);
+COMMENT ON TABLE cm_result_fingerprints IS 'The fingerprints of the matches content search triggers found in their previous run';
+COMMENT ON COLUMN cm_result_fingerprints.fingerprint IS 'A hash of the repository, path and line content of a match';
+
+CREATE TABLE IF NOT EXISTS cm_result_history (
+    id bigserial PRIMARY KEY,
+    monitor bigint NOT NULL REFERENCES cm_monitors(id) ON DELETE CASCADE,
+    trigger_job integer REFERENCES cm_trigger_jobs(id) ON DELETE SET NULL,
+    repository text NOT NULL,
+    url text NOT NULL,
+    commit text,
+    message text,
+    author text,
+    path text,
+    line_number integer,
+    preview text,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS cm_result_history_monitor_id ON cm_result_history (monitor, id);
+COMMENT ON TABLE cm_result_history IS 'The new search results code monitors ran their actions for';
+COMMENT ON COLUMN cm_result_history.url IS 'The URL of the result relative to the external URL';
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE cm_monitors
+    DROP CONSTRAINT IF EXISTS cm_monitors_quiet_hours_check,
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_timezone;
+
+ALTER TABLE cm_emails DROP COLUMN IF EXISTS digest;
+DROP TYPE IF EXISTS cm_email_digest;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TYPE cm_email_digest AS ENUM (
+    'NONE',
+    'HOURLY',
+    'DAILY'
+);
+
+ALTER TABLE cm_emails ADD COLUMN IF NOT EXISTS digest cm_email_digest NOT NULL DEFAULT 'NONE';
+COMMENT ON COLUMN cm_emails.digest IS 'The window over which new search results are collected into one email. NONE sends an email for each trigger event';
+
+ALTER TABLE cm_monitors
+    ADD COLUMN IF NOT EXISTS quiet_hours_start smallint,
+    ADD COLUMN IF NOT EXISTS quiet_hours_end smallint,
+    ADD COLUMN IF NOT EXISTS quiet_hours_timezone text,
+    ADD CONSTRAINT cm_monitors_quiet_hours_check CHECK (
+        num_nulls(quiet_hours_start, quiet_hours_end, quiet_hours_timezone) IN (0, 3)
+        AND quiet_hours_start BETWEEN 0 AND 23
+        AND quiet_hours_end BETWEEN 0 AND 23
+    );
+COMMENT ON COLUMN cm_monitors.quiet_hours_start IS 'The hour of the day quiet hours start at, in quiet_hours_timezone. Actions are delayed until the end of quiet hours';
+COMMENT ON COLUMN cm_monitors.quiet_hours_end IS 'The hour of the day quiet hours end at, in quiet_hours_timezone';
+COMMENT ON COLUMN cm_monitors.quiet_hours_timezone IS 'The IANA time zone of quiet hours, for example Europe/Berlin';
+
+COMMIT;
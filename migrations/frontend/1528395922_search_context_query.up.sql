@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS query text;
+COMMENT ON COLUMN search_contexts.query IS 'Repo-scoping search query defining the repositories of the search context. If set, the search context has no rows in search_context_repos and is evaluated at search time';
+
+COMMIT;
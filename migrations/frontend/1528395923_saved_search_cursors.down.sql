@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS saved_search_cursors;
+
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS notify_teams;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS teams_webhook_url;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS notify_teams boolean NOT NULL DEFAULT false;
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS teams_webhook_url text;
+COMMENT ON COLUMN saved_searches.notify_teams IS 'Whether to notify the owner of the saved search via a Microsoft Teams webhook';
+COMMENT ON COLUMN saved_searches.teams_webhook_url IS 'Microsoft Teams incoming webhook URL that notifications of the saved search are posted to';
+
+CREATE TABLE IF NOT EXISTS saved_search_cursors (
+    saved_search_id integer PRIMARY KEY REFERENCES saved_searches(id) ON DELETE CASCADE,
+    latest_result timestamp with time zone NOT NULL,
+    seen_results text[] NOT NULL DEFAULT '{}'::text[],
+    slack_thread_ts text,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE saved_search_cursors IS 'Position of the query-runner in the results of a saved search, used to notify subscribers of new results only';
+COMMENT ON COLUMN saved_search_cursors.latest_result IS 'Timestamp of the latest result of the saved search that subscribers were notified of';
+COMMENT ON COLUMN saved_search_cursors.seen_results IS 'Keys of the results at latest_result that subscribers were already notified of, since after: filters are inclusive';
+COMMENT ON COLUMN saved_search_cursors.slack_thread_ts IS 'Timestamp of the Slack message that notifications of the saved search are threaded under';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS managed boolean NOT NULL DEFAULT false;
+COMMENT ON COLUMN search_contexts.managed IS 'Whether the search context is declared in the search contexts file of the site configuration. Managed search contexts are kept in sync with the file and cannot be edited otherwise';
+
+COMMIT;
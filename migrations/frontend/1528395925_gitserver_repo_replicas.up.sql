@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gitserver_repo_replicas (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    shard_id text NOT NULL,
+    clone_status text NOT NULL DEFAULT 'not_cloned'::text,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (repo_id, shard_id)
+);
+
+COMMENT ON TABLE gitserver_repo_replicas IS 'Clone status of a repository on each gitserver it is replicated to, when the gitReplicationFactor site configuration is larger than 1';
+COMMENT ON COLUMN gitserver_repo_replicas.shard_id IS 'Hostname of the gitserver the repository is replicated to';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS slack_channel text;
+COMMENT ON COLUMN saved_searches.slack_channel IS 'ID of the Slack channel that notifications of the saved search are posted to as the Slack bot of the site';
+
+COMMIT;